@@ -0,0 +1,117 @@
+package benchstat
+
+import (
+	"fmt"
+	"math"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+const (
+	defaultAlpha           = 0.05
+	defaultConfidenceLevel = 0.95
+)
+
+// ComparisonOptions control Compare.  A difference is reported as significant when its Mann–Whitney p-value is less
+// than Alpha (0.05 if zero).  The variation of each metric is the half-width of the distribution-free confidence
+// interval for its median at ConfidenceLevel (0.95 if zero).
+type ComparisonOptions struct {
+	Alpha           float64
+	ConfidenceLevel float64
+}
+
+// A Summary describes the values of a metric in one collection.  Variation is the larger distance from Median to the
+// bounds of the confidence interval for the median, as a fraction of Median; it is +Inf when there are too few values
+// for a confidence interval (fewer than 6 for a level of 0.95).
+type Summary struct {
+	Count     int
+	Median    float64
+	Variation float64
+}
+
+// A Comparison compares the values of a metric in two collections.  Old or New is nil if the metric is absent from
+// that collection, in which case Delta and PValue are NaN.  Delta is the change from the old median to the new as a
+// fraction of the old median, and PValue is that of a two-sided Mann–Whitney U test of the old and new values.
+// Significant is true when PValue is less than the alpha of the comparison.
+type Comparison struct {
+	Metric      Metric
+	Old         *Summary
+	New         *Summary
+	Delta       float64
+	PValue      float64
+	Significant bool
+}
+
+// Compare compares each metric in old and new, in the order in which the metrics appear in old and then, for those
+// not in old, in new.
+func Compare(old *Collection, new *Collection, options ComparisonOptions) ([]*Comparison, error) {
+	if options.Alpha == 0 {
+		options.Alpha = defaultAlpha
+	}
+
+	if options.ConfidenceLevel == 0 {
+		options.ConfidenceLevel = defaultConfidenceLevel
+	}
+
+	if !(options.Alpha > 0 && options.Alpha < 1) {
+		return nil, fmt.Errorf("alpha must be greater than 0 and less than 1")
+	}
+
+	if !(options.ConfidenceLevel > 0 && options.ConfidenceLevel < 1) {
+		return nil, fmt.Errorf("confidence level must be greater than 0 and less than 1")
+	}
+
+	metrics := append([]Metric{}, old.Metrics...)
+	for _, metric := range new.Metrics {
+		if _, metricIsInOld := old.Sets[metric]; !metricIsInOld {
+			metrics = append(metrics, metric)
+		}
+	}
+
+	comparisons := make([]*Comparison, 0, len(metrics))
+
+	for _, metric := range metrics {
+		oldSet, newSet := old.Sets[metric], new.Sets[metric]
+
+		comparison := &Comparison{
+			Metric: metric,
+			Old:    summaryOf(oldSet, options.ConfidenceLevel),
+			New:    summaryOf(newSet, options.ConfidenceLevel),
+			Delta:  math.NaN(),
+			PValue: math.NaN(),
+		}
+
+		if oldSet != nil && newSet != nil {
+			comparison.Delta = (comparison.New.Median - comparison.Old.Median) / comparison.Old.Median
+
+			// the test fails only when every value is the same, in which case there is no evidence of a difference
+			comparison.PValue = 1
+			if test, err := stats.MannWhitneyUTest(oldSet, newSet, stats.AlternativeTwoSided, options.ConfidenceLevel); err == nil {
+				comparison.PValue = test.PValue
+			}
+
+			comparison.Significant = comparison.PValue < options.Alpha
+		}
+
+		comparisons = append(comparisons, comparison)
+	}
+
+	return comparisons, nil
+}
+
+func summaryOf(set *stats.StatisticalSampleSet, confidenceLevel float64) *Summary {
+	if set == nil {
+		return nil
+	}
+
+	summary := &Summary{Count: set.Count(), Median: set.Median(), Variation: math.Inf(1)}
+
+	if interval, err := set.MedianConfidenceInterval(confidenceLevel); err == nil {
+		summary.Variation = math.Max(summary.Median-interval.Lower, interval.Upper-summary.Median) / math.Abs(summary.Median)
+		if math.IsNaN(summary.Variation) {
+			summary.Variation = 0
+		}
+	}
+
+	return summary
+}
@@ -0,0 +1,163 @@
+package benchstat_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/blorticus-go/statistics/benchstat"
+)
+
+const newBenchmarkOutput = `pkg: example.com/codec
+BenchmarkEncode-8   	 1000000	      1100 ns/op	     256 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1105 ns/op	     256 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1098 ns/op	     256 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1102 ns/op	     256 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1099 ns/op	     256 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1101 ns/op	     256 B/op	       2 allocs/op
+BenchmarkDecode/small-8	  500000	      2004 ns/op	  41.4 MB/s
+BenchmarkDecode/small-8	  500000	      1998 ns/op	  41.6 MB/s
+BenchmarkDecode/small-8	  500000	      2001 ns/op	  41.5 MB/s
+BenchmarkDecode/small-8	  500000	      2009 ns/op	  41.3 MB/s
+BenchmarkDecode/small-8	  500000	      1993 ns/op	  41.7 MB/s
+BenchmarkDecode/small-8	  500000	      2002 ns/op	  41.5 MB/s
+BenchmarkDecode/large-8	    1000	   1500000 ns/op	  41.2 MB/s
+`
+
+func compareTestOutputs(t *testing.T) []*benchstat.Comparison {
+	old, err := benchstat.ParseCollection(strings.NewReader(oldBenchmarkOutput))
+	if err != nil {
+		t.Fatalf("on old output got unexpected error: %s", err)
+	}
+
+	new, err := benchstat.ParseCollection(strings.NewReader(newBenchmarkOutput))
+	if err != nil {
+		t.Fatalf("on new output got unexpected error: %s", err)
+	}
+
+	comparisons, err := benchstat.Compare(old, new, benchstat.ComparisonOptions{})
+	if err != nil {
+		t.Fatalf("on Compare got unexpected error: %s", err)
+	}
+
+	return comparisons
+}
+
+func TestCompare(t *testing.T) {
+	comparisons := compareTestOutputs(t)
+
+	if len(comparisons) != 7 {
+		t.Fatalf("expected (7) comparisons, got (%d)", len(comparisons))
+	}
+
+	encode := comparisons[0]
+	if encode.Metric.Benchmark != "Encode-8" || encode.Metric.Unit != "ns/op" {
+		t.Errorf("expected first comparison to be of Encode-8 ns/op, got %v", encode.Metric)
+	}
+
+	// every new value is less than every old value, so the exact p-value is 2 / choose(12, 6)
+	if !encode.Significant || math.Abs(encode.PValue-2.0/924) > 1e-12 {
+		t.Errorf("on Encode-8 expected significant difference with PValue (%f), got (%t) and (%f)", 2.0/924, encode.Significant, encode.PValue)
+	}
+
+	if expected := (1100.5 - 1201) / 1201; math.Abs(encode.Delta-expected) > 1e-12 {
+		t.Errorf("on Encode-8 expected Delta (%f), got (%f)", expected, encode.Delta)
+	}
+
+	// with six values, the 95% interval for the median is bounded by the smallest and largest values, and the smallest
+	// is farther from the median
+	if expected := (1201 - 1190.0) / 1201; encode.Old.Count != 6 || encode.Old.Median != 1201 || math.Abs(encode.Old.Variation-expected) > 1e-12 {
+		t.Errorf("on Encode-8 expected old Count (6), Median (1201) and Variation (%f), got %+v", expected, *encode.Old)
+	}
+
+	allocations := comparisons[2]
+	if allocations.Significant || allocations.PValue != 1 || allocations.Delta != 0 {
+		t.Errorf("on identical allocs/op expected no significant difference, got %+v", *allocations)
+	}
+
+	large := comparisons[5]
+	if large.Metric.Benchmark != "Decode/large-8" || large.Old != nil || large.New == nil || !math.IsNaN(large.PValue) || large.Significant {
+		t.Errorf("on benchmark only in new output got unexpected comparison %+v", *large)
+	}
+
+	if !math.IsInf(large.New.Variation, 1) {
+		t.Errorf("on benchmark with one value expected infinite Variation, got (%f)", large.New.Variation)
+	}
+
+	if _, err := benchstat.Compare(&benchstat.Collection{}, &benchstat.Collection{}, benchstat.ComparisonOptions{Alpha: 2}); err == nil {
+		t.Errorf("on Alpha (2) expected error, got none")
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var output bytes.Buffer
+	if err := benchstat.WriteText(&output, compareTestOutputs(t)); err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	expected := `name             old ns/op     new ns/op     delta
+Encode-8         1.201k ± 1%   1.101k ± 0%   -8.37% (p=0.002 n=6+6)
+Decode/small-8   2.002k ± 1%   2.002k ± 0%   ~ (p=1.000 n=6+6)
+Decode/large-8                 1.500M ± ∞    
+
+name       old B/op   new B/op   delta
+Encode-8   256 ± 0%   256 ± 0%   ~ (p=1.000 n=6+6)
+
+name       old allocs/op   new allocs/op   delta
+Encode-8   2 ± 0%          2 ± 0%          ~ (p=1.000 n=6+6)
+
+name             old MB/s     new MB/s     delta
+Decode/small-8   41.50 ± 0%   41.50 ± 0%   ~ (p=1.000 n=6+6)
+Decode/large-8                41.20 ± ∞    
+`
+
+	if output.String() != expected {
+		t.Errorf("expected text:\n%s\ngot:\n%s", expected, output.String())
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var output bytes.Buffer
+	if err := benchstat.WriteMarkdown(&output, compareTestOutputs(t)[:2]); err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	expected := `| name | old ns/op | new ns/op | delta |
+|:--|--:|--:|--:|
+| Encode-8 | 1.201k ± 1% | 1.101k ± 0% | -8.37% (p=0.002 n=6+6) |
+
+| name | old B/op | new B/op | delta |
+|:--|--:|--:|--:|
+| Encode-8 | 256 ± 0% | 256 ± 0% | ~ (p=1.000 n=6+6) |
+`
+
+	if output.String() != expected {
+		t.Errorf("expected Markdown:\n%s\ngot:\n%s", expected, output.String())
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var output bytes.Buffer
+	if err := benchstat.WriteCSV(&output, compareTestOutputs(t)); err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	records, err := csv.NewReader(&output).ReadAll()
+	if err != nil {
+		t.Fatalf("on reading CSV got unexpected error: %s", err)
+	}
+
+	if len(records) != 8 || len(records[0]) != 11 || records[0][3] != "old median" {
+		t.Fatalf("expected header and (7) rows of (11) cells, got %v", records)
+	}
+
+	if encode := records[1]; encode[1] != "Encode-8" || encode[3] != "1201" || encode[5] != "6" || encode[6] != "1100.5" || !strings.HasPrefix(encode[9], "-8.368") {
+		t.Errorf("got unexpected row for Encode-8 %v", encode)
+	}
+
+	if large := records[6]; large[3] != "" || large[6] != "1.5e+06" || large[9] != "" || large[10] != "" {
+		t.Errorf("got unexpected row for Decode/large-8 %v", large)
+	}
+}
@@ -0,0 +1,206 @@
+package benchstat
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteText writes comparisons as aligned text, with one table for each unit, in the style of benchstat:
+//
+//	name       old ns/op     new ns/op     delta
+//	Encode-8   1.234k ± 2%   1.103k ± 1%   -10.62% (p=0.008 n=5+5)
+//	Decode-8   2.001k ± 1%   2.010k ± 3%   ~ (p=0.690 n=5+5)
+//
+// A delta is shown only when it is significant, and a variation of ∞ means there are too few values to bound it.
+func WriteText(w io.Writer, comparisons []*Comparison) error {
+	tabularWriter := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+
+	for i, table := range tablesByUnit(comparisons) {
+		if i > 0 {
+			fmt.Fprintln(tabularWriter)
+		}
+
+		fmt.Fprintf(tabularWriter, "name\told %s\tnew %s\tdelta\n", table.unit, table.unit)
+		for _, comparison := range table.comparisons {
+			fmt.Fprintf(tabularWriter, "%s\t%s\t%s\t%s\n", nameOf(comparison, table.qualifyNames), formatSummary(comparison.Old), formatSummary(comparison.New), formatDelta(comparison))
+		}
+	}
+
+	return tabularWriter.Flush()
+}
+
+// WriteMarkdown writes comparisons as Markdown tables, one for each unit, with the same cells as WriteText.
+func WriteMarkdown(w io.Writer, comparisons []*Comparison) error {
+	for i, table := range tablesByUnit(comparisons) {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+
+		if _, err := fmt.Fprintf(w, "| name | old %s | new %s | delta |\n|:--|--:|--:|--:|\n", escapeMarkdown(table.unit), escapeMarkdown(table.unit)); err != nil {
+			return err
+		}
+
+		for _, comparison := range table.comparisons {
+			if _, err := fmt.Fprintf(w, "| %s | %s | %s | %s |\n", escapeMarkdown(nameOf(comparison, table.qualifyNames)), formatSummary(comparison.Old), formatSummary(comparison.New), formatDelta(comparison)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// WriteCSV writes comparisons as CSV with a header row and one row for each comparison.  Numbers are written in
+// full precision, with variations and deltas as percentages; cells for a metric missing from either collection are
+// empty.
+func WriteCSV(w io.Writer, comparisons []*Comparison) error {
+	csvWriter := csv.NewWriter(w)
+
+	if err := csvWriter.Write([]string{"package", "name", "unit", "old median", "old ±%", "old n", "new median", "new ±%", "new n", "delta %", "p-value"}); err != nil {
+		return err
+	}
+
+	for _, comparison := range comparisons {
+		record := []string{comparison.Metric.Package, comparison.Metric.Benchmark, comparison.Metric.Unit}
+		record = append(record, csvCellsOfSummary(comparison.Old)...)
+		record = append(record, csvCellsOfSummary(comparison.New)...)
+		record = append(record, csvCellOfNumber(100*comparison.Delta), csvCellOfNumber(comparison.PValue))
+
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+type tableOfUnit struct {
+	unit         string
+	comparisons  []*Comparison
+	qualifyNames bool
+}
+
+// tablesByUnit groups comparisons by unit, in the order in which each unit first appears.  Names are qualified by
+// their package when the comparisons in a table span more than one package.
+func tablesByUnit(comparisons []*Comparison) []*tableOfUnit {
+	var tables []*tableOfUnit
+	tableOfEachUnit := make(map[string]*tableOfUnit)
+
+	for _, comparison := range comparisons {
+		table, tableExists := tableOfEachUnit[comparison.Metric.Unit]
+		if !tableExists {
+			table = &tableOfUnit{unit: comparison.Metric.Unit}
+			tableOfEachUnit[comparison.Metric.Unit] = table
+			tables = append(tables, table)
+		}
+
+		if len(table.comparisons) > 0 && table.comparisons[0].Metric.Package != comparison.Metric.Package {
+			table.qualifyNames = true
+		}
+
+		table.comparisons = append(table.comparisons, comparison)
+	}
+
+	return tables
+}
+
+func nameOf(comparison *Comparison, qualifyNames bool) string {
+	if qualifyNames && comparison.Metric.Package != "" {
+		return comparison.Metric.Package + "." + comparison.Metric.Benchmark
+	}
+
+	return comparison.Metric.Benchmark
+}
+
+func formatSummary(summary *Summary) string {
+	if summary == nil {
+		return ""
+	}
+
+	if math.IsInf(summary.Variation, 1) {
+		return formatValue(summary.Median) + " ± ∞"
+	}
+
+	return fmt.Sprintf("%s ± %.0f%%", formatValue(summary.Median), 100*summary.Variation)
+}
+
+func formatDelta(comparison *Comparison) string {
+	if comparison.Old == nil || comparison.New == nil {
+		return ""
+	}
+
+	delta := "~"
+	if comparison.Significant {
+		delta = fmt.Sprintf("%+.2f%%", 100*comparison.Delta)
+	}
+
+	return fmt.Sprintf("%s (p=%.3f n=%d+%d)", delta, comparison.PValue, comparison.Old.Count, comparison.New.Count)
+}
+
+var siPrefixes = []struct {
+	scale  float64
+	prefix string
+}{
+	{1e12, "T"},
+	{1e9, "G"},
+	{1e6, "M"},
+	{1e3, "k"},
+}
+
+// formatValue formats value with four significant digits, using an SI prefix for values of 1000 or more.  Integers
+// less than 1000, such as counts of allocations, are formatted without a fraction.
+func formatValue(value float64) string {
+	magnitude := math.Abs(value)
+
+	if magnitude < 1e3 && value == math.Trunc(value) {
+		return strconv.FormatFloat(value, 'f', 0, 64)
+	}
+
+	if magnitude < 1e-3 || math.IsInf(magnitude, 0) || math.IsNaN(magnitude) {
+		return strconv.FormatFloat(value, 'g', 4, 64)
+	}
+
+	prefix := ""
+	for _, si := range siPrefixes {
+		if magnitude >= si.scale {
+			value, magnitude, prefix = value/si.scale, magnitude/si.scale, si.prefix
+			break
+		}
+	}
+
+	decimals := 3 - int(math.Floor(math.Log10(magnitude)))
+	if decimals < 0 {
+		decimals = 0
+	}
+
+	return strconv.FormatFloat(value, 'f', decimals, 64) + prefix
+}
+
+func csvCellsOfSummary(summary *Summary) []string {
+	if summary == nil {
+		return []string{"", "", ""}
+	}
+
+	return []string{csvCellOfNumber(summary.Median), csvCellOfNumber(100 * summary.Variation), strconv.Itoa(summary.Count)}
+}
+
+func csvCellOfNumber(value float64) string {
+	if math.IsNaN(value) {
+		return ""
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func escapeMarkdown(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
@@ -0,0 +1,212 @@
+// Package benchstat compares the results of Go benchmarks, in the text format written by `go test -bench`, in the
+// manner of golang.org/x/perf/cmd/benchstat.
+package benchstat
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+// A Measurement is a single value reported by a benchmark, such as (1234, "ns/op").
+type Measurement struct {
+	Value float64
+	Unit  string
+}
+
+// A Result is one line of benchmark output.  Name omits the "Benchmark" prefix but retains any sub-benchmark path
+// and GOMAXPROCS suffix (e.g., "Encode/small-8").  Config holds the configuration lines (e.g., "goos: linux") in
+// effect when the result was read.
+type Result struct {
+	Name         string
+	Iterations   int
+	Measurements []Measurement
+	Config       map[string]string
+}
+
+// A Metric identifies one measurement of one benchmark across repetitions.  Package is the value of the "pkg"
+// configuration line, if any, so that benchmarks of the same name in different packages are distinct.
+type Metric struct {
+	Package   string
+	Benchmark string
+	Unit      string
+}
+
+// A Collection holds the values of each metric read from benchmark output, such as the output of `go test -bench
+// -count 10`.  Metrics are listed in the order in which they were first read.  Config holds the configuration lines
+// whose value was the same for every result.
+type Collection struct {
+	Results []*Result
+	Metrics []Metric
+	Sets    map[Metric]*stats.StatisticalSampleSet
+	Config  map[string]string
+}
+
+// ParseResults reads benchmark results from r.  Lines that are neither results nor configuration lines (such as
+// "PASS" and test output) are ignored, as are lines consisting only of a benchmark name, which `go test -v` writes
+// before running the benchmark.  An error is returned for a benchmark line that is malformed.
+func ParseResults(r io.Reader) ([]*Result, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	config := make(map[string]string)
+	configHasChanged := true
+	var configOfResults map[string]string
+
+	var results []*Result
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+
+		if key, value, isConfigLine := parseConfigLine(line); isConfigLine {
+			config[key] = value
+			configHasChanged = true
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !isBenchmarkName(fields[0]) {
+			continue
+		}
+
+		result, err := parseResultFields(fields)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNumber, err)
+		}
+
+		if configHasChanged {
+			configOfResults = make(map[string]string, len(config))
+			for key, value := range config {
+				configOfResults[key] = value
+			}
+			configHasChanged = false
+		}
+		result.Config = configOfResults
+
+		results = append(results, result)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ParseCollection reads benchmark results from r using ParseResults and collects the values of each metric into a
+// set.
+func ParseCollection(r io.Reader) (*Collection, error) {
+	results, err := ParseResults(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return CollectionFrom(results)
+}
+
+// CollectionFrom collects the values of each metric in results into a set.
+func CollectionFrom(results []*Result) (*Collection, error) {
+	valuesOfMetrics := make(map[Metric][]float64)
+	collection := &Collection{
+		Results: results,
+		Sets:    make(map[Metric]*stats.StatisticalSampleSet),
+		Config:  make(map[string]string),
+	}
+
+	for i, result := range results {
+		for _, measurement := range result.Measurements {
+			metric := Metric{Package: result.Config["pkg"], Benchmark: result.Name, Unit: measurement.Unit}
+			if _, metricHasBeenSeen := valuesOfMetrics[metric]; !metricHasBeenSeen {
+				collection.Metrics = append(collection.Metrics, metric)
+			}
+			valuesOfMetrics[metric] = append(valuesOfMetrics[metric], measurement.Value)
+		}
+
+		if i == 0 {
+			for key, value := range result.Config {
+				collection.Config[key] = value
+			}
+			continue
+		}
+
+		for key, value := range collection.Config {
+			if valueOfResult, resultHasKey := result.Config[key]; !resultHasKey || valueOfResult != value {
+				delete(collection.Config, key)
+			}
+		}
+	}
+
+	for metric, values := range valuesOfMetrics {
+		set, err := stats.MakeStatisticalSampleSetFrom(values)
+		if err != nil {
+			return nil, fmt.Errorf("benchmark (%s) unit (%s): %s", metric.Benchmark, metric.Unit, err)
+		}
+		collection.Sets[metric] = set
+	}
+
+	return collection, nil
+}
+
+// parseConfigLine recognizes a configuration line, which is a key that begins with a lower case letter and contains
+// no spaces or upper case letters, followed by a colon and either a space or the end of the line.
+func parseConfigLine(line string) (key string, value string, isConfigLine bool) {
+	colon := strings.IndexByte(line, ':')
+	if colon < 1 || (colon+1 < len(line) && line[colon+1] != ' ') {
+		return "", "", false
+	}
+
+	key = line[:colon]
+	firstRune, _ := utf8.DecodeRuneInString(key)
+	if !unicode.IsLower(firstRune) {
+		return "", "", false
+	}
+
+	for _, r := range key {
+		if unicode.IsSpace(r) || unicode.IsUpper(r) {
+			return "", "", false
+		}
+	}
+
+	return key, strings.TrimSpace(line[colon+1:]), true
+}
+
+// isBenchmarkName reports whether field is "Benchmark" followed by nothing or by a character that is not a lower
+// case letter, so that, e.g., "Benchmarking" is not a benchmark name.
+func isBenchmarkName(field string) bool {
+	if !strings.HasPrefix(field, "Benchmark") {
+		return false
+	}
+
+	next, _ := utf8.DecodeRuneInString(field[len("Benchmark"):])
+
+	return next == utf8.RuneError || !unicode.IsLower(next)
+}
+
+func parseResultFields(fields []string) (*Result, error) {
+	iterations, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid iteration count (%s)", fields[1])
+	}
+
+	if len(fields)%2 != 0 {
+		return nil, fmt.Errorf("missing unit after value (%s)", fields[len(fields)-1])
+	}
+
+	result := &Result{Name: strings.TrimPrefix(fields[0], "Benchmark"), Iterations: iterations}
+
+	for i := 2; i < len(fields); i += 2 {
+		value, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value (%s)", fields[i])
+		}
+		result.Measurements = append(result.Measurements, Measurement{Value: value, Unit: fields[i+1]})
+	}
+
+	return result, nil
+}
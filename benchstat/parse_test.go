@@ -0,0 +1,130 @@
+package benchstat_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blorticus-go/statistics/benchstat"
+)
+
+const oldBenchmarkOutput = `goos: linux
+goarch: amd64
+pkg: example.com/codec
+cpu: Example CPU @ 2.00GHz
+BenchmarkEncode-8   	 1000000	      1200 ns/op	     256 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1210 ns/op	     256 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1190 ns/op	     256 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1205 ns/op	     256 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1195 ns/op	     256 B/op	       2 allocs/op
+BenchmarkEncode-8   	 1000000	      1202 ns/op	     256 B/op	       2 allocs/op
+BenchmarkDecode/small-8	  500000	      2000 ns/op	  41.5 MB/s
+BenchmarkDecode/small-8	  500000	      2010 ns/op	  41.3 MB/s
+BenchmarkDecode/small-8	  500000	      1990 ns/op	  41.7 MB/s
+BenchmarkDecode/small-8	  500000	      2005 ns/op	  41.4 MB/s
+BenchmarkDecode/small-8	  500000	      1995 ns/op	  41.6 MB/s
+BenchmarkDecode/small-8	  500000	      2003 ns/op	  41.5 MB/s
+PASS
+ok  	example.com/codec	12.345s
+`
+
+func TestParseResults(t *testing.T) {
+	input := `goos: linux
+pkg: example.com/a
+BenchmarkFoo
+BenchmarkFoo-4   	     100	     12.5 ns/op	   3 widgets/op
+some test output: with a colon
+Benchmarking is not a benchmark 1 2
+pkg: example.com/b
+BenchmarkFoo-4   	     200	     13 ns/op
+PASS
+`
+
+	results, err := benchstat.ParseResults(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected (2) results, got (%d)", len(results))
+	}
+
+	first, second := results[0], results[1]
+
+	if first.Name != "Foo-4" || first.Iterations != 100 || len(first.Measurements) != 2 {
+		t.Errorf("on first result expected Name (Foo-4), Iterations (100) and (2) measurements, got (%s), (%d) and (%d)", first.Name, first.Iterations, len(first.Measurements))
+	} else if first.Measurements[0] != (benchstat.Measurement{Value: 12.5, Unit: "ns/op"}) || first.Measurements[1] != (benchstat.Measurement{Value: 3, Unit: "widgets/op"}) {
+		t.Errorf("on first result got unexpected measurements %v", first.Measurements)
+	}
+
+	if first.Config["goos"] != "linux" || first.Config["pkg"] != "example.com/a" || len(first.Config) != 2 {
+		t.Errorf("on first result got unexpected Config %v", first.Config)
+	}
+
+	if second.Config["pkg"] != "example.com/b" || second.Config["goos"] != "linux" {
+		t.Errorf("on second result got unexpected Config %v", second.Config)
+	}
+
+	collection, err := benchstat.CollectionFrom(results)
+	if err != nil {
+		t.Fatalf("on CollectionFrom got unexpected error: %s", err)
+	}
+
+	if len(collection.Metrics) != 3 {
+		t.Errorf("expected metrics for each package and unit, got %v", collection.Metrics)
+	}
+
+	if collection.Config["goos"] != "linux" || len(collection.Config) != 1 {
+		t.Errorf("expected only the config common to every result, got %v", collection.Config)
+	}
+}
+
+func TestParseCollectionWithRepetitions(t *testing.T) {
+	collection, err := benchstat.ParseCollection(strings.NewReader(oldBenchmarkOutput))
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	expectedMetrics := []benchstat.Metric{
+		{"example.com/codec", "Encode-8", "ns/op"},
+		{"example.com/codec", "Encode-8", "B/op"},
+		{"example.com/codec", "Encode-8", "allocs/op"},
+		{"example.com/codec", "Decode/small-8", "ns/op"},
+		{"example.com/codec", "Decode/small-8", "MB/s"},
+	}
+
+	if len(collection.Metrics) != len(expectedMetrics) {
+		t.Fatalf("expected metrics %v, got %v", expectedMetrics, collection.Metrics)
+	}
+
+	for i, metric := range expectedMetrics {
+		if collection.Metrics[i] != metric {
+			t.Errorf("on metric (%d) expected %v, got %v", i, metric, collection.Metrics[i])
+		}
+
+		if count := collection.Sets[metric].Count(); count != 6 {
+			t.Errorf("on metric %v expected (6) values, got (%d)", metric, count)
+		}
+	}
+
+	if median := collection.Sets[expectedMetrics[0]].Median(); median != 1201 {
+		t.Errorf("expected median ns/op of Encode-8 (1201), got (%f)", median)
+	}
+
+	if collection.Config["cpu"] != "Example CPU @ 2.00GHz" {
+		t.Errorf("expected cpu config (Example CPU @ 2.00GHz), got (%s)", collection.Config["cpu"])
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for testIndex, input := range []string{
+		"BenchmarkFoo-8 lots 12 ns/op\n",
+		"BenchmarkFoo-8 100 12 ns/op 7\n",
+		"BenchmarkFoo-8 100 twelve ns/op\n",
+	} {
+		if _, err := benchstat.ParseResults(strings.NewReader(input)); err == nil {
+			t.Errorf("on test with index (%d) expected error, got none", testIndex)
+		} else if !strings.HasPrefix(err.Error(), "line 1: ") {
+			t.Errorf("on test with index (%d) expected error to name the line, got (%s)", testIndex, err)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+const (
+	defaultNumberOfBootstrapResamples = 2000
+	defaultBootstrapConfidenceLevel   = 0.95
+)
+
+// Work that draws random numbers is divided into blocks of this many items, each with its own random number
+// generator, so that the results do not depend on how many goroutines process the blocks.
+const itemsPerRandomBlock = 64
+
+// A SetStatistic computes a statistic (e.g., the median) of a set.
+type SetStatistic func(set *StatisticalSampleSet) float64
+
+// BootstrapOptions control Bootstrap.  Zero values select the defaults: 2000 resamples, a confidence level of 0.95,
+// and a single goroutine.  Results depend only on Seed, not on Parallelism.
+//
+// The studentized interval requires the standard error of the statistic for each resample.  If StandardError is
+// provided, it is used; otherwise, if NumberOfInnerResamples is greater than 0, the standard error of each resample
+// is estimated by bootstrapping that resample, which multiplies the cost by NumberOfInnerResamples.  If neither is
+// set, no studentized interval is computed.
+type BootstrapOptions struct {
+	NumberOfResamples      int
+	Seed                   int64
+	Parallelism            int
+	ConfidenceLevel        float64
+	StandardError          SetStatistic
+	NumberOfInnerResamples int
+}
+
+// A BootstrapResult is the result of Bootstrap.  Estimate is the statistic computed on the original set, Replicates
+// are the statistic computed on each resample (in the order in which the resamples were drawn), StandardError is the
+// sample standard deviation of the replicates, and Bias is the mean of the replicates minus Estimate.  BCa is nil
+// when it cannot be computed: when every replicate lies on the same side of Estimate, when the set has only one
+// value, or when the statistic is NaN for a set with one value removed.  Studentized is nil unless requested in the BootstrapOptions.
+type BootstrapResult struct {
+	Estimate      float64
+	Replicates    []float64
+	StandardError float64
+	Bias          float64
+	Percentile    *Interval
+	Basic         *Interval
+	BCa           *Interval
+	Studentized   *Interval
+}
+
+// Bootstrap estimates the sampling distribution of statistic by computing it on sets resampled with replacement from
+// set, each of the same size as set.  The statistic must not modify the set passed to it, and must be safe to call
+// from concurrent goroutines when options.Parallelism is greater than 1.  An error is returned if the statistic is NaN
+// for the set or for any resample, or if ctx is cancelled before all resamples have been computed.
+//
+// The BCa interval estimates its acceleration from the jackknife replicates of the statistic (see Jackknife), which
+// requires computing the statistic once for each distinct value in the set.
+func Bootstrap(ctx context.Context, set *StatisticalSampleSet, statistic SetStatistic, options BootstrapOptions) (*BootstrapResult, error) {
+	if statistic == nil {
+		return nil, fmt.Errorf("statistic must not be nil")
+	}
+
+	if options.NumberOfResamples < 0 || options.NumberOfInnerResamples < 0 {
+		return nil, fmt.Errorf("number of resamples must not be negative")
+	}
+
+	if options.NumberOfResamples == 0 {
+		options.NumberOfResamples = defaultNumberOfBootstrapResamples
+	}
+
+	if options.ConfidenceLevel == 0 {
+		options.ConfidenceLevel = defaultBootstrapConfidenceLevel
+	}
+
+	if err := errorIfConfidenceLevelIsNotValid(options.ConfidenceLevel); err != nil {
+		return nil, err
+	}
+
+	estimate := statistic(set)
+	if math.IsNaN(estimate) {
+		return nil, fmt.Errorf("statistic is NaN for the set")
+	}
+
+	sortedValues := set.snapshotOfSortedValues()
+	studentized := options.StandardError != nil || options.NumberOfInnerResamples > 0
+
+	replicates := make([]float64, options.NumberOfResamples)
+	standardErrorsOfReplicates := make([]float64, options.NumberOfResamples)
+
+	err := forEachItemInRandomBlocks(ctx, options.NumberOfResamples, options.Parallelism, options.Seed, func(rng *rand.Rand, index int) error {
+		resampledValues := resampleOfSortedValues(rng, sortedValues)
+		resample, err := MakeStatisticalSampleSetFrom(resampledValues)
+		if err != nil {
+			return err
+		}
+
+		replicates[index] = statistic(resample)
+		if math.IsNaN(replicates[index]) {
+			return fmt.Errorf("statistic is NaN for resample (%d)", index)
+		}
+
+		switch {
+		case options.StandardError != nil:
+			standardErrorsOfReplicates[index] = options.StandardError(resample)
+		case studentized:
+			standardErrorsOfReplicates[index], err = bootstrapStandardError(rng, resampledValues, statistic, options.NumberOfInnerResamples)
+		}
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return computeBootstrapResult(set, statistic, estimate, replicates, standardErrorsOfReplicates, studentized, options)
+}
+
+func computeBootstrapResult(set *StatisticalSampleSet, statistic SetStatistic, estimate float64, replicates []float64, standardErrorsOfReplicates []float64, studentized bool, options BootstrapOptions) (*BootstrapResult, error) {
+	distribution, err := MakeStatisticalSampleSetFrom(replicates)
+	if err != nil {
+		return nil, err
+	}
+
+	sortedReplicates := distribution.snapshotOfSortedValues()
+	level := options.ConfidenceLevel
+	alpha := 1 - level
+
+	result := &BootstrapResult{
+		Estimate:      estimate,
+		Replicates:    replicates,
+		StandardError: distribution.SampleStdev(),
+		Bias:          distribution.Mean() - estimate,
+	}
+
+	lowerQuantile := quantileOfSortedValues(sortedReplicates, alpha/2, QuantileDefault)
+	upperQuantile := quantileOfSortedValues(sortedReplicates, 1-alpha/2, QuantileDefault)
+
+	result.Percentile = &Interval{Lower: lowerQuantile, Upper: upperQuantile, Level: level, Method: IntervalBootstrapPercentile}
+	result.Basic = &Interval{Lower: 2*estimate - upperQuantile, Upper: 2*estimate - lowerQuantile, Level: level, Method: IntervalBootstrapBasic}
+	result.BCa = bcaInterval(set, statistic, estimate, sortedReplicates, level)
+
+	if studentized {
+		standardError := result.StandardError
+		if options.StandardError != nil {
+			standardError = options.StandardError(set)
+		}
+		result.Studentized = studentizedInterval(estimate, standardError, replicates, standardErrorsOfReplicates, level)
+	}
+
+	return result, nil
+}
+
+// bcaInterval returns the bias-corrected and accelerated interval (Efron, "Better Bootstrap Confidence Intervals",
+// 1987), or nil if it cannot be computed.  The bias correction counts replicates equal to the estimate as half below
+// it, which matters for statistics, such as the median, that take few distinct values.
+func bcaInterval(set *StatisticalSampleSet, statistic SetStatistic, estimate float64, sortedReplicates []float64, level float64) *Interval {
+	below := sort.SearchFloat64s(sortedReplicates, estimate)
+	equal := sort.SearchFloat64s(sortedReplicates, math.Nextafter(estimate, math.Inf(1))) - below
+
+	biasCorrection := standardNormalQuantile((float64(below) + float64(equal)/2) / float64(len(sortedReplicates)))
+	if math.IsInf(biasCorrection, 0) {
+		return nil
+	}
+
+	leaveOneOutValues, multiplicities := leaveOneOutReplicatesOfDistinctValues(set, statistic)
+	if leaveOneOutValues == nil {
+		return nil
+	}
+
+	acceleration := jackknifeAcceleration(leaveOneOutValues, multiplicities)
+	if math.IsNaN(acceleration) {
+		return nil
+	}
+
+	adjustedProbability := func(p float64) float64 {
+		z := biasCorrection + standardNormalQuantile(p)
+		return standardNormalCDF(biasCorrection + z/(1-acceleration*z))
+	}
+
+	alpha := 1 - level
+
+	return &Interval{
+		Lower:  quantileOfSortedValues(sortedReplicates, adjustedProbability(alpha/2), QuantileDefault),
+		Upper:  quantileOfSortedValues(sortedReplicates, adjustedProbability(1-alpha/2), QuantileDefault),
+		Level:  level,
+		Method: IntervalBootstrapBCa,
+	}
+}
+
+// jackknifeAcceleration returns the skewness-based acceleration constant from the leave-one-out replicates, each
+// counted multiplicities[i] times.
+func jackknifeAcceleration(leaveOneOutValues []float64, multiplicities []int) float64 {
+	n := 0
+	sum := float64(0)
+	for i, value := range leaveOneOutValues {
+		n += multiplicities[i]
+		sum += float64(multiplicities[i]) * value
+	}
+	mean := sum / float64(n)
+
+	sumOfSquares, sumOfCubes := float64(0), float64(0)
+	for i, value := range leaveOneOutValues {
+		deviation := mean - value
+		sumOfSquares += float64(multiplicities[i]) * deviation * deviation
+		sumOfCubes += float64(multiplicities[i]) * deviation * deviation * deviation
+	}
+
+	if sumOfSquares == 0 {
+		return 0
+	}
+
+	return sumOfCubes / (6 * math.Pow(sumOfSquares, 1.5))
+}
+
+// studentizedInterval returns the bootstrap-t interval, or nil if no replicate has a positive standard error.
+// Replicates whose standard error is not positive are excluded.
+func studentizedInterval(estimate float64, standardError float64, replicates []float64, standardErrorsOfReplicates []float64, level float64) *Interval {
+	studentizedReplicates := make([]float64, 0, len(replicates))
+	for i, replicate := range replicates {
+		if standardErrorsOfReplicates[i] > 0 {
+			studentizedReplicates = append(studentizedReplicates, (replicate-estimate)/standardErrorsOfReplicates[i])
+		}
+	}
+
+	if len(studentizedReplicates) == 0 || math.IsNaN(standardError) {
+		return nil
+	}
+
+	sort.Float64s(studentizedReplicates)
+	alpha := 1 - level
+
+	return &Interval{
+		Lower:  estimate - quantileOfSortedValues(studentizedReplicates, 1-alpha/2, QuantileDefault)*standardError,
+		Upper:  estimate - quantileOfSortedValues(studentizedReplicates, alpha/2, QuantileDefault)*standardError,
+		Level:  level,
+		Method: IntervalBootstrapStudentized,
+	}
+}
+
+// bootstrapStandardError returns the standard deviation of statistic over numberOfResamples resamples of
+// sortedValues.
+func bootstrapStandardError(rng *rand.Rand, sortedValues []float64, statistic SetStatistic, numberOfResamples int) (float64, error) {
+	replicates := make([]float64, numberOfResamples)
+	for i := range replicates {
+		resample, err := MakeStatisticalSampleSetFrom(resampleOfSortedValues(rng, sortedValues))
+		if err != nil {
+			return 0, err
+		}
+		replicates[i] = statistic(resample)
+	}
+
+	if numberOfResamples < 2 {
+		return math.NaN(), nil
+	}
+
+	distribution, err := MakeStatisticalSampleSetFrom(replicates)
+	if err != nil {
+		return 0, err
+	}
+
+	return distribution.SampleStdev(), nil
+}
+
+// resampleOfSortedValues returns len(sortedValues) values drawn with replacement from sortedValues, in ascending
+// order, so that making a set from them does not require sorting.
+func resampleOfSortedValues(rng *rand.Rand, sortedValues []float64) []float64 {
+	n := len(sortedValues)
+
+	timesDrawn := make([]int, n)
+	for i := 0; i < n; i++ {
+		timesDrawn[rng.Intn(n)]++
+	}
+
+	resample := make([]float64, 0, n)
+	for i, count := range timesDrawn {
+		for ; count > 0; count-- {
+			resample = append(resample, sortedValues[i])
+		}
+	}
+
+	return resample
+}
+
+// forEachItemInRandomBlocks calls work for each index in [0, numberOfItems) using up to parallelism goroutines.  The
+// indices are divided into blocks of itemsPerRandomBlock, and the items in a block are processed in order using a
+// random number generator seeded for that block from seed, so the random numbers seen by each item do not depend on
+// parallelism.  The first error returned by work, or the error of ctx once it is cancelled, stops the remaining work
+// and is returned.
+func forEachItemInRandomBlocks(ctx context.Context, numberOfItems int, parallelism int, seed int64, work func(rng *rand.Rand, index int) error) error {
+	numberOfBlocks := (numberOfItems + itemsPerRandomBlock - 1) / itemsPerRandomBlock
+
+	seedGenerator := rand.New(rand.NewSource(seed))
+	blocks := make(chan int, numberOfBlocks)
+	seedsOfBlocks := make([]int64, numberOfBlocks)
+	for i := range seedsOfBlocks {
+		seedsOfBlocks[i] = seedGenerator.Int63()
+		blocks <- i
+	}
+	close(blocks)
+
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	if parallelism > numberOfBlocks {
+		parallelism = numberOfBlocks
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var firstError error
+	var errorMutex sync.Mutex
+	var waitGroup sync.WaitGroup
+
+	for worker := 0; worker < parallelism; worker++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+
+			for block := range blocks {
+				rng := rand.New(rand.NewSource(seedsOfBlocks[block]))
+				first := block * itemsPerRandomBlock
+				last := first + itemsPerRandomBlock
+				if last > numberOfItems {
+					last = numberOfItems
+				}
+
+				for index := first; index < last; index++ {
+					err := ctx.Err()
+					if err == nil {
+						err = work(rng, index)
+					}
+
+					if err != nil {
+						errorMutex.Lock()
+						if firstError == nil {
+							firstError = err
+						}
+						errorMutex.Unlock()
+						cancel()
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	waitGroup.Wait()
+
+	return firstError
+}
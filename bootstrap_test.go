@@ -0,0 +1,192 @@
+package stats_test
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+func meanOfSet(set *stats.StatisticalSampleSet) float64 {
+	return set.Mean()
+}
+
+func standardErrorOfMeanOfSet(set *stats.StatisticalSampleSet) float64 {
+	return set.SampleStdev() / math.Sqrt(float64(set.Count()))
+}
+
+func TestBootstrapOfMeanAgreesWithNormalTheory(t *testing.T) {
+	normal, _ := stats.NewNormalDistribution(10, 2)
+	set, _ := stats.MakeStatisticalSampleSetFromDistribution(normal, 200, rand.New(rand.NewSource(5)))
+
+	result, err := stats.Bootstrap(context.Background(), set, meanOfSet, stats.BootstrapOptions{
+		NumberOfResamples: 4000,
+		Seed:              11,
+		Parallelism:       4,
+		StandardError:     standardErrorOfMeanOfSet,
+	})
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	if result.Estimate != set.Mean() {
+		t.Errorf("expected Estimate (%f), got (%f)", set.Mean(), result.Estimate)
+	}
+
+	if len(result.Replicates) != 4000 {
+		t.Errorf("expected (4000) replicates, got (%d)", len(result.Replicates))
+	}
+
+	expectedStandardError := standardErrorOfMeanOfSet(set)
+	if math.Abs(result.StandardError-expectedStandardError) > 0.1*expectedStandardError {
+		t.Errorf("expected StandardError near (%f), got (%f)", expectedStandardError, result.StandardError)
+	}
+
+	if math.Abs(result.Bias) > 0.1*expectedStandardError {
+		t.Errorf("expected Bias near (0), got (%f)", result.Bias)
+	}
+
+	tInterval, _ := set.MeanConfidenceInterval(0.95)
+
+	for _, interval := range []*stats.Interval{result.Percentile, result.Basic, result.BCa, result.Studentized} {
+		if interval == nil {
+			t.Errorf("expected all intervals, got nil")
+			continue
+		}
+
+		if interval.Level != 0.95 {
+			t.Errorf("on %s interval expected Level (0.95), got (%f)", interval.Method, interval.Level)
+		}
+
+		if math.Abs(interval.Lower-tInterval.Lower) > 0.05 || math.Abs(interval.Upper-tInterval.Upper) > 0.05 {
+			t.Errorf("on %s interval expected near %s, got %s", interval.Method, tInterval, interval)
+		}
+	}
+
+	for _, expectation := range []struct {
+		interval *stats.Interval
+		method   stats.IntervalMethod
+	}{
+		{result.Percentile, stats.IntervalBootstrapPercentile},
+		{result.Basic, stats.IntervalBootstrapBasic},
+		{result.BCa, stats.IntervalBootstrapBCa},
+		{result.Studentized, stats.IntervalBootstrapStudentized},
+	} {
+		if expectation.interval != nil && expectation.interval.Method != expectation.method {
+			t.Errorf("expected Method (%s), got (%s)", expectation.method, expectation.interval.Method)
+		}
+	}
+}
+
+func TestBootstrapBasicIntervalIsPercentileIntervalReflected(t *testing.T) {
+	set, _ := stats.MakeStatisticalSampleSetFrom([]float64{1, 1, 2, 3, 5, 8, 13, 21, 34, 55})
+
+	result, err := stats.Bootstrap(context.Background(), set, func(s *stats.StatisticalSampleSet) float64 { return s.Median() }, stats.BootstrapOptions{Seed: 3})
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	if len(result.Replicates) != 2000 {
+		t.Errorf("expected default of (2000) replicates, got (%d)", len(result.Replicates))
+	}
+
+	if result.Percentile.Level != 0.95 {
+		t.Errorf("expected default Level (0.95), got (%f)", result.Percentile.Level)
+	}
+
+	if result.Basic.Lower != 2*result.Estimate-result.Percentile.Upper || result.Basic.Upper != 2*result.Estimate-result.Percentile.Lower {
+		t.Errorf("expected Basic interval to be Percentile interval %s reflected about (%f), got %s", result.Percentile, result.Estimate, result.Basic)
+	}
+
+	if result.Studentized != nil {
+		t.Errorf("expected no Studentized interval when not requested, got %s", result.Studentized)
+	}
+}
+
+func TestBootstrapIsReproducible(t *testing.T) {
+	exponential, _ := stats.NewExponentialDistribution(1)
+	set, _ := stats.MakeStatisticalSampleSetFromDistribution(exponential, 50, rand.New(rand.NewSource(8)))
+	p90 := func(s *stats.StatisticalSampleSet) float64 { return s.Quantile(0.9, stats.QuantileDefault) }
+
+	run := func(seed int64, parallelism int) *stats.BootstrapResult {
+		result, err := stats.Bootstrap(context.Background(), set, p90, stats.BootstrapOptions{
+			NumberOfResamples:      500,
+			Seed:                   seed,
+			Parallelism:            parallelism,
+			NumberOfInnerResamples: 20,
+		})
+		if err != nil {
+			t.Fatalf("got unexpected error: %s", err)
+		}
+		return result
+	}
+
+	sequential, parallel, otherSeed := run(1, 1), run(1, 7), run(2, 7)
+
+	for i := range sequential.Replicates {
+		if sequential.Replicates[i] != parallel.Replicates[i] {
+			t.Fatalf("on replicate (%d) expected (%f) with Parallelism (7), got (%f)", i, sequential.Replicates[i], parallel.Replicates[i])
+		}
+	}
+
+	if *sequential.Studentized != *parallel.Studentized {
+		t.Errorf("expected Studentized interval %s with Parallelism (7), got %s", sequential.Studentized, parallel.Studentized)
+	}
+
+	differences := 0
+	for i := range sequential.Replicates {
+		if sequential.Replicates[i] != otherSeed.Replicates[i] {
+			differences++
+		}
+	}
+	if differences == 0 {
+		t.Errorf("expected a different seed to produce different replicates")
+	}
+}
+
+func TestBootstrapErrorsAndDegenerateSets(t *testing.T) {
+	set, _ := stats.MakeStatisticalSampleSetFrom([]float64{1, 2, 3, 4})
+	single, _ := stats.MakeStatisticalSampleSetFrom([]float64{7})
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for testIndex, testCase := range []struct {
+		ctx       context.Context
+		set       *stats.StatisticalSampleSet
+		statistic stats.SetStatistic
+		options   stats.BootstrapOptions
+	}{
+		{context.Background(), set, nil, stats.BootstrapOptions{}},
+		{context.Background(), set, meanOfSet, stats.BootstrapOptions{NumberOfResamples: -1}},
+		{context.Background(), set, meanOfSet, stats.BootstrapOptions{NumberOfInnerResamples: -1}},
+		{context.Background(), set, meanOfSet, stats.BootstrapOptions{ConfidenceLevel: 1.5}},
+		{context.Background(), single, func(s *stats.StatisticalSampleSet) float64 { return s.SampleVariance() }, stats.BootstrapOptions{}},
+		{context.Background(), set, func(s *stats.StatisticalSampleSet) float64 {
+			if s.Minimum() == s.Maximum() {
+				return math.NaN()
+			}
+			return s.Mean()
+		}, stats.BootstrapOptions{NumberOfResamples: 1000}},
+		{cancelled, set, meanOfSet, stats.BootstrapOptions{}},
+	} {
+		if _, err := stats.Bootstrap(testCase.ctx, testCase.set, testCase.statistic, testCase.options); err == nil {
+			t.Errorf("on test with index (%d) expected error, got none", testIndex)
+		}
+	}
+
+	result, err := stats.Bootstrap(context.Background(), single, meanOfSet, stats.BootstrapOptions{NumberOfResamples: 10})
+	if err != nil {
+		t.Fatalf("on set with one value got unexpected error: %s", err)
+	}
+
+	if result.BCa != nil {
+		t.Errorf("on set with one value expected no BCa interval, got %s", result.BCa)
+	}
+
+	if result.Percentile.Lower != 7 || result.Percentile.Upper != 7 || result.StandardError != 0 {
+		t.Errorf("on set with one value expected Percentile interval [7, 7] and StandardError (0), got %s and (%f)", result.Percentile, result.StandardError)
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type inputOptions struct {
+	format      string
+	column      string
+	header      bool
+	field       string
+	skipInvalid bool
+}
+
+func (options *inputOptions) validate() error {
+	switch options.format {
+	case "lines", "csv", "json", "ndjson":
+	default:
+		return fmt.Errorf("unknown input format (%s)", options.format)
+	}
+
+	if options.format == "csv" && !options.header {
+		if index, err := strconv.Atoi(options.column); err != nil || index < 1 {
+			return fmt.Errorf("without a header, the CSV column must be a 1-based index")
+		}
+	}
+
+	return nil
+}
+
+// readValues reads the numbers in r in the format given by options.  Blank lines, and in the lines format, lines
+// beginning with "#", are ignored.  When options.skipInvalid is set, values that are not numbers are counted rather
+// than causing an error.
+func readValues(r io.Reader, options inputOptions) (values []float64, numberSkipped int, err error) {
+	switch options.format {
+	case "csv":
+		return readCSVValues(r, options)
+	case "json":
+		return readJSONArrayValues(r, options)
+	case "ndjson":
+		return readNDJSONValues(r, options)
+	}
+
+	return readLineValues(r, options)
+}
+
+func readLineValues(r io.Reader, options inputOptions) (values []float64, numberSkipped int, err error) {
+	scanner := bufio.NewScanner(r)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		value, err := parseNumber(line)
+		if err != nil {
+			if options.skipInvalid {
+				numberSkipped++
+				continue
+			}
+			return nil, 0, fmt.Errorf("line %d: (%s) is not a number", lineNumber, line)
+		}
+
+		values = append(values, value)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return values, numberSkipped, nil
+}
+
+func readCSVValues(r io.Reader, options inputOptions) (values []float64, numberSkipped int, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columnIndex := -1
+	if index, err := strconv.Atoi(options.column); err == nil && index >= 1 {
+		columnIndex = index - 1
+	}
+
+	for recordNumber := 1; ; recordNumber++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if recordNumber == 1 && options.header {
+			// a column name takes precedence over an index, so that a column may be named "2"
+			for i, name := range record {
+				if strings.TrimSpace(name) == options.column {
+					columnIndex = i
+					break
+				}
+			}
+
+			if columnIndex < 0 {
+				return nil, 0, fmt.Errorf("there is no CSV column named (%s)", options.column)
+			}
+			continue
+		}
+
+		if len(record) == 1 && record[0] == "" {
+			continue
+		}
+
+		if columnIndex >= len(record) {
+			if options.skipInvalid {
+				numberSkipped++
+				continue
+			}
+			return nil, 0, fmt.Errorf("record %d: there is no column %d", recordNumber, columnIndex+1)
+		}
+
+		cell := strings.TrimSpace(record[columnIndex])
+		value, err := parseNumber(cell)
+		if err != nil {
+			if options.skipInvalid {
+				numberSkipped++
+				continue
+			}
+			return nil, 0, fmt.Errorf("record %d: (%s) is not a number", recordNumber, cell)
+		}
+
+		values = append(values, value)
+	}
+
+	return values, numberSkipped, nil
+}
+
+func readJSONArrayValues(r io.Reader, options inputOptions) (values []float64, numberSkipped int, err error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var elements []interface{}
+	if err := decoder.Decode(&elements); err != nil {
+		return nil, 0, fmt.Errorf("input is not a JSON array: %s", err)
+	}
+
+	for i, element := range elements {
+		value, err := numberAtPath(element, options.field)
+		if err != nil {
+			if options.skipInvalid {
+				numberSkipped++
+				continue
+			}
+			return nil, 0, fmt.Errorf("element %d: %s", i, err)
+		}
+
+		values = append(values, value)
+	}
+
+	return values, numberSkipped, nil
+}
+
+func readNDJSONValues(r io.Reader, options inputOptions) (values []float64, numberSkipped int, err error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	for elementNumber := 1; ; elementNumber++ {
+		var element interface{}
+		if err := decoder.Decode(&element); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, 0, fmt.Errorf("value %d: %s", elementNumber, err)
+		}
+
+		value, err := numberAtPath(element, options.field)
+		if err != nil {
+			if options.skipInvalid {
+				numberSkipped++
+				continue
+			}
+			return nil, 0, fmt.Errorf("value %d: %s", elementNumber, err)
+		}
+
+		values = append(values, value)
+	}
+
+	return values, numberSkipped, nil
+}
+
+// numberAtPath returns the number found by following the dotted path from element, where each part of the path is
+// either the key of an object or the index of an array.  A string holding a number is accepted as a number.
+func numberAtPath(element interface{}, path string) (float64, error) {
+	if path != "" {
+		for _, part := range strings.Split(path, ".") {
+			switch container := element.(type) {
+			case map[string]interface{}:
+				value, keyIsPresent := container[part]
+				if !keyIsPresent {
+					return 0, fmt.Errorf("there is no field (%s)", path)
+				}
+				element = value
+
+			case []interface{}:
+				index, err := strconv.Atoi(part)
+				if err != nil || index < 0 || index >= len(container) {
+					return 0, fmt.Errorf("there is no field (%s)", path)
+				}
+				element = container[index]
+
+			default:
+				return 0, fmt.Errorf("there is no field (%s)", path)
+			}
+		}
+	}
+
+	switch value := element.(type) {
+	case json.Number:
+		return value.Float64()
+	case string:
+		if number, err := parseNumber(strings.TrimSpace(value)); err == nil {
+			return number, nil
+		}
+	}
+
+	return 0, fmt.Errorf("(%v) is not a number", element)
+}
+
+// parseNumber parses a finite number, rejecting the infinities and NaN that strconv.ParseFloat accepts.
+func parseNumber(text string) (float64, error) {
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if math.IsInf(value, 0) || math.IsNaN(value) {
+		return 0, fmt.Errorf("(%s) is not a finite number", text)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,129 @@
+// Command stats reads numbers from standard input or files and writes summary statistics of them.
+//
+// Usage:
+//
+//	stats [flags] [file ...]
+//
+// With no files, or with a file named "-", numbers are read from standard input.  The values from every file are
+// combined into a single set.  Input is one number per line by default; -input selects CSV, a JSON array, or
+// newline-delimited JSON.  For CSV, -column selects a column by header name or by 1-based index.  For JSON, -field
+// selects a value within each element by a dotted path such as "latency.p99" (array elements are selected by
+// index, e.g., "samples.0").
+//
+// With -output json, a statistic that overflows, such as the standard deviation of 1e200 and -1e200, is written as
+// the string "+Inf", "-Inf" or "NaN" rather than as a number.
+//
+// For example, to summarize the seventh column of a space-separated log:
+//
+//	awk '{print $7}' access.log | stats -percentiles 50,90,99,99.9
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command with args, returning the exit status: 0 on success, 1 if the input could not be read or
+// summarized, and 2 if the arguments are invalid.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var input inputOptions
+	flags.StringVar(&input.format, "input", "lines", "input format: lines, csv, json or ndjson")
+	flags.StringVar(&input.column, "column", "1", "CSV column to read, by header name or 1-based index")
+	flags.BoolVar(&input.header, "header", true, "whether the first CSV record is a header")
+	flags.StringVar(&input.field, "field", "", "dotted path of the value within each JSON element (default the element itself)")
+	flags.BoolVar(&input.skipInvalid, "skip-invalid", false, "skip values that are not numbers, rather than failing")
+
+	outputFormat := flags.String("output", "human", "output format: human, json or csv")
+	percentilesFlag := flags.String("percentiles", "50,90,95,99", "comma-separated percentiles to report, each in [0, 100]")
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := input.validate(); err != nil {
+		fmt.Fprintf(stderr, "stats: %s\n", err)
+		return 2
+	}
+
+	writeSummary, outputFormatIsValid := summaryWriters[*outputFormat]
+	if !outputFormatIsValid {
+		fmt.Fprintf(stderr, "stats: unknown output format (%s)\n", *outputFormat)
+		return 2
+	}
+
+	percentiles, err := parsePercentiles(*percentilesFlag)
+	if err != nil {
+		fmt.Fprintf(stderr, "stats: %s\n", err)
+		return 2
+	}
+
+	fileNames := flags.Args()
+	if len(fileNames) == 0 {
+		fileNames = []string{"-"}
+	}
+
+	var values []float64
+	for _, fileName := range fileNames {
+		valuesOfFile, numberSkipped, err := readValuesFromFile(fileName, stdin, input)
+		if err != nil {
+			fmt.Fprintf(stderr, "stats: %s\n", err)
+			return 1
+		}
+
+		if numberSkipped > 0 {
+			fmt.Fprintf(stderr, "stats: %s: skipped %d values that are not numbers\n", displayNameOf(fileName), numberSkipped)
+		}
+
+		values = append(values, valuesOfFile...)
+	}
+
+	summary, err := summarize(values, percentiles)
+	if err != nil {
+		fmt.Fprintf(stderr, "stats: %s\n", err)
+		return 1
+	}
+
+	if err := writeSummary(stdout, summary); err != nil {
+		fmt.Fprintf(stderr, "stats: %s\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+func readValuesFromFile(fileName string, stdin io.Reader, options inputOptions) (values []float64, numberSkipped int, err error) {
+	reader := stdin
+	if fileName != "-" {
+		file, err := os.Open(fileName)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer file.Close()
+
+		reader = file
+	}
+
+	values, numberSkipped, err = readValues(reader, options)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %s", displayNameOf(fileName), err)
+	}
+
+	return values, numberSkipped, nil
+}
+
+func displayNameOf(fileName string) string {
+	if fileName == "-" {
+		return "standard input"
+	}
+
+	return fileName
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func runWith(args []string, input string) (exitStatus int, stdout string, stderr string) {
+	var stdoutBuffer, stderrBuffer bytes.Buffer
+	exitStatus = run(args, strings.NewReader(input), &stdoutBuffer, &stderrBuffer)
+	return exitStatus, stdoutBuffer.String(), stderrBuffer.String()
+}
+
+func TestSummaryOfEachInputFormat(t *testing.T) {
+	for testIndex, testCase := range []struct {
+		args  []string
+		input string
+	}{
+		{[]string{}, "1\n2\n\n# a comment\n2\n3.5\n10\n"},
+		{[]string{"-input", "csv", "-column", "latency"}, "host,latency\na,1\nb,2\nc,2\nd,3.5\ne,10\n"},
+		{[]string{"-input", "csv", "-column", "2", "-header=false"}, "a,1\nb,2\nc,2\nd,3.5\ne,10\n"},
+		{[]string{"-input", "json", "-field", "timing.total"}, `[{"timing":{"total":1}},{"timing":{"total":2}},{"timing":{"total":"2"}},{"timing":{"total":3.5}},{"timing":{"total":10}}]`},
+		{[]string{"-input", "json"}, `[1, 2, 2, 3.5, 10]`},
+		{[]string{"-input", "ndjson", "-field", "samples.1"}, "{\"samples\":[0,1]}\n{\"samples\":[0,2]}\n{\"samples\":[0,2]}\n{\"samples\":[0,3.5]}\n{\"samples\":[0,10]}\n"},
+	} {
+		exitStatus, stdout, stderr := runWith(append(testCase.args, "-output", "json", "-percentiles", "50,90"), testCase.input)
+		if exitStatus != 0 {
+			t.Errorf("on test with index (%d) expected exit status (0), got (%d) with error (%s)", testIndex, exitStatus, stderr)
+			continue
+		}
+
+		var s summary
+		if err := json.Unmarshal([]byte(stdout), &s); err != nil {
+			t.Errorf("on test with index (%d) output is not a JSON summary: %s", testIndex, err)
+			continue
+		}
+
+		if s.Count != 5 || s.Minimum != 1 || s.Maximum != 10 || s.Mean != 3.7 || s.Median != 2 {
+			t.Errorf("on test with index (%d) got unexpected count, min, max, mean or median in %+v", testIndex, s)
+		}
+
+		if len(s.Mode) != 1 || s.Mode[0] != 2 || s.ModeFrequency != 2 {
+			t.Errorf("on test with index (%d) expected mode (2) occurring (2) times, got %v occurring (%d) times", testIndex, s.Mode, s.ModeFrequency)
+		}
+
+		if s.Q1 != 1.5 || s.Q3 != 6.75 || s.IQR != 5.25 {
+			t.Errorf("on test with index (%d) expected q1, q3 and iqr (1.5, 6.75, 5.25), got (%f, %f, %f)", testIndex, s.Q1, s.Q3, s.IQR)
+		}
+
+		if len(s.Percentiles) != 2 || s.Percentiles[0] != (percentileValue{50, 2}) || s.Percentiles[1].Percentile != 90 {
+			t.Errorf("on test with index (%d) got unexpected percentiles %v", testIndex, s.Percentiles)
+		}
+	}
+}
+
+func TestHumanAndCSVOutput(t *testing.T) {
+	exitStatus, stdout, _ := runWith([]string{"-percentiles", "50"}, "4\n")
+	expected := `count   1
+min     4
+max     4
+mean    4
+median  4
+mode    4 (1 times)
+stdev   undefined (one value)
+q1      4
+q3      4
+iqr     0
+p50     4
+`
+	if exitStatus != 0 || stdout != expected {
+		t.Errorf("on human output expected:\n%s\ngot (exit status %d):\n%s", expected, exitStatus, stdout)
+	}
+
+	exitStatus, stdout, _ = runWith([]string{"-output", "csv", "-percentiles", "50,99.9"}, "1\n2\n3\n")
+	expected = "count,min,max,mean,median,mode,stdev,q1,q3,iqr,p50,p99.9\n3,1,3,2,2,,1,1,3,2,2,2.998\n"
+	if exitStatus != 0 || stdout != expected {
+		t.Errorf("on csv output expected:\n%s\ngot (exit status %d):\n%s", expected, exitStatus, stdout)
+	}
+}
+
+func TestJSONOutputOfStatisticsThatOverflow(t *testing.T) {
+	for testIndex, testCase := range []struct {
+		input         string
+		expectedStdev any
+		expectedIQR   any
+	}{
+		// the squares of the values overflow
+		{"1e200\n-1e200\n", "+Inf", 2e200},
+		// so does the distance between the quartiles
+		{"1.7e308\n-1.7e308\n1.7e308\n-1.7e308\n", "+Inf", "+Inf"},
+	} {
+		exitStatus, stdout, stderr := runWith([]string{"-output", "json"}, testCase.input)
+		if exitStatus != 0 {
+			t.Errorf("on test with index (%d) expected exit status (0), got (%d) with error (%s)", testIndex, exitStatus, stderr)
+			continue
+		}
+
+		var s map[string]any
+		if err := json.Unmarshal([]byte(stdout), &s); err != nil {
+			t.Errorf("on test with index (%d) output is not JSON: %s", testIndex, err)
+			continue
+		}
+
+		if s["mean"] != 0.0 || s["stdev"] != testCase.expectedStdev || s["iqr"] != testCase.expectedIQR {
+			t.Errorf("on test with index (%d) expected mean (0), stdev (%v) and iqr (%v), got (%v), (%v) and (%v)", testIndex, testCase.expectedStdev, testCase.expectedIQR, s["mean"], s["stdev"], s["iqr"])
+		}
+	}
+}
+
+func TestSkipInvalid(t *testing.T) {
+	exitStatus, stdout, stderr := runWith([]string{"-output", "json", "-skip-invalid"}, "1\nfast\n3\nNaN\n")
+	if exitStatus != 0 {
+		t.Fatalf("expected exit status (0), got (%d) with error (%s)", exitStatus, stderr)
+	}
+
+	var s summary
+	if err := json.Unmarshal([]byte(stdout), &s); err != nil {
+		t.Fatalf("output is not a JSON summary: %s", err)
+	}
+
+	if s.Count != 2 || s.Mean != 2 {
+		t.Errorf("expected count (2) and mean (2), got (%d) and (%f)", s.Count, s.Mean)
+	}
+
+	if stderr != "stats: standard input: skipped 2 values that are not numbers\n" {
+		t.Errorf("expected a note of the skipped values, got (%s)", stderr)
+	}
+}
+
+func TestErrorExitStatus(t *testing.T) {
+	for testIndex, testCase := range []struct {
+		args               []string
+		input              string
+		expectedExitStatus int
+	}{
+		{[]string{"-input", "xml"}, "1\n", 2},
+		{[]string{"-output", "yaml"}, "1\n", 2},
+		{[]string{"-percentiles", "50,101"}, "1\n", 2},
+		{[]string{"-input", "csv", "-header=false", "-column", "latency"}, "1\n", 2},
+		{[]string{"-no-such-flag"}, "1\n", 2},
+		{[]string{}, "1\nfast\n", 1},
+		{[]string{}, "", 1},
+		{[]string{"-input", "csv", "-column", "latency"}, "host,duration\na,1\n", 1},
+		{[]string{"-input", "json"}, `{"not": "an array"}`, 1},
+		{[]string{"-input", "json", "-field", "a.b"}, `[{"a": {"c": 1}}]`, 1},
+		{[]string{"no-such-file"}, "", 1},
+	} {
+		exitStatus, _, stderr := runWith(testCase.args, testCase.input)
+		if exitStatus != testCase.expectedExitStatus {
+			t.Errorf("on test with index (%d) expected exit status (%d), got (%d)", testIndex, testCase.expectedExitStatus, exitStatus)
+		}
+
+		if stderr == "" {
+			t.Errorf("on test with index (%d) expected an error message, got none", testIndex)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+// A statistic is written to JSON as a number when it is finite, and otherwise as the string "+Inf", "-Inf" or "NaN",
+// since JSON numbers cannot represent those.  Statistics of finite values can still overflow: for example, the
+// standard deviation of 1e200 and -1e200 is +Inf because the squares of the values are.
+type statistic float64
+
+func (value statistic) MarshalJSON() ([]byte, error) {
+	if math.IsInf(float64(value), 0) || math.IsNaN(float64(value)) {
+		return json.Marshal(formatNumber(float64(value)))
+	}
+
+	return json.Marshal(float64(value))
+}
+
+type percentileValue struct {
+	Percentile float64   `json:"percentile"`
+	Value      statistic `json:"value"`
+}
+
+// A summary holds the statistics that are written.  The standard deviation is nil when there is only one value,
+// and Mode is empty when every value occurs once.
+type summary struct {
+	Count         int               `json:"count"`
+	Minimum       float64           `json:"min"`
+	Maximum       float64           `json:"max"`
+	Mean          statistic         `json:"mean"`
+	Median        statistic         `json:"median"`
+	Mode          []float64         `json:"mode"`
+	ModeFrequency uint              `json:"mode_frequency"`
+	Stdev         *statistic        `json:"stdev"`
+	Q1            statistic         `json:"q1"`
+	Q3            statistic         `json:"q3"`
+	IQR           statistic         `json:"iqr"`
+	Percentiles   []percentileValue `json:"percentiles"`
+}
+
+func summarize(values []float64, percentiles []float64) (*summary, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("there are no values to summarize")
+	}
+
+	set, err := stats.MakeStatisticalSampleSetFrom(values)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &summary{
+		Count:   set.Count(),
+		Minimum: set.Minimum(),
+		Maximum: set.Maximum(),
+		Mean:    statistic(set.Mean()),
+		Median:  statistic(set.Median()),
+		Mode:    []float64{},
+	}
+
+	if s.Count > 1 {
+		stdev := statistic(set.SampleStdev())
+		s.Stdev = &stdev
+	}
+
+	frequency, modes := set.Mode()
+	s.ModeFrequency = frequency
+	if frequency > 1 || s.Count == 1 {
+		s.Mode = append(s.Mode, modes...)
+		sort.Float64s(s.Mode)
+	}
+
+	q1, q3, iqr := set.InterQuartileRange()
+	s.Q1, s.Q3, s.IQR = statistic(q1), statistic(q3), statistic(iqr)
+
+	quantiles := make([]float64, len(percentiles))
+	for i, percentile := range percentiles {
+		quantiles[i] = percentile / 100
+	}
+
+	for i, value := range set.Quantiles(quantiles, stats.QuantileDefault) {
+		s.Percentiles = append(s.Percentiles, percentileValue{Percentile: percentiles[i], Value: statistic(value)})
+	}
+
+	return s, nil
+}
+
+func parsePercentiles(list string) ([]float64, error) {
+	var percentiles []float64
+
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		percentile, err := strconv.ParseFloat(field, 64)
+		if err != nil || !(percentile >= 0 && percentile <= 100) {
+			return nil, fmt.Errorf("percentile (%s) is not a number in [0, 100]", field)
+		}
+
+		percentiles = append(percentiles, percentile)
+	}
+
+	return percentiles, nil
+}
+
+var summaryWriters = map[string]func(w io.Writer, s *summary) error{
+	"human": writeHumanSummary,
+	"json":  writeJSONSummary,
+	"csv":   writeCSVSummary,
+}
+
+// labeledValues returns the name and formatted value of each statistic, in the order in which they are written.
+func (s *summary) labeledValues() (labels []string, values []string) {
+	add := func(label string, value string) {
+		labels = append(labels, label)
+		values = append(values, value)
+	}
+
+	add("count", strconv.Itoa(s.Count))
+	add("min", formatNumber(s.Minimum))
+	add("max", formatNumber(s.Maximum))
+	add("mean", formatNumber(float64(s.Mean)))
+	add("median", formatNumber(float64(s.Median)))
+
+	modes := make([]string, len(s.Mode))
+	for i, mode := range s.Mode {
+		modes[i] = formatNumber(mode)
+	}
+	add("mode", strings.Join(modes, " "))
+
+	stdev := ""
+	if s.Stdev != nil {
+		stdev = formatNumber(float64(*s.Stdev))
+	}
+	add("stdev", stdev)
+
+	add("q1", formatNumber(float64(s.Q1)))
+	add("q3", formatNumber(float64(s.Q3)))
+	add("iqr", formatNumber(float64(s.IQR)))
+
+	for _, percentile := range s.Percentiles {
+		add("p"+formatNumber(percentile.Percentile), formatNumber(float64(percentile.Value)))
+	}
+
+	return labels, values
+}
+
+func writeHumanSummary(w io.Writer, s *summary) error {
+	tabularWriter := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	labels, values := s.labeledValues()
+
+	for i, label := range labels {
+		value := values[i]
+
+		switch {
+		case label == "mode" && len(s.Mode) == 0:
+			value = "none (every value occurs once)"
+		case label == "mode":
+			value = fmt.Sprintf("%s (%d times)", value, s.ModeFrequency)
+		case label == "stdev" && s.Stdev == nil:
+			value = "undefined (one value)"
+		}
+
+		fmt.Fprintf(tabularWriter, "%s\t%s\n", label, value)
+	}
+
+	return tabularWriter.Flush()
+}
+
+func writeJSONSummary(w io.Writer, s *summary) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(s)
+}
+
+// writeCSVSummary writes a header row naming each statistic and a single row of values, so that the output of
+// several runs may be concatenated.  Multiple modes are separated by spaces.
+func writeCSVSummary(w io.Writer, s *summary) error {
+	csvWriter := csv.NewWriter(w)
+	labels, values := s.labeledValues()
+
+	csvWriter.Write(labels)
+	csvWriter.Write(values)
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
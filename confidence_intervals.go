@@ -0,0 +1,173 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+)
+
+// An IntervalMethod identifies how an Interval was computed.
+type IntervalMethod int
+
+const (
+	// Student's t-distribution, for the mean of a normally distributed population.
+	IntervalStudentT IntervalMethod = iota
+	// The chi-squared distribution, for the variance of a normally distributed population.
+	IntervalChiSquared
+	// A pair of order statistics whose ranks are chosen from the binomial distribution; distribution-free.
+	IntervalOrderStatistic
+	// The quantiles of the bootstrap replicates.
+	IntervalBootstrapPercentile
+	// The quantiles of the bootstrap replicates reflected about the estimate.
+	IntervalBootstrapBasic
+	// The quantiles of the bootstrap replicates, adjusted for bias and skewness (bias-corrected and accelerated).
+	IntervalBootstrapBCa
+	// The quantiles of the bootstrap replicates standardized by their own standard errors (bootstrap-t).
+	IntervalBootstrapStudentized
+	// An estimate plus or minus a multiple of its approximate standard error, from the normal distribution.
+	IntervalNormalApproximation
+	// Cliff's asymmetric interval for an ordinal dominance statistic, which remains within [-1, 1].
+	IntervalCliffAsymmetric
+	// The normal interval for Fisher's z transformation of a correlation coefficient, transformed back to [-1, 1].
+	IntervalFisherZ
+)
+
+func (method IntervalMethod) String() string {
+	switch method {
+	case IntervalStudentT:
+		return "Student's t"
+	case IntervalChiSquared:
+		return "chi-squared"
+	case IntervalOrderStatistic:
+		return "order statistic"
+	case IntervalBootstrapPercentile:
+		return "bootstrap percentile"
+	case IntervalBootstrapBasic:
+		return "bootstrap basic"
+	case IntervalBootstrapBCa:
+		return "bootstrap BCa"
+	case IntervalBootstrapStudentized:
+		return "bootstrap studentized"
+	case IntervalNormalApproximation:
+		return "normal approximation"
+	case IntervalCliffAsymmetric:
+		return "Cliff's asymmetric"
+	case IntervalFisherZ:
+		return "Fisher's z"
+	}
+
+	return fmt.Sprintf("IntervalMethod(%d)", int(method))
+}
+
+// An Interval is a confidence interval [Lower, Upper] at confidence level Level (e.g., 0.95), computed using Method.
+type Interval struct {
+	Lower  float64
+	Upper  float64
+	Level  float64
+	Method IntervalMethod
+}
+
+func (interval *Interval) String() string {
+	return fmt.Sprintf("[%g, %g]", interval.Lower, interval.Upper)
+}
+
+// MeanConfidenceInterval returns the two-sided confidence interval for the population mean based on Student's
+// t-distribution, which assumes that the population is normally distributed or that the set is large.  The
+// confidence level must be greater than 0 and less than 1, and there must be at least two values in the set.
+func (set *StatisticalSampleSet) MeanConfidenceInterval(level float64) (*Interval, error) {
+	if err := errorIfConfidenceLevelIsNotValid(level); err != nil {
+		return nil, err
+	}
+
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	n := float64(len(set.valuesSortedInAscendingOrder))
+	if n < 2 {
+		return nil, fmt.Errorf("there must be at least two samples in the set")
+	}
+
+	mean := set.mean()
+	margin := studentTQuantile((1+level)/2, n-1) * math.Sqrt(set.sampleVariance()/n)
+
+	return &Interval{Lower: mean - margin, Upper: mean + margin, Level: level, Method: IntervalStudentT}, nil
+}
+
+// VarianceConfidenceInterval returns the two-sided confidence interval for the population variance based on the
+// chi-squared distribution.  Unlike the interval for the mean, this is sensitive to departures from normality even
+// for large sets.  The confidence level must be greater than 0 and less than 1, and there must be at least two values
+// in the set.
+func (set *StatisticalSampleSet) VarianceConfidenceInterval(level float64) (*Interval, error) {
+	if err := errorIfConfidenceLevelIsNotValid(level); err != nil {
+		return nil, err
+	}
+
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	n := float64(len(set.valuesSortedInAscendingOrder))
+	if n < 2 {
+		return nil, fmt.Errorf("there must be at least two samples in the set")
+	}
+
+	sumOfSquaredDeviations := (n - 1) * set.sampleVariance()
+	alpha := 1 - level
+
+	chiSquared, _ := NewChiSquaredDistribution(n - 1)
+
+	return &Interval{
+		Lower:  sumOfSquaredDeviations / chiSquared.Quantile(1-alpha/2),
+		Upper:  sumOfSquaredDeviations / chiSquared.Quantile(alpha/2),
+		Level:  level,
+		Method: IntervalChiSquared,
+	}, nil
+}
+
+// MedianConfidenceInterval returns QuantileConfidenceInterval(0.5, level).
+func (set *StatisticalSampleSet) MedianConfidenceInterval(level float64) (*Interval, error) {
+	return set.QuantileConfidenceInterval(0.5, level)
+}
+
+// QuantileConfidenceInterval returns a distribution-free two-sided confidence interval for the population quantile
+// q, which must be greater than 0 and less than 1.  The bounds are the order statistics X(l) and X(u), where l and u
+// are chosen so that the probability of the quantile lying below X(l) or above X(u) is each at most (1 - level) / 2
+// under the binomial distribution of the number of values below the quantile.  Because the ranks are integers, the
+// actual coverage is at least level.  An error is returned if the set is too small for such ranks to exist; for
+// example, an interval for the 99th percentile at level 0.95 requires at least 368 values.
+func (set *StatisticalSampleSet) QuantileConfidenceInterval(q float64, level float64) (*Interval, error) {
+	if err := errorIfConfidenceLevelIsNotValid(level); err != nil {
+		return nil, err
+	}
+
+	if !(q > 0 && q < 1) {
+		return nil, fmt.Errorf("quantile must be greater than 0 and less than 1")
+	}
+
+	sortedValues := set.snapshotOfSortedValues()
+	n := len(sortedValues)
+
+	binomial, _ := NewBinomialDistribution(n, q)
+	alpha := 1 - level
+
+	// P(X(l) > quantile) = P(Binomial(n, q) <= l - 1), and P(X(u) < quantile) = 1 - P(Binomial(n, q) <= u - 1)
+	lowerRank := int(binomial.Quantile(alpha / 2))
+	upperRank := int(binomial.Quantile(1-alpha/2)) + 1
+
+	if lowerRank < 1 || upperRank > n {
+		return nil, fmt.Errorf("there are too few samples in the set for a confidence interval for quantile (%g) at level (%g)", q, level)
+	}
+
+	return &Interval{
+		Lower:  sortedValues[lowerRank-1],
+		Upper:  sortedValues[upperRank-1],
+		Level:  level,
+		Method: IntervalOrderStatistic,
+	}, nil
+}
+
+func errorIfConfidenceLevelIsNotValid(level float64) error {
+	if !(level > 0 && level < 1) {
+		return fmt.Errorf("confidence level must be greater than 0 and less than 1")
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+func TestMeanAndVarianceConfidenceIntervalsAgainstR(t *testing.T) {
+	group1, _ := stats.MakeStatisticalSampleSetFrom(sleepGroup1)
+
+	// R: t.test(x, conf.level = level)$conf.int; (n - 1) * var(x) / qchisq(c(1 - alpha/2, alpha/2), n - 1)
+	for testIndex, testCase := range []struct {
+		compute        func(level float64) (*stats.Interval, error)
+		level          float64
+		expectedLower  float64
+		expectedUpper  float64
+		expectedMethod stats.IntervalMethod
+	}{
+		{group1.MeanConfidenceInterval, 0.95, -0.5297804, 2.0297804, stats.IntervalStudentT},
+		{group1.MeanConfidenceInterval, 0.99, -1.0885444, 2.5885444, stats.IntervalStudentT},
+		{group1.VarianceConfidenceInterval, 0.95, 1.5142379, 10.6669817, stats.IntervalChiSquared},
+		{group1.VarianceConfidenceInterval, 0.90, 1.7025258, 8.6628635, stats.IntervalChiSquared},
+	} {
+		interval, err := testCase.compute(testCase.level)
+		if err != nil {
+			t.Errorf("on test with index (%d) got unexpected error: %s", testIndex, err)
+			continue
+		}
+
+		if math.Abs(interval.Lower-testCase.expectedLower) > 1e-5 || math.Abs(interval.Upper-testCase.expectedUpper) > 1e-5 {
+			t.Errorf("on test with index (%d) expected interval [%f, %f], got [%f, %f]", testIndex, testCase.expectedLower, testCase.expectedUpper, interval.Lower, interval.Upper)
+		}
+
+		if interval.Level != testCase.level {
+			t.Errorf("on test with index (%d) expected Level (%f), got (%f)", testIndex, testCase.level, interval.Level)
+		}
+
+		if interval.Method != testCase.expectedMethod {
+			t.Errorf("on test with index (%d) expected Method (%s), got (%s)", testIndex, testCase.expectedMethod, interval.Method)
+		}
+	}
+}
+
+func TestQuantileConfidenceIntervals(t *testing.T) {
+	// the values are the integers 1..n, so each bound is the rank of the order statistic it was chosen from
+	setOfIntegers := func(n int) *stats.StatisticalSampleSet {
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = float64(n - i)
+		}
+		set, _ := stats.MakeStatisticalSampleSetFrom(values)
+		return set
+	}
+
+	// expected ranks are R: qbinom(alpha/2, n, q) and qbinom(1 - alpha/2, n, q) + 1
+	for testIndex, testCase := range []struct {
+		n             int
+		q             float64
+		level         float64
+		expectedLower float64
+		expectedUpper float64
+	}{
+		{10, 0.5, 0.95, 2, 9},
+		{6, 0.5, 0.95, 1, 6},
+		{100, 0.5, 0.95, 40, 61},
+		{100, 0.9, 0.95, 84, 96},
+		{100, 0.25, 0.99, 14, 38},
+		{368, 0.99, 0.95, 360, 368},
+		{1000, 0.1, 0.9, 85, 117},
+	} {
+		interval, err := setOfIntegers(testCase.n).QuantileConfidenceInterval(testCase.q, testCase.level)
+		if err != nil {
+			t.Errorf("on test with index (%d) got unexpected error: %s", testIndex, err)
+			continue
+		}
+
+		if interval.Lower != testCase.expectedLower || interval.Upper != testCase.expectedUpper {
+			t.Errorf("on test with index (%d) expected interval [%.0f, %.0f], got [%.0f, %.0f]", testIndex, testCase.expectedLower, testCase.expectedUpper, interval.Lower, interval.Upper)
+		}
+
+		if interval.Method != stats.IntervalOrderStatistic {
+			t.Errorf("on test with index (%d) expected Method (%s), got (%s)", testIndex, stats.IntervalOrderStatistic, interval.Method)
+		}
+	}
+
+	median, err := setOfIntegers(10).MedianConfidenceInterval(0.95)
+	if err != nil {
+		t.Errorf("on MedianConfidenceInterval got unexpected error: %s", err)
+	} else if median.Lower != 2 || median.Upper != 9 {
+		t.Errorf("on MedianConfidenceInterval expected interval [2, 9], got [%.0f, %.0f]", median.Lower, median.Upper)
+	}
+}
+
+func TestConfidenceIntervalErrors(t *testing.T) {
+	single, _ := stats.MakeStatisticalSampleSetFrom([]float64{1})
+	five, _ := stats.MakeStatisticalSampleSetFrom([]float64{1, 2, 3, 4, 5})
+	ten, _ := stats.MakeStatisticalSampleSetFrom(sleepGroup1)
+
+	for testIndex, compute := range []func() (*stats.Interval, error){
+		func() (*stats.Interval, error) { return single.MeanConfidenceInterval(0.95) },
+		func() (*stats.Interval, error) { return single.VarianceConfidenceInterval(0.95) },
+		func() (*stats.Interval, error) { return ten.MeanConfidenceInterval(0) },
+		func() (*stats.Interval, error) { return ten.MeanConfidenceInterval(1) },
+		func() (*stats.Interval, error) { return ten.VarianceConfidenceInterval(math.NaN()) },
+		func() (*stats.Interval, error) { return ten.QuantileConfidenceInterval(0, 0.95) },
+		func() (*stats.Interval, error) { return ten.QuantileConfidenceInterval(1, 0.95) },
+		func() (*stats.Interval, error) { return ten.QuantileConfidenceInterval(0.5, 1.5) },
+		func() (*stats.Interval, error) { return five.MedianConfidenceInterval(0.95) },
+		func() (*stats.Interval, error) { return ten.QuantileConfidenceInterval(0.9, 0.95) },
+	} {
+		if _, err := compute(); err == nil {
+			t.Errorf("on test with index (%d) expected error, got none", testIndex)
+		}
+	}
+}
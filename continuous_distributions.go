@@ -0,0 +1,680 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// NormalDistribution is the normal distribution with a given mean and standard deviation.
+type NormalDistribution struct {
+	mean              float64
+	standardDeviation float64
+}
+
+// NewNormalDistribution returns the normal distribution with the given mean and standard deviation, which must be
+// greater than 0.
+func NewNormalDistribution(mean float64, standardDeviation float64) (*NormalDistribution, error) {
+	if math.IsNaN(mean) || math.IsInf(mean, 0) {
+		return nil, fmt.Errorf("mean must be finite")
+	}
+
+	if !(standardDeviation > 0) || math.IsInf(standardDeviation, 1) {
+		return nil, fmt.Errorf("standard deviation must be greater than 0 and finite")
+	}
+
+	return &NormalDistribution{mean: mean, standardDeviation: standardDeviation}, nil
+}
+
+func (d *NormalDistribution) PDF(x float64) float64 {
+	z := (x - d.mean) / d.standardDeviation
+	return math.Exp(-z*z/2) / (d.standardDeviation * math.Sqrt(2*math.Pi))
+}
+
+func (d *NormalDistribution) CDF(x float64) float64 {
+	return standardNormalCDF((x - d.mean) / d.standardDeviation)
+}
+
+func (d *NormalDistribution) Quantile(p float64) float64 {
+	if probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	return d.mean + d.standardDeviation*standardNormalQuantile(p)
+}
+
+func (d *NormalDistribution) Mean() float64 {
+	return d.mean
+}
+
+func (d *NormalDistribution) Variance() float64 {
+	return d.standardDeviation * d.standardDeviation
+}
+
+func (d *NormalDistribution) Rand(rng *rand.Rand) float64 {
+	return d.mean + d.standardDeviation*rng.NormFloat64()
+}
+
+// LogNormalDistribution is the distribution of a variate whose logarithm is normally distributed.
+type LogNormalDistribution struct {
+	meanOfLog              float64
+	standardDeviationOfLog float64
+}
+
+// NewLogNormalDistribution returns the log-normal distribution whose logarithm has the given mean and standard
+// deviation, which must be greater than 0.
+func NewLogNormalDistribution(meanOfLog float64, standardDeviationOfLog float64) (*LogNormalDistribution, error) {
+	if math.IsNaN(meanOfLog) || math.IsInf(meanOfLog, 0) {
+		return nil, fmt.Errorf("mean of the logarithm must be finite")
+	}
+
+	if !(standardDeviationOfLog > 0) || math.IsInf(standardDeviationOfLog, 1) {
+		return nil, fmt.Errorf("standard deviation of the logarithm must be greater than 0 and finite")
+	}
+
+	return &LogNormalDistribution{meanOfLog: meanOfLog, standardDeviationOfLog: standardDeviationOfLog}, nil
+}
+
+func (d *LogNormalDistribution) PDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+
+	z := (math.Log(x) - d.meanOfLog) / d.standardDeviationOfLog
+	return math.Exp(-z*z/2) / (x * d.standardDeviationOfLog * math.Sqrt(2*math.Pi))
+}
+
+func (d *LogNormalDistribution) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+
+	return standardNormalCDF((math.Log(x) - d.meanOfLog) / d.standardDeviationOfLog)
+}
+
+func (d *LogNormalDistribution) Quantile(p float64) float64 {
+	if probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	return math.Exp(d.meanOfLog + d.standardDeviationOfLog*standardNormalQuantile(p))
+}
+
+func (d *LogNormalDistribution) Mean() float64 {
+	return math.Exp(d.meanOfLog + d.standardDeviationOfLog*d.standardDeviationOfLog/2)
+}
+
+func (d *LogNormalDistribution) Variance() float64 {
+	varianceOfLog := d.standardDeviationOfLog * d.standardDeviationOfLog
+	return math.Expm1(varianceOfLog) * math.Exp(2*d.meanOfLog+varianceOfLog)
+}
+
+func (d *LogNormalDistribution) Rand(rng *rand.Rand) float64 {
+	return math.Exp(d.meanOfLog + d.standardDeviationOfLog*rng.NormFloat64())
+}
+
+// StudentTDistribution is Student's t-distribution.
+type StudentTDistribution struct {
+	degreesOfFreedom float64
+}
+
+// NewStudentTDistribution returns Student's t-distribution with the given degrees of freedom, which must be greater
+// than 0.
+func NewStudentTDistribution(degreesOfFreedom float64) (*StudentTDistribution, error) {
+	if !(degreesOfFreedom > 0) || math.IsInf(degreesOfFreedom, 1) {
+		return nil, fmt.Errorf("degrees of freedom must be greater than 0 and finite")
+	}
+
+	return &StudentTDistribution{degreesOfFreedom: degreesOfFreedom}, nil
+}
+
+func (d *StudentTDistribution) PDF(x float64) float64 {
+	v := d.degreesOfFreedom
+	return math.Exp(-(v+1)/2*math.Log1p(x*x/v)-LogBeta(0.5, v/2)) / math.Sqrt(v)
+}
+
+func (d *StudentTDistribution) CDF(x float64) float64 {
+	return studentTCDF(x, d.degreesOfFreedom)
+}
+
+func (d *StudentTDistribution) Quantile(p float64) float64 {
+	if probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	return studentTQuantile(p, d.degreesOfFreedom)
+}
+
+func (d *StudentTDistribution) Mean() float64 {
+	if d.degreesOfFreedom <= 1 {
+		return math.NaN()
+	}
+
+	return 0
+}
+
+func (d *StudentTDistribution) Variance() float64 {
+	switch {
+	case d.degreesOfFreedom <= 1:
+		return math.NaN()
+	case d.degreesOfFreedom <= 2:
+		return math.Inf(1)
+	}
+
+	return d.degreesOfFreedom / (d.degreesOfFreedom - 2)
+}
+
+func (d *StudentTDistribution) Rand(rng *rand.Rand) float64 {
+	return rng.NormFloat64() / math.Sqrt(2*gammaVariate(rng, d.degreesOfFreedom/2)/d.degreesOfFreedom)
+}
+
+// GammaDistribution is the gamma distribution, parameterized by shape and rate.  The scale is the reciprocal of the
+// rate.
+type GammaDistribution struct {
+	shape float64
+	rate  float64
+}
+
+// NewGammaDistribution returns the gamma distribution with the given shape and rate, both of which must be greater
+// than 0.
+func NewGammaDistribution(shape float64, rate float64) (*GammaDistribution, error) {
+	if !(shape > 0) || math.IsInf(shape, 1) {
+		return nil, fmt.Errorf("shape must be greater than 0 and finite")
+	}
+
+	if !(rate > 0) || math.IsInf(rate, 1) {
+		return nil, fmt.Errorf("rate must be greater than 0 and finite")
+	}
+
+	return &GammaDistribution{shape: shape, rate: rate}, nil
+}
+
+func (d *GammaDistribution) PDF(x float64) float64 {
+	if x < 0 {
+		return 0
+	}
+
+	if x == 0 {
+		switch {
+		case d.shape < 1:
+			return math.Inf(1)
+		case d.shape == 1:
+			return d.rate
+		}
+		return 0
+	}
+
+	lgammaShape, _ := math.Lgamma(d.shape)
+	return math.Exp(d.shape*math.Log(d.rate) + (d.shape-1)*math.Log(x) - d.rate*x - lgammaShape)
+}
+
+func (d *GammaDistribution) CDF(x float64) float64 {
+	return RegularizedLowerIncompleteGamma(d.shape, d.rate*x)
+}
+
+func (d *GammaDistribution) Quantile(p float64) float64 {
+	if probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	return InverseRegularizedLowerIncompleteGamma(d.shape, p) / d.rate
+}
+
+func (d *GammaDistribution) Mean() float64 {
+	return d.shape / d.rate
+}
+
+func (d *GammaDistribution) Variance() float64 {
+	return d.shape / (d.rate * d.rate)
+}
+
+func (d *GammaDistribution) Rand(rng *rand.Rand) float64 {
+	return gammaVariate(rng, d.shape) / d.rate
+}
+
+// ChiSquaredDistribution is the chi-squared distribution, which is the gamma distribution with shape k / 2 and
+// rate 1 / 2, where k is the degrees of freedom.
+type ChiSquaredDistribution struct {
+	GammaDistribution
+}
+
+// NewChiSquaredDistribution returns the chi-squared distribution with the given degrees of freedom, which must be
+// greater than 0.
+func NewChiSquaredDistribution(degreesOfFreedom float64) (*ChiSquaredDistribution, error) {
+	if !(degreesOfFreedom > 0) || math.IsInf(degreesOfFreedom, 1) {
+		return nil, fmt.Errorf("degrees of freedom must be greater than 0 and finite")
+	}
+
+	return &ChiSquaredDistribution{GammaDistribution{shape: degreesOfFreedom / 2, rate: 0.5}}, nil
+}
+
+// FDistribution is the F-distribution, the distribution of the ratio of two chi-squared variates, each divided by
+// its degrees of freedom.
+type FDistribution struct {
+	numeratorDegreesOfFreedom   float64
+	denominatorDegreesOfFreedom float64
+}
+
+// NewFDistribution returns the F-distribution with the given degrees of freedom, both of which must be greater
+// than 0.
+func NewFDistribution(numeratorDegreesOfFreedom float64, denominatorDegreesOfFreedom float64) (*FDistribution, error) {
+	if !(numeratorDegreesOfFreedom > 0) || math.IsInf(numeratorDegreesOfFreedom, 1) ||
+		!(denominatorDegreesOfFreedom > 0) || math.IsInf(denominatorDegreesOfFreedom, 1) {
+		return nil, fmt.Errorf("degrees of freedom must be greater than 0 and finite")
+	}
+
+	return &FDistribution{numeratorDegreesOfFreedom: numeratorDegreesOfFreedom, denominatorDegreesOfFreedom: denominatorDegreesOfFreedom}, nil
+}
+
+func (d *FDistribution) PDF(x float64) float64 {
+	d1, d2 := d.numeratorDegreesOfFreedom, d.denominatorDegreesOfFreedom
+
+	if x < 0 {
+		return 0
+	}
+
+	if x == 0 {
+		switch {
+		case d1 < 2:
+			return math.Inf(1)
+		case d1 == 2:
+			return 1
+		}
+		return 0
+	}
+
+	return math.Exp((d1*math.Log(d1*x)+d2*math.Log(d2)-(d1+d2)*math.Log(d1*x+d2))/2 - math.Log(x) - LogBeta(d1/2, d2/2))
+}
+
+func (d *FDistribution) CDF(x float64) float64 {
+	d1, d2 := d.numeratorDegreesOfFreedom, d.denominatorDegreesOfFreedom
+
+	if x <= 0 {
+		return 0
+	}
+
+	if math.IsInf(x, 1) {
+		return 1
+	}
+
+	return RegularizedIncompleteBeta(d1/2, d2/2, d1*x/(d1*x+d2))
+}
+
+func (d *FDistribution) Quantile(p float64) float64 {
+	d1, d2 := d.numeratorDegreesOfFreedom, d.denominatorDegreesOfFreedom
+
+	if probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	if p == 1 {
+		return math.Inf(1)
+	}
+
+	y := InverseRegularizedIncompleteBeta(d1/2, d2/2, p)
+	return d2 * y / (d1 * (1 - y))
+}
+
+func (d *FDistribution) Mean() float64 {
+	d2 := d.denominatorDegreesOfFreedom
+	if d2 <= 2 {
+		return math.NaN()
+	}
+
+	return d2 / (d2 - 2)
+}
+
+func (d *FDistribution) Variance() float64 {
+	d1, d2 := d.numeratorDegreesOfFreedom, d.denominatorDegreesOfFreedom
+
+	switch {
+	case d2 <= 2:
+		return math.NaN()
+	case d2 <= 4:
+		return math.Inf(1)
+	}
+
+	return 2 * d2 * d2 * (d1 + d2 - 2) / (d1 * (d2 - 2) * (d2 - 2) * (d2 - 4))
+}
+
+func (d *FDistribution) Rand(rng *rand.Rand) float64 {
+	d1, d2 := d.numeratorDegreesOfFreedom, d.denominatorDegreesOfFreedom
+	return (gammaVariate(rng, d1/2) / d1) / (gammaVariate(rng, d2/2) / d2)
+}
+
+// ExponentialDistribution is the exponential distribution with a given rate.
+type ExponentialDistribution struct {
+	rate float64
+}
+
+// NewExponentialDistribution returns the exponential distribution with the given rate, which must be greater than 0.
+func NewExponentialDistribution(rate float64) (*ExponentialDistribution, error) {
+	if !(rate > 0) || math.IsInf(rate, 1) {
+		return nil, fmt.Errorf("rate must be greater than 0 and finite")
+	}
+
+	return &ExponentialDistribution{rate: rate}, nil
+}
+
+func (d *ExponentialDistribution) PDF(x float64) float64 {
+	if x < 0 {
+		return 0
+	}
+
+	return d.rate * math.Exp(-d.rate*x)
+}
+
+func (d *ExponentialDistribution) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+
+	return -math.Expm1(-d.rate * x)
+}
+
+func (d *ExponentialDistribution) Quantile(p float64) float64 {
+	if probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	return -math.Log1p(-p) / d.rate
+}
+
+func (d *ExponentialDistribution) Mean() float64 {
+	return 1 / d.rate
+}
+
+func (d *ExponentialDistribution) Variance() float64 {
+	return 1 / (d.rate * d.rate)
+}
+
+func (d *ExponentialDistribution) Rand(rng *rand.Rand) float64 {
+	return rng.ExpFloat64() / d.rate
+}
+
+// BetaDistribution is the beta distribution on [0, 1].
+type BetaDistribution struct {
+	alpha float64
+	beta  float64
+}
+
+// NewBetaDistribution returns the beta distribution with the given shape parameters, both of which must be greater
+// than 0.
+func NewBetaDistribution(alpha float64, beta float64) (*BetaDistribution, error) {
+	if !(alpha > 0) || math.IsInf(alpha, 1) || !(beta > 0) || math.IsInf(beta, 1) {
+		return nil, fmt.Errorf("shape parameters must be greater than 0 and finite")
+	}
+
+	return &BetaDistribution{alpha: alpha, beta: beta}, nil
+}
+
+func (d *BetaDistribution) PDF(x float64) float64 {
+	if x < 0 || x > 1 {
+		return 0
+	}
+
+	if (x == 0 && d.alpha < 1) || (x == 1 && d.beta < 1) {
+		return math.Inf(1)
+	}
+
+	return math.Exp(xLogY(d.alpha-1, x) + xLog1pY(d.beta-1, -x) - LogBeta(d.alpha, d.beta))
+}
+
+func (d *BetaDistribution) CDF(x float64) float64 {
+	return RegularizedIncompleteBeta(d.alpha, d.beta, x)
+}
+
+func (d *BetaDistribution) Quantile(p float64) float64 {
+	if probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	return InverseRegularizedIncompleteBeta(d.alpha, d.beta, p)
+}
+
+func (d *BetaDistribution) Mean() float64 {
+	return d.alpha / (d.alpha + d.beta)
+}
+
+func (d *BetaDistribution) Variance() float64 {
+	sum := d.alpha + d.beta
+	return d.alpha * d.beta / (sum * sum * (sum + 1))
+}
+
+func (d *BetaDistribution) Rand(rng *rand.Rand) float64 {
+	return betaVariate(rng, d.alpha, d.beta)
+}
+
+// WeibullDistribution is the Weibull distribution, parameterized by shape and scale.
+type WeibullDistribution struct {
+	shape float64
+	scale float64
+}
+
+// NewWeibullDistribution returns the Weibull distribution with the given shape and scale, both of which must be
+// greater than 0.
+func NewWeibullDistribution(shape float64, scale float64) (*WeibullDistribution, error) {
+	if !(shape > 0) || math.IsInf(shape, 1) {
+		return nil, fmt.Errorf("shape must be greater than 0 and finite")
+	}
+
+	if !(scale > 0) || math.IsInf(scale, 1) {
+		return nil, fmt.Errorf("scale must be greater than 0 and finite")
+	}
+
+	return &WeibullDistribution{shape: shape, scale: scale}, nil
+}
+
+func (d *WeibullDistribution) PDF(x float64) float64 {
+	if x < 0 {
+		return 0
+	}
+
+	if x == 0 && d.shape < 1 {
+		return math.Inf(1)
+	}
+
+	z := x / d.scale
+	return d.shape / d.scale * math.Exp(xLogY(d.shape-1, z)-math.Pow(z, d.shape))
+}
+
+func (d *WeibullDistribution) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+
+	return -math.Expm1(-math.Pow(x/d.scale, d.shape))
+}
+
+func (d *WeibullDistribution) Quantile(p float64) float64 {
+	if probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	return d.scale * math.Pow(-math.Log1p(-p), 1/d.shape)
+}
+
+func (d *WeibullDistribution) Mean() float64 {
+	return d.scale * math.Gamma(1+1/d.shape)
+}
+
+func (d *WeibullDistribution) Variance() float64 {
+	g1 := math.Gamma(1 + 1/d.shape)
+	return d.scale * d.scale * (math.Gamma(1+2/d.shape) - g1*g1)
+}
+
+func (d *WeibullDistribution) Rand(rng *rand.Rand) float64 {
+	return d.scale * math.Pow(rng.ExpFloat64(), 1/d.shape)
+}
+
+// ParetoDistribution is the Pareto (type I) distribution, parameterized by scale, which is the smallest possible
+// value, and shape.
+type ParetoDistribution struct {
+	scale float64
+	shape float64
+}
+
+// NewParetoDistribution returns the Pareto distribution with the given scale and shape, both of which must be
+// greater than 0.
+func NewParetoDistribution(scale float64, shape float64) (*ParetoDistribution, error) {
+	if !(scale > 0) || math.IsInf(scale, 1) {
+		return nil, fmt.Errorf("scale must be greater than 0 and finite")
+	}
+
+	if !(shape > 0) || math.IsInf(shape, 1) {
+		return nil, fmt.Errorf("shape must be greater than 0 and finite")
+	}
+
+	return &ParetoDistribution{scale: scale, shape: shape}, nil
+}
+
+func (d *ParetoDistribution) PDF(x float64) float64 {
+	if x < d.scale {
+		return 0
+	}
+
+	return d.shape / x * math.Pow(d.scale/x, d.shape)
+}
+
+func (d *ParetoDistribution) CDF(x float64) float64 {
+	if x <= d.scale {
+		return 0
+	}
+
+	return -math.Expm1(d.shape * math.Log(d.scale/x))
+}
+
+func (d *ParetoDistribution) Quantile(p float64) float64 {
+	if probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	return d.scale * math.Exp(-math.Log1p(-p)/d.shape)
+}
+
+func (d *ParetoDistribution) Mean() float64 {
+	if d.shape <= 1 {
+		return math.Inf(1)
+	}
+
+	return d.shape * d.scale / (d.shape - 1)
+}
+
+func (d *ParetoDistribution) Variance() float64 {
+	if d.shape <= 2 {
+		return math.Inf(1)
+	}
+
+	return d.scale * d.scale * d.shape / ((d.shape - 1) * (d.shape - 1) * (d.shape - 2))
+}
+
+func (d *ParetoDistribution) Rand(rng *rand.Rand) float64 {
+	return d.scale * math.Exp(rng.ExpFloat64()/d.shape)
+}
+
+// UniformDistribution is the continuous uniform distribution on [minimum, maximum].
+type UniformDistribution struct {
+	minimum float64
+	maximum float64
+}
+
+// NewUniformDistribution returns the uniform distribution on [minimum, maximum].  The minimum must be less than
+// the maximum.
+func NewUniformDistribution(minimum float64, maximum float64) (*UniformDistribution, error) {
+	if math.IsInf(minimum, 0) || math.IsInf(maximum, 0) || !(minimum < maximum) {
+		return nil, fmt.Errorf("minimum must be less than maximum, and both must be finite")
+	}
+
+	return &UniformDistribution{minimum: minimum, maximum: maximum}, nil
+}
+
+func (d *UniformDistribution) PDF(x float64) float64 {
+	if x < d.minimum || x > d.maximum {
+		return 0
+	}
+
+	return 1 / (d.maximum - d.minimum)
+}
+
+func (d *UniformDistribution) CDF(x float64) float64 {
+	switch {
+	case x <= d.minimum:
+		return 0
+	case x >= d.maximum:
+		return 1
+	}
+
+	return (x - d.minimum) / (d.maximum - d.minimum)
+}
+
+func (d *UniformDistribution) Quantile(p float64) float64 {
+	if probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	return d.minimum + p*(d.maximum-d.minimum)
+}
+
+func (d *UniformDistribution) Mean() float64 {
+	return (d.minimum + d.maximum) / 2
+}
+
+func (d *UniformDistribution) Variance() float64 {
+	width := d.maximum - d.minimum
+	return width * width / 12
+}
+
+func (d *UniformDistribution) Rand(rng *rand.Rand) float64 {
+	return d.minimum + rng.Float64()*(d.maximum-d.minimum)
+}
+
+// xLogY returns x * log(y), treating 0 * log(0) as 0.
+func xLogY(x float64, y float64) float64 {
+	if x == 0 {
+		return 0
+	}
+
+	return x * math.Log(y)
+}
+
+// xLog1pY returns x * log(1 + y), treating 0 * log(0) as 0.
+func xLog1pY(x float64, y float64) float64 {
+	if x == 0 {
+		return 0
+	}
+
+	return x * math.Log1p(y)
+}
+
+// gammaVariate draws from the gamma distribution with the given shape and rate 1, using the method of Marsaglia
+// and Tsang (2000).  Shapes less than 1 are handled by drawing with shape + 1 and multiplying by U^(1 / shape).
+func gammaVariate(rng *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		return gammaVariate(rng, shape+1) * math.Pow(1-rng.Float64(), 1/shape)
+	}
+
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+
+		v = v * v * v
+		u := 1 - rng.Float64()
+
+		if u < 1-0.0331*x*x*x*x || math.Log(u) < x*x/2+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
+
+func betaVariate(rng *rand.Rand, alpha float64, beta float64) float64 {
+	x := gammaVariate(rng, alpha)
+	y := gammaVariate(rng, beta)
+
+	return x / (x + y)
+}
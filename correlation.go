@@ -0,0 +1,281 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// A CorrelationTestResult is the result of one of the correlation tests, which test whether x and y in a
+// PairedSampleSet are associated.  Coefficient is Pearson's r, Spearman's rho or Kendall's tau-b.  Statistic is the t
+// statistic, with DegreesOfFreedom degrees of freedom, for the Pearson and Spearman tests, and the z statistic (with
+// DegreesOfFreedom of zero) for the Kendall test.  The confidence interval for the coefficient is computed using
+// Fisher's z transformation; for one-sided alternatives, one of its bounds is -1 or 1.
+type CorrelationTestResult struct {
+	Coefficient        float64
+	Statistic          float64
+	DegreesOfFreedom   float64
+	PValue             float64
+	Alternative        HypothesisAlternative
+	ConfidenceInterval Interval
+}
+
+// PearsonCorrelation tests whether Pearson's product-moment correlation coefficient of the population from which set
+// is drawn differs from zero.  The test assumes that x and y are bivariate normal, or that the set is large.  There
+// must be at least four pairs, and neither x nor y may be constant.
+func PearsonCorrelation(set *PairedSampleSet, alternative HypothesisAlternative, confidenceLevel float64) (*CorrelationTestResult, error) {
+	if err := errorIfHypothesisTestParametersAreNotValid(alternative, confidenceLevel); err != nil {
+		return nil, err
+	}
+
+	if err := errorIfPairedSampleSetIsTooSmall(set, 4); err != nil {
+		return nil, err
+	}
+
+	sxx, syy, sxy := set.sumsOfSquaredDeviations()
+	r := clampCorrelationCoefficient(sxy / math.Sqrt(sxx*syy))
+	n := float64(set.Count())
+
+	return tTestOfCorrelationCoefficient(r, n, 1/math.Sqrt(n-3), alternative, confidenceLevel), nil
+}
+
+// SpearmanRankCorrelation tests whether Spearman's rank correlation coefficient, which is Pearson's r of the ranks of
+// x and y (with tied values given the mean of their ranks), differs from zero.  It detects any monotonic association
+// and is insensitive to outliers.  The p-value uses the t approximation, and the standard error of Fisher's z is that
+// of Fieller, Hartley and Pearson (1957).  There must be at least four pairs, and neither x nor y may be constant.
+func SpearmanRankCorrelation(set *PairedSampleSet, alternative HypothesisAlternative, confidenceLevel float64) (*CorrelationTestResult, error) {
+	if err := errorIfHypothesisTestParametersAreNotValid(alternative, confidenceLevel); err != nil {
+		return nil, err
+	}
+
+	if err := errorIfPairedSampleSetIsTooSmall(set, 4); err != nil {
+		return nil, err
+	}
+
+	rankedSet, err := MakePairedSampleSetFrom(midranksOf(set.xValues), midranksOf(set.yValues))
+	if err != nil {
+		return nil, err
+	}
+
+	sxx, syy, sxy := rankedSet.sumsOfSquaredDeviations()
+	rho := clampCorrelationCoefficient(sxy / math.Sqrt(sxx*syy))
+	n := float64(set.Count())
+
+	return tTestOfCorrelationCoefficient(rho, n, math.Sqrt(1.06/(n-3)), alternative, confidenceLevel), nil
+}
+
+// KendallTauB tests whether Kendall's tau-b, the difference between the number of concordant and discordant pairs of
+// pairs normalized for ties in x and in y, differs from zero.  The p-value uses the normal approximation with the
+// variance corrected for ties, and the standard error of Fisher's z is that of Fieller, Hartley and Pearson (1957).
+// The coefficient is computed in O(n log n) time using Knight's algorithm.  There must be at least five pairs, and
+// neither x nor y may be constant.
+func KendallTauB(set *PairedSampleSet, alternative HypothesisAlternative, confidenceLevel float64) (*CorrelationTestResult, error) {
+	if err := errorIfHypothesisTestParametersAreNotValid(alternative, confidenceLevel); err != nil {
+		return nil, err
+	}
+
+	if err := errorIfPairedSampleSetIsTooSmall(set, 5); err != nil {
+		return nil, err
+	}
+
+	count := set.Count()
+	n := float64(count)
+
+	indexes := make([]int, count)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		xi, xj := set.xValues[indexes[i]], set.xValues[indexes[j]]
+		return xi < xj || (xi == xj && set.yValues[indexes[i]] < set.yValues[indexes[j]])
+	})
+
+	xSortedByX, ySortedByX := make([]float64, count), make([]float64, count)
+	for i, index := range indexes {
+		xSortedByX[i], ySortedByX[i] = set.xValues[index], set.yValues[index]
+	}
+
+	pairsTiedInBoth := float64(0)
+	for start := 0; start < count; {
+		end := start + 1
+		for end < count && xSortedByX[end] == xSortedByX[start] && ySortedByX[end] == ySortedByX[start] {
+			end++
+		}
+		pairsTiedInBoth += float64((end - start) * (end - start - 1) / 2)
+		start = end
+	}
+
+	// after the y values are sorted, each exchange made by the merge sort was of a discordant pair
+	discordantPairs := float64(countExchangesInMergeSort(ySortedByX, make([]float64, count)))
+
+	xTies := tieStatisticsOfSortedValues(xSortedByX)
+	yTies := tieStatisticsOfSortedValues(ySortedByX)
+
+	totalPairs := n * (n - 1) / 2
+	concordantMinusDiscordant := totalPairs - xTies.tiedPairs - yTies.tiedPairs + pairsTiedInBoth - 2*discordantPairs
+
+	tau := clampCorrelationCoefficient(concordantMinusDiscordant / math.Sqrt((totalPairs-xTies.tiedPairs)*(totalPairs-yTies.tiedPairs)))
+
+	variance := (n*(n-1)*(2*n+5)-xTies.sumForVariance-yTies.sumForVariance)/18 +
+		(2*xTies.tiedPairs)*(2*yTies.tiedPairs)/(2*n*(n-1)) +
+		xTies.sumOfTriples*yTies.sumOfTriples/(9*n*(n-1)*(n-2))
+
+	z := concordantMinusDiscordant / math.Sqrt(variance)
+
+	result := &CorrelationTestResult{
+		Coefficient: tau,
+		Statistic:   z,
+		Alternative: alternative,
+	}
+
+	switch alternative {
+	case AlternativeTwoSided:
+		result.PValue = 2 * standardNormalCDF(-math.Abs(z))
+	case AlternativeLess:
+		result.PValue = standardNormalCDF(z)
+	case AlternativeGreater:
+		result.PValue = standardNormalCDF(-z)
+	}
+
+	result.ConfidenceInterval = fisherZConfidenceInterval(tau, math.Sqrt(0.437/(n-4)), alternative, confidenceLevel)
+
+	return result, nil
+}
+
+// tTestOfCorrelationCoefficient computes the result of testing that a correlation coefficient r, computed from n pairs,
+// differs from zero using the t statistic with n-2 degrees of freedom.  The standard error of Fisher's z of r is
+// standardErrorOfZ.
+func tTestOfCorrelationCoefficient(r float64, n float64, standardErrorOfZ float64, alternative HypothesisAlternative, confidenceLevel float64) *CorrelationTestResult {
+	degreesOfFreedom := n - 2
+	t := r * math.Sqrt(degreesOfFreedom/((1-r)*(1+r)))
+
+	result := &CorrelationTestResult{
+		Coefficient:      r,
+		Statistic:        t,
+		DegreesOfFreedom: degreesOfFreedom,
+		Alternative:      alternative,
+	}
+
+	switch alternative {
+	case AlternativeTwoSided:
+		result.PValue = 2 * studentTCDF(-math.Abs(t), degreesOfFreedom)
+	case AlternativeLess:
+		result.PValue = studentTCDF(t, degreesOfFreedom)
+	case AlternativeGreater:
+		result.PValue = studentTCDF(-t, degreesOfFreedom)
+	}
+
+	result.ConfidenceInterval = fisherZConfidenceInterval(r, standardErrorOfZ, alternative, confidenceLevel)
+
+	return result
+}
+
+// fisherZConfidenceInterval returns the confidence interval for a correlation coefficient obtained by transforming the
+// normal interval for its Fisher's z, atanh(r), back to the scale of the coefficient.
+func fisherZConfidenceInterval(r float64, standardErrorOfZ float64, alternative HypothesisAlternative, confidenceLevel float64) Interval {
+	z := math.Atanh(r)
+	interval := Interval{Lower: -1, Upper: 1, Level: confidenceLevel, Method: IntervalFisherZ}
+
+	switch alternative {
+	case AlternativeTwoSided:
+		margin := standardNormalQuantile((1+confidenceLevel)/2) * standardErrorOfZ
+		interval.Lower, interval.Upper = math.Tanh(z-margin), math.Tanh(z+margin)
+	case AlternativeLess:
+		interval.Upper = math.Tanh(z + standardNormalQuantile(confidenceLevel)*standardErrorOfZ)
+	case AlternativeGreater:
+		interval.Lower = math.Tanh(z - standardNormalQuantile(confidenceLevel)*standardErrorOfZ)
+	}
+
+	return interval
+}
+
+// clampCorrelationCoefficient limits r to [-1, 1], which it may exceed by rounding error.
+func clampCorrelationCoefficient(r float64) float64 {
+	return math.Max(-1, math.Min(1, r))
+}
+
+// midranksOf returns the rank of each of values, from 1, in the order of values.  Tied values are given the mean of
+// the ranks that they span.
+func midranksOf(values []float64) []float64 {
+	indexes := make([]int, len(values))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.Slice(indexes, func(i, j int) bool { return values[indexes[i]] < values[indexes[j]] })
+
+	ranks := make([]float64, len(values))
+	for start := 0; start < len(indexes); {
+		end := start + 1
+		for end < len(indexes) && values[indexes[end]] == values[indexes[start]] {
+			end++
+		}
+
+		midrank := float64(start+end+1) / 2
+		for _, index := range indexes[start:end] {
+			ranks[index] = midrank
+		}
+
+		start = end
+	}
+
+	return ranks
+}
+
+type tieStatistics struct {
+	// the number of pairs of tied values, sum(t(t-1)/2) over each group of t tied values
+	tiedPairs float64
+	// sum(t(t-1)(2t+5))
+	sumForVariance float64
+	// sum(t(t-1)(t-2))
+	sumOfTriples float64
+}
+
+func tieStatisticsOfSortedValues(sortedValues []float64) tieStatistics {
+	var statistics tieStatistics
+
+	for start := 0; start < len(sortedValues); {
+		end := start + 1
+		for end < len(sortedValues) && sortedValues[end] == sortedValues[start] {
+			end++
+		}
+
+		t := float64(end - start)
+		statistics.tiedPairs += t * (t - 1) / 2
+		statistics.sumForVariance += t * (t - 1) * (2*t + 5)
+		statistics.sumOfTriples += t * (t - 1) * (t - 2)
+
+		start = end
+	}
+
+	return statistics
+}
+
+// countExchangesInMergeSort sorts values in ascending order, using buffer (which must be as long as values) as scratch
+// space, and returns the number of pairs i < j for which values[i] > values[j] before sorting.
+func countExchangesInMergeSort(values []float64, buffer []float64) int {
+	if len(values) < 2 {
+		return 0
+	}
+
+	middle := len(values) / 2
+	exchanges := countExchangesInMergeSort(values[:middle], buffer[:middle]) + countExchangesInMergeSort(values[middle:], buffer[middle:])
+
+	left, right := values[:middle], values[middle:]
+	i, j, k := 0, 0, 0
+	for i < len(left) && j < len(right) {
+		if right[j] < left[i] {
+			buffer[k] = right[j]
+			exchanges += len(left) - i
+			j++
+		} else {
+			buffer[k] = left[i]
+			i++
+		}
+		k++
+	}
+
+	k += copy(buffer[k:], left[i:])
+	copy(buffer[k:], right[j:])
+	copy(values, buffer)
+
+	return exchanges
+}
@@ -0,0 +1,164 @@
+package stats_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+var correlationX = []float64{1, 2, 2, 3, 4, 5, 6, 7, 8, 9, 10, 10}
+var correlationY = []float64{3, 1, 4, 1, 5, 9, 2, 6, 5, 3, 5, 8}
+
+func TestCorrelationTests(t *testing.T) {
+	set, _ := stats.MakePairedSampleSetFrom(correlationX, correlationY)
+
+	for testIndex, testCase := range []struct {
+		name                string
+		test                func(set *stats.PairedSampleSet, alternative stats.HypothesisAlternative, level float64) (*stats.CorrelationTestResult, error)
+		expectedCoefficient float64
+		expectedStatistic   float64
+		expectedDF          float64
+		expectedPValue      float64
+		expectedLower       float64
+		expectedUpper       float64
+		expectedLessUpper   float64
+	}{
+		{"PearsonCorrelation", stats.PearsonCorrelation, 0.4623703163772516, 1.6489961592312166, 10, 0.13016435140633031, -0.15181660747609302, 0.8189574263647414, 0.7812640007877933},
+		{"SpearmanRankCorrelation", stats.SpearmanRankCorrelation, 0.5017856695332115, 1.8344512229826506, 10, 0.09647080493873539, -0.12035930262838426, 0.84092544770149, 0.8062372692450311},
+		{"KendallTauB", stats.KendallTauB, 0.36810602980707596, 1.605796369195214, 0, 0.10831865867128387, -0.07172872980122946, 0.6880868216435514, 0.6473162893880284},
+	} {
+		got, err := testCase.test(set, stats.AlternativeTwoSided, 0.95)
+		if err != nil {
+			t.Errorf("on test with index (%d) (%s) got unexpected error: %s", testIndex, testCase.name, err)
+			continue
+		}
+
+		if math.Abs(got.Coefficient-testCase.expectedCoefficient) > 1e-9 || math.Abs(got.Statistic-testCase.expectedStatistic) > 1e-9 || got.DegreesOfFreedom != testCase.expectedDF {
+			t.Errorf("on test with index (%d) (%s) expected Coefficient (%f), Statistic (%f) and DegreesOfFreedom (%f), got (%f), (%f) and (%f)", testIndex, testCase.name, testCase.expectedCoefficient, testCase.expectedStatistic, testCase.expectedDF, got.Coefficient, got.Statistic, got.DegreesOfFreedom)
+		}
+
+		if math.Abs(got.PValue-testCase.expectedPValue) > 1e-8 {
+			t.Errorf("on test with index (%d) (%s) expected PValue (%f), got (%f)", testIndex, testCase.name, testCase.expectedPValue, got.PValue)
+		}
+
+		if math.Abs(got.ConfidenceInterval.Lower-testCase.expectedLower) > 1e-8 || math.Abs(got.ConfidenceInterval.Upper-testCase.expectedUpper) > 1e-8 {
+			t.Errorf("on test with index (%d) (%s) expected interval [%f, %f], got %s", testIndex, testCase.name, testCase.expectedLower, testCase.expectedUpper, &got.ConfidenceInterval)
+		}
+
+		if got.ConfidenceInterval.Method != stats.IntervalFisherZ || got.ConfidenceInterval.Level != 0.95 {
+			t.Errorf("on test with index (%d) (%s) expected Method (Fisher's z) and Level (0.95), got (%s) and (%f)", testIndex, testCase.name, got.ConfidenceInterval.Method, got.ConfidenceInterval.Level)
+		}
+
+		less, _ := testCase.test(set, stats.AlternativeLess, 0.95)
+		greater, _ := testCase.test(set, stats.AlternativeGreater, 0.95)
+
+		if less.ConfidenceInterval.Lower != -1 || math.Abs(less.ConfidenceInterval.Upper-testCase.expectedLessUpper) > 1e-8 || greater.ConfidenceInterval.Upper != 1 {
+			t.Errorf("on test with index (%d) (%s) expected one-sided intervals [-1, %f] and [.., 1], got %s and %s", testIndex, testCase.name, testCase.expectedLessUpper, &less.ConfidenceInterval, &greater.ConfidenceInterval)
+		}
+
+		if math.Abs(less.PValue+greater.PValue-1) > 1e-12 || math.Abs(2*greater.PValue-got.PValue) > 1e-12 {
+			t.Errorf("on test with index (%d) (%s) expected one-sided p-values (%f, %f) to sum to 1 and be half of (%f)", testIndex, testCase.name, less.PValue, greater.PValue, got.PValue)
+		}
+	}
+}
+
+func TestPerfectCorrelation(t *testing.T) {
+	// y is a monotonic but non-linear function of x, so only the rank correlations are exactly -1
+	x := []float64{1, 2, 3, 4, 5, 6}
+	y := []float64{-1, -8, -27, -64, -125, -216}
+	set, _ := stats.MakePairedSampleSetFrom(x, y)
+
+	for testIndex, test := range []func(set *stats.PairedSampleSet, alternative stats.HypothesisAlternative, level float64) (*stats.CorrelationTestResult, error){
+		stats.SpearmanRankCorrelation,
+		stats.KendallTauB,
+	} {
+		got, err := test(set, stats.AlternativeTwoSided, 0.95)
+		if err != nil {
+			t.Errorf("on test with index (%d) got unexpected error: %s", testIndex, err)
+			continue
+		}
+
+		if got.Coefficient != -1 || got.ConfidenceInterval.Lower != -1 || got.ConfidenceInterval.Upper != -1 {
+			t.Errorf("on test with index (%d) expected Coefficient (-1) and interval [-1, -1], got (%f) and %s", testIndex, got.Coefficient, &got.ConfidenceInterval)
+		}
+	}
+
+	pearson, _ := stats.PearsonCorrelation(set, stats.AlternativeTwoSided, 0.95)
+	if !(pearson.Coefficient > -1 && pearson.Coefficient < -0.9) {
+		t.Errorf("expected Pearson Coefficient in (-1, -0.9), got (%f)", pearson.Coefficient)
+	}
+}
+
+func TestKendallTauBMatchesDefinition(t *testing.T) {
+	rng := rand.New(rand.NewSource(21))
+
+	for testIndex := 0; testIndex < 20; testIndex++ {
+		n := 5 + rng.Intn(60)
+		x, y := make([]float64, n), make([]float64, n)
+		for i := range x {
+			x[i], y[i] = float64(rng.Intn(8)), float64(rng.Intn(12))
+		}
+
+		set, _ := stats.MakePairedSampleSetFrom(x, y)
+		got, err := stats.KendallTauB(set, stats.AlternativeTwoSided, 0.95)
+		if err != nil {
+			t.Errorf("on test with index (%d) got unexpected error: %s", testIndex, err)
+			continue
+		}
+
+		concordantMinusDiscordant, untiedInX, untiedInY := 0.0, 0.0, 0.0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				signOfX, signOfY := sign(x[i]-x[j]), sign(y[i]-y[j])
+				concordantMinusDiscordant += signOfX * signOfY
+				untiedInX += signOfX * signOfX
+				untiedInY += signOfY * signOfY
+			}
+		}
+
+		if expected := concordantMinusDiscordant / math.Sqrt(untiedInX*untiedInY); math.Abs(got.Coefficient-expected) > 1e-12 {
+			t.Errorf("on test with index (%d) expected Coefficient (%f), got (%f)", testIndex, expected, got.Coefficient)
+		}
+	}
+}
+
+func sign(v float64) float64 {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+
+	return 0
+}
+
+func TestCorrelationErrors(t *testing.T) {
+	fourPairs, _ := stats.MakePairedSampleSetFrom([]float64{1, 2, 3, 4}, []float64{2, 1, 4, 3})
+	constantY, _ := stats.MakePairedSampleSetFrom([]float64{1, 2, 3, 4, 5}, []float64{7, 7, 7, 7, 7})
+	constantX, _ := stats.MakePairedSampleSetFrom([]float64{7, 7, 7, 7, 7}, []float64{1, 2, 3, 4, 5})
+
+	if _, err := stats.PearsonCorrelation(fourPairs, stats.AlternativeTwoSided, 0.95); err != nil {
+		t.Errorf("on PearsonCorrelation of four pairs got unexpected error: %s", err)
+	}
+
+	for testIndex, testCase := range []struct {
+		test        func(set *stats.PairedSampleSet, alternative stats.HypothesisAlternative, level float64) (*stats.CorrelationTestResult, error)
+		set         *stats.PairedSampleSet
+		alternative stats.HypothesisAlternative
+		level       float64
+	}{
+		{stats.KendallTauB, fourPairs, stats.AlternativeTwoSided, 0.95},
+		{stats.PearsonCorrelation, constantY, stats.AlternativeTwoSided, 0.95},
+		{stats.SpearmanRankCorrelation, constantX, stats.AlternativeTwoSided, 0.95},
+		{stats.KendallTauB, constantY, stats.AlternativeTwoSided, 0.95},
+		{stats.PearsonCorrelation, fourPairs, stats.HypothesisAlternative(7), 0.95},
+		{stats.SpearmanRankCorrelation, fourPairs, stats.AlternativeTwoSided, 1},
+	} {
+		if _, err := testCase.test(testCase.set, testCase.alternative, testCase.level); err == nil {
+			t.Errorf("on test with index (%d) expected error, got none", testIndex)
+		}
+	}
+}
@@ -0,0 +1,511 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// A NonlinearModel computes the value of a curve at x for the given parameters.  It must not modify parameters.
+type NonlinearModel func(x float64, parameters []float64) float64
+
+// A CurveFit is the least squares fit of a curve to the pairs in a PairedSampleSet.  Parameters are the fitted
+// parameters of the curve, and StandardErrors are their (for nonlinear curves, asymptotic) standard errors.
+// Residuals[i] is the observed minus the fitted value of the ith pair, in the order in which the pairs were supplied.
+// DegreesOfFreedom is the number of pairs less the number of parameters.  RSquared is 1 minus the ratio of the sum of
+// squared residuals to the sum of squared deviations of y from its mean; for a nonlinear curve, it is only a rough
+// guide to the quality of the fit.  Iterations is the number of iterations of the Levenberg–Marquardt algorithm, or
+// zero for curves that are fitted directly.
+type CurveFit struct {
+	Parameters            []float64
+	StandardErrors        []float64
+	Residuals             []float64
+	SumOfSquaredResiduals float64
+	ResidualStandardError float64
+	DegreesOfFreedom      float64
+	RSquared              float64
+	AdjustedRSquared      float64
+	Iterations            int
+
+	model NonlinearModel
+}
+
+// Evaluate returns the value of the fitted curve at x.
+func (fit *CurveFit) Evaluate(x float64) float64 {
+	return fit.model(x, fit.Parameters)
+}
+
+// ParameterConfidenceIntervals returns the two-sided confidence interval for each parameter, in the order of
+// Parameters, based on Student's t-distribution.  For nonlinear curves, the intervals rely on the curve being
+// approximately linear in its parameters near the fit.  The confidence level must be greater than 0 and less than 1.
+func (fit *CurveFit) ParameterConfidenceIntervals(level float64) ([]Interval, error) {
+	if err := errorIfConfidenceLevelIsNotValid(level); err != nil {
+		return nil, err
+	}
+
+	criticalValue := studentTQuantile((1+level)/2, fit.DegreesOfFreedom)
+
+	intervals := make([]Interval, len(fit.Parameters))
+	for j, parameter := range fit.Parameters {
+		margin := criticalValue * fit.StandardErrors[j]
+		intervals[j] = Interval{Lower: parameter - margin, Upper: parameter + margin, Level: level, Method: IntervalStudentT}
+	}
+
+	return intervals, nil
+}
+
+// FitPolynomial fits y = p[0] + p[1]*x + ... + p[degree]*x^degree by ordinary least squares (see
+// MultipleLinearRegression).  The degree must be at least 1, there must be more pairs than degree+1, and there must be
+// more distinct x values than degree.  High degrees are rarely justified.  To keep the fit well conditioned when x is
+// far from zero relative to its spread, the polynomial is fitted in z = (x - c) / s, where c and s are the midpoint and
+// half-width of the range of x, and its parameters and their standard errors are converted back to powers of x.
+func FitPolynomial(set *PairedSampleSet, degree int) (*CurveFit, error) {
+	if degree < 1 {
+		return nil, fmt.Errorf("degree must be at least 1")
+	}
+
+	if err := errorIfPairedSampleSetIsTooSmall(set, degree+2); err != nil {
+		return nil, err
+	}
+
+	distinctXValues := make(map[float64]bool)
+	minimumOfX, maximumOfX := math.Inf(1), math.Inf(-1)
+	for _, x := range set.xValues {
+		distinctXValues[x] = true
+		minimumOfX, maximumOfX = math.Min(minimumOfX, x), math.Max(maximumOfX, x)
+	}
+
+	if len(distinctXValues) <= degree {
+		return nil, fmt.Errorf("there are too few distinct x values for a polynomial of degree %d", degree)
+	}
+
+	center, scale := minimumOfX/2+maximumOfX/2, maximumOfX/2-minimumOfX/2
+
+	powers := make([][]float64, degree)
+	for d := range powers {
+		powers[d] = make([]float64, set.Count())
+		for i, x := range set.xValues {
+			powers[d][i] = math.Pow((x-center)/scale, float64(d+1))
+		}
+	}
+
+	predictors, err := MakeMatrixFromColumns(powers)
+	if err != nil {
+		return nil, err
+	}
+
+	regression, err := MultipleLinearRegression(predictors, nil, set.yValues)
+	if err != nil {
+		return nil, fmt.Errorf("on fitting a polynomial of degree %d: %w", degree, err)
+	}
+
+	fit := &CurveFit{
+		Parameters:            make([]float64, degree+1),
+		StandardErrors:        make([]float64, degree+1),
+		Residuals:             regression.Residuals,
+		ResidualStandardError: regression.ResidualStandardError,
+		DegreesOfFreedom:      regression.DegreesOfFreedom,
+		RSquared:              regression.RSquared,
+		AdjustedRSquared:      regression.AdjustedRSquared,
+		model:                 polynomialModel,
+	}
+
+	// z^j = sum_{i<=j} C(j, i) x^i (-c)^(j-i) / s^j, so the parameters are p = T q for the coefficients q of the powers
+	// of z, where T[i][j] = C(j, i) (-c)^(j-i) / s^j, and their covariance matrix is T Cov(q) T'
+	transformation := make([][]float64, degree+1)
+	for i := range transformation {
+		transformation[i] = make([]float64, degree+1)
+	}
+	for j := 0; j <= degree; j++ {
+		binomialCoefficient := float64(1)
+		for i := j; i >= 0; i-- {
+			transformation[i][j] = binomialCoefficient * math.Pow(-center, float64(j-i)) / math.Pow(scale, float64(j))
+			binomialCoefficient = binomialCoefficient * float64(i) / float64(j-i+1)
+		}
+	}
+
+	for i, row := range transformation {
+		variance := float64(0)
+		for j := range row {
+			fit.Parameters[i] += row[j] * regression.Coefficients[j].Estimate
+			for l := range row {
+				variance += row[j] * regression.CovarianceMatrix.At(j, l) * row[l]
+			}
+		}
+		fit.StandardErrors[i] = math.Sqrt(variance)
+	}
+
+	for _, residual := range fit.Residuals {
+		fit.SumOfSquaredResiduals += residual * residual
+	}
+
+	return fit, nil
+}
+
+func polynomialModel(x float64, parameters []float64) float64 {
+	value := float64(0)
+	for j := len(parameters) - 1; j >= 0; j-- {
+		value = value*x + parameters[j]
+	}
+
+	return value
+}
+
+// FitLogarithmic fits y = p[0] + p[1]*ln(x) by ordinary least squares of y on ln(x).  Every x value must be positive,
+// and there must be at least three pairs.
+func FitLogarithmic(set *PairedSampleSet) (*CurveFit, error) {
+	logOfX := make([]float64, set.Count())
+	for i, x := range set.xValues {
+		if !(x > 0) {
+			return nil, fmt.Errorf("x values must be positive")
+		}
+		logOfX[i] = math.Log(x)
+	}
+
+	transformedSet, err := MakePairedSampleSetFrom(logOfX, set.yValues)
+	if err != nil {
+		return nil, err
+	}
+
+	regression, err := LinearRegression(transformedSet)
+	if err != nil {
+		return nil, err
+	}
+
+	fit := &CurveFit{
+		Parameters:            []float64{regression.Intercept, regression.Slope},
+		StandardErrors:        []float64{regression.InterceptStandardError, regression.SlopeStandardError},
+		Residuals:             regression.Residuals,
+		ResidualStandardError: regression.ResidualStandardError,
+		DegreesOfFreedom:      regression.DegreesOfFreedom,
+		RSquared:              regression.RSquared,
+		AdjustedRSquared:      regression.AdjustedRSquared,
+		model:                 func(x float64, p []float64) float64 { return p[0] + p[1]*math.Log(x) },
+	}
+
+	for _, residual := range fit.Residuals {
+		fit.SumOfSquaredResiduals += residual * residual
+	}
+
+	return fit, nil
+}
+
+// FitExponential fits y = p[0]*exp(p[1]*x) by nonlinear least squares, starting from the least squares line of ln(y)
+// on x.  Unlike that line, the fit minimizes the squared residuals of y itself, so large values of y are not
+// discounted.  Every y value must be positive, and there must be at least three pairs.
+func FitExponential(set *PairedSampleSet) (*CurveFit, error) {
+	logOfY := make([]float64, set.Count())
+	for i, y := range set.yValues {
+		if !(y > 0) {
+			return nil, fmt.Errorf("y values must be positive")
+		}
+		logOfY[i] = math.Log(y)
+	}
+
+	initialParameters, err := parametersOfLogLinearFit(set.xValues, logOfY)
+	if err != nil {
+		return nil, err
+	}
+
+	return NonlinearLeastSquares(set, exponentialModel, initialParameters, NonlinearLeastSquaresOptions{Gradient: exponentialModelGradient})
+}
+
+func exponentialModel(x float64, p []float64) float64 {
+	return p[0] * math.Exp(p[1]*x)
+}
+
+func exponentialModelGradient(x float64, p []float64, gradient []float64) {
+	e := math.Exp(p[1] * x)
+	gradient[0], gradient[1] = e, p[0]*x*e
+}
+
+// FitPowerLaw fits y = p[0]*x^p[1] by nonlinear least squares, starting from the least squares line of ln(y) on
+// ln(x).  Every x and y value must be positive, and there must be at least three pairs.
+func FitPowerLaw(set *PairedSampleSet) (*CurveFit, error) {
+	logOfX, logOfY := make([]float64, set.Count()), make([]float64, set.Count())
+	for i := range set.xValues {
+		if !(set.xValues[i] > 0) || !(set.yValues[i] > 0) {
+			return nil, fmt.Errorf("x and y values must be positive")
+		}
+		logOfX[i], logOfY[i] = math.Log(set.xValues[i]), math.Log(set.yValues[i])
+	}
+
+	initialParameters, err := parametersOfLogLinearFit(logOfX, logOfY)
+	if err != nil {
+		return nil, err
+	}
+
+	return NonlinearLeastSquares(set, powerLawModel, initialParameters, NonlinearLeastSquaresOptions{Gradient: powerLawModelGradient})
+}
+
+func powerLawModel(x float64, p []float64) float64 {
+	return p[0] * math.Pow(x, p[1])
+}
+
+func powerLawModelGradient(x float64, p []float64, gradient []float64) {
+	power := math.Pow(x, p[1])
+	gradient[0], gradient[1] = power, p[0]*power*math.Log(x)
+}
+
+// parametersOfLogLinearFit returns [exp(a), b], where ln(y) = a + b*x is the least squares line of logOfY on x.
+func parametersOfLogLinearFit(x []float64, logOfY []float64) ([]float64, error) {
+	transformedSet, err := MakePairedSampleSetFrom(x, logOfY)
+	if err != nil {
+		return nil, err
+	}
+
+	regression, err := LinearRegression(transformedSet)
+	if err != nil {
+		return nil, err
+	}
+
+	return []float64{math.Exp(regression.Intercept), regression.Slope}, nil
+}
+
+// NonlinearLeastSquaresOptions control NonlinearLeastSquares.  Zero values select the defaults: at most 200
+// iterations, a relative tolerance of 1e-10, and a Jacobian computed by central differences.  If Gradient is provided,
+// it must set gradient[j] to the partial derivative of the model with respect to parameters[j] at x.
+type NonlinearLeastSquaresOptions struct {
+	MaximumIterations int
+	Tolerance         float64
+	Gradient          func(x float64, parameters []float64, gradient []float64)
+}
+
+const (
+	defaultMaximumIterationsOfNonlinearLeastSquares = 200
+	defaultToleranceOfNonlinearLeastSquares         = 1e-10
+
+	// beyond this damping, a step is so much shorter than the gradient step that failing to reduce the sum of squared
+	// residuals means the model is not smooth enough near the parameters to be fitted
+	maximumDampingOfNonlinearLeastSquares = 1e16
+)
+
+// NonlinearLeastSquares fits model to the pairs in set by minimizing the sum of squared residuals using the
+// Levenberg–Marquardt algorithm, starting from initialParameters (which are not modified).  Each step solves the
+// damped least squares problem by QR decomposition, with the damping scaled by the size of each column of the
+// Jacobian.  The fit has converged when a step changes the parameters by less than the tolerance relative to their
+// size.
+//
+// Like any local method, it finds the minimum nearest to the initial parameters, which should be chosen with care.
+// The standard errors are computed from the Jacobian at the fit.  There must be more pairs than parameters.  An error
+// is returned if the model is not finite at the initial parameters, if its Jacobian is not finite during the fit, if
+// it does not depend on some parameter, or if the fit does not converge within the maximum number of iterations.  Neither x nor y may be constant.
+func NonlinearLeastSquares(set *PairedSampleSet, model NonlinearModel, initialParameters []float64, options NonlinearLeastSquaresOptions) (*CurveFit, error) {
+	if model == nil {
+		return nil, fmt.Errorf("model must not be nil")
+	}
+
+	n, k := set.Count(), len(initialParameters)
+	if k == 0 {
+		return nil, fmt.Errorf("there must be at least one parameter")
+	}
+
+	if err := errorIfPairedSampleSetIsTooSmall(set, k+1); err != nil {
+		return nil, err
+	}
+
+	if options.MaximumIterations < 0 || options.Tolerance < 0 {
+		return nil, fmt.Errorf("maximum iterations and tolerance must not be negative")
+	}
+
+	if options.MaximumIterations == 0 {
+		options.MaximumIterations = defaultMaximumIterationsOfNonlinearLeastSquares
+	}
+
+	if options.Tolerance == 0 {
+		options.Tolerance = defaultToleranceOfNonlinearLeastSquares
+	}
+
+	parameters := make([]float64, k)
+	copy(parameters, initialParameters)
+
+	residuals, sumOfSquaredResiduals := residualsOfModel(set, model, parameters)
+	if math.IsNaN(sumOfSquaredResiduals) || math.IsInf(sumOfSquaredResiduals, 0) {
+		return nil, fmt.Errorf("model is not finite at the initial parameters")
+	}
+
+	fit := &CurveFit{model: model}
+	damping := 1e-3
+
+	for converged := false; !converged; {
+		if fit.Iterations == options.MaximumIterations {
+			return nil, fmt.Errorf("Levenberg-Marquardt did not converge in %d iterations", options.MaximumIterations)
+		}
+		fit.Iterations++
+
+		jacobian, err := jacobianOfModel(set, model, parameters, options.Gradient)
+		if err != nil {
+			return nil, err
+		}
+
+		columnScales := make([]float64, k)
+		largestColumnScale := float64(0)
+		for j := range columnScales {
+			for i := 0; i < n; i++ {
+				columnScales[j] = math.Hypot(columnScales[j], jacobian.values[i*k+j])
+			}
+			largestColumnScale = math.Max(largestColumnScale, columnScales[j])
+		}
+
+		// increase the damping until a step reduces the sum of squared residuals
+		for {
+			if damping > maximumDampingOfNonlinearLeastSquares {
+				return nil, fmt.Errorf("Levenberg-Marquardt did not converge: no step reduces the sum of squared residuals")
+			}
+
+			step, err := dampedLeastSquaresStep(jacobian, residuals, columnScales, largestColumnScale, damping)
+			if err != nil {
+				var dependentColumn *dependentColumnError
+				if errors.As(err, &dependentColumn) {
+					return nil, fmt.Errorf("the model does not depend on parameter %d", dependentColumn.column)
+				}
+				return nil, err
+			}
+
+			trialParameters := make([]float64, k)
+			sizeOfStep, sizeOfParameters := float64(0), float64(0)
+			for j := range trialParameters {
+				trialParameters[j] = parameters[j] + step[j]
+				sizeOfStep = math.Hypot(sizeOfStep, step[j])
+				sizeOfParameters = math.Hypot(sizeOfParameters, parameters[j])
+			}
+
+			if math.IsNaN(sizeOfStep) || math.IsInf(sizeOfStep, 0) {
+				return nil, fmt.Errorf("Levenberg-Marquardt step is not finite at parameters %v", parameters)
+			}
+
+			trialResiduals, trialSumOfSquaredResiduals := residualsOfModel(set, model, trialParameters)
+
+			if trialSumOfSquaredResiduals < sumOfSquaredResiduals {
+				converged = sizeOfStep <= options.Tolerance*(sizeOfParameters+options.Tolerance)
+
+				parameters, residuals, sumOfSquaredResiduals = trialParameters, trialResiduals, trialSumOfSquaredResiduals
+				damping /= 10
+				break
+			}
+
+			// even a negligible step does not reduce the sum of squared residuals, so the parameters are at a minimum
+			// (to within rounding error)
+			if sizeOfStep <= options.Tolerance*(sizeOfParameters+options.Tolerance) {
+				converged = true
+				break
+			}
+
+			damping *= 10
+		}
+	}
+
+	jacobian, err := jacobianOfModel(set, model, parameters, options.Gradient)
+	if err != nil {
+		return nil, err
+	}
+
+	decomposition, err := householderQR(jacobian)
+	if err != nil {
+		var dependentColumn *dependentColumnError
+		if errors.As(err, &dependentColumn) {
+			return nil, fmt.Errorf("the model does not depend on parameter %d at the fit", dependentColumn.column)
+		}
+		return nil, err
+	}
+
+	_, syy, _ := set.sumsOfSquaredDeviations()
+
+	fit.Parameters = parameters
+	fit.Residuals = residuals
+	fit.SumOfSquaredResiduals = sumOfSquaredResiduals
+	fit.DegreesOfFreedom = float64(n - k)
+	fit.ResidualStandardError = math.Sqrt(sumOfSquaredResiduals / fit.DegreesOfFreedom)
+	fit.RSquared = 1 - sumOfSquaredResiduals/syy
+	fit.AdjustedRSquared = 1 - (1-fit.RSquared)*float64(n-1)/fit.DegreesOfFreedom
+
+	inverseOfGramMatrix := decomposition.inverseOfGramMatrix()
+	fit.StandardErrors = make([]float64, k)
+	for j := range fit.StandardErrors {
+		fit.StandardErrors[j] = fit.ResidualStandardError * math.Sqrt(inverseOfGramMatrix.values[j*k+j])
+	}
+
+	return fit, nil
+}
+
+// dampedLeastSquaresStep returns the step that minimizes |J*step - residuals|² + damping*|D*step|², where D is the
+// diagonal matrix of columnScales (with zero scales replaced by a small fraction of the largest), by solving the
+// least squares problem of J stacked on sqrt(damping)*D.
+func dampedLeastSquaresStep(jacobian *Matrix, residuals []float64, columnScales []float64, largestColumnScale float64, damping float64) ([]float64, error) {
+	n, k := jacobian.rows, jacobian.columns
+
+	augmented := &Matrix{rows: n + k, columns: k, values: make([]float64, (n+k)*k)}
+	copy(augmented.values, jacobian.values)
+
+	for j, scale := range columnScales {
+		augmented.values[(n+j)*k+j] = math.Sqrt(damping) * math.Max(scale, 1e-8*largestColumnScale)
+	}
+
+	decomposition, err := householderQR(augmented)
+	if err != nil {
+		return nil, err
+	}
+
+	augmentedResiduals := make([]float64, n+k)
+	copy(augmentedResiduals, residuals)
+
+	return decomposition.solveLeastSquares(augmentedResiduals), nil
+}
+
+// residualsOfModel returns the residuals of the pairs in set from model with parameters, and their sum of squares.
+func residualsOfModel(set *PairedSampleSet, model NonlinearModel, parameters []float64) ([]float64, float64) {
+	residuals := make([]float64, set.Count())
+	sumOfSquares := float64(0)
+
+	for i, x := range set.xValues {
+		residuals[i] = set.yValues[i] - model(x, parameters)
+		sumOfSquares += residuals[i] * residuals[i]
+	}
+
+	return residuals, sumOfSquares
+}
+
+// jacobianOfModel returns the matrix of partial derivatives of model, with a row for each pair in set and a column for
+// each parameter, computed by gradient if it is not nil and otherwise by central differences.  An error is returned if
+// any partial derivative is not finite.
+func jacobianOfModel(set *PairedSampleSet, model NonlinearModel, parameters []float64, gradient func(x float64, parameters []float64, gradient []float64)) (*Matrix, error) {
+	n, k := set.Count(), len(parameters)
+	jacobian := &Matrix{rows: n, columns: k, values: make([]float64, n*k)}
+
+	if gradient != nil {
+		for i, x := range set.xValues {
+			gradient(x, parameters, jacobian.values[i*k:(i+1)*k])
+		}
+		return jacobian, errorIfJacobianIsNotFinite(jacobian, parameters)
+	}
+
+	shiftedParameters := make([]float64, k)
+	copy(shiftedParameters, parameters)
+
+	for j, parameter := range parameters {
+		step := math.Cbrt(2.220446049250313e-16) * math.Max(math.Abs(parameter), 1)
+
+		for i, x := range set.xValues {
+			shiftedParameters[j] = parameter + step
+			above := model(x, shiftedParameters)
+			shiftedParameters[j] = parameter - step
+			below := model(x, shiftedParameters)
+
+			jacobian.values[i*k+j] = (above - below) / (2 * step)
+		}
+
+		shiftedParameters[j] = parameter
+	}
+
+	return jacobian, errorIfJacobianIsNotFinite(jacobian, parameters)
+}
+
+func errorIfJacobianIsNotFinite(jacobian *Matrix, parameters []float64) error {
+	for _, v := range jacobian.values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("the Jacobian of the model is not finite at parameters %v", parameters)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,300 @@
+package stats_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+// roughly y = 2*exp(0.3x), as in steady percentage growth
+var curveX = []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+var curveY = []float64{2.7, 3.6, 4.9, 6.6, 9.1, 12.0, 16.5, 22.4, 29.8, 40.6}
+
+// the Puromycin (treated) data of Bates and Watts, fitted by the Michaelis–Menten model rate = Vm*conc/(K + conc)
+var puromycinConcentration = []float64{0.02, 0.02, 0.06, 0.06, 0.11, 0.11, 0.22, 0.22, 0.56, 0.56, 1.10, 1.10}
+var puromycinRate = []float64{76, 47, 97, 107, 123, 139, 159, 152, 191, 201, 207, 200}
+
+func michaelisMenten(x float64, parameters []float64) float64 {
+	return parameters[0] * x / (parameters[1] + x)
+}
+
+func TestCurveFits(t *testing.T) {
+	set, _ := stats.MakePairedSampleSetFrom(curveX, curveY)
+
+	polynomial, err := stats.FitPolynomial(set, 2)
+	if err != nil {
+		t.Fatalf("on FitPolynomial got unexpected error: %s", err)
+	}
+
+	logarithmic, err := stats.FitLogarithmic(set)
+	if err != nil {
+		t.Fatalf("on FitLogarithmic got unexpected error: %s", err)
+	}
+
+	exponential, err := stats.FitExponential(set)
+	if err != nil {
+		t.Fatalf("on FitExponential got unexpected error: %s", err)
+	}
+
+	powerLaw, err := stats.FitPowerLaw(set)
+	if err != nil {
+		t.Fatalf("on FitPowerLaw got unexpected error: %s", err)
+	}
+
+	for testIndex, testCase := range []struct {
+		name                          string
+		got                           *stats.CurveFit
+		expectedParameters            []float64
+		expectedStandardErrors        []float64
+		expectedSumOfSquaredResiduals float64
+		expectedRSquared              float64
+		expectedFirstResidual         float64
+	}{
+		{"FitPolynomial", polynomial, []float64{5.466666666666667, -2.16, 0.5515151515151515}, []float64{1.3333435064546968, 0.5568604601553976, 0.049335742413285165}, 8.996121212121212, 0.993703352372255, -1.1581818181818182},
+		{"FitLogarithmic", logarithmic, []float64{-6.099454386989086, 13.84989603917415}, []float64{5.984447336168721, 3.598939673636627}, 501.0924503173981, 0.6492707785750296, 8.799454386989087},
+		{"FitExponential", exponential, []float64{1.9935820606751653, 0.3012670918638086}, []float64{0.024223553094878513, 0.0013574117800389976}, 0.1298303790390272, 0.9999091279309261, 0.005533722573549671},
+		{"FitPowerLaw", powerLaw, []float64{0.2535134244827257, 2.183989655306128}, []float64{0.09154666392846218, 0.16646976351386344}, 27.07703220398207, 0.981047995400078, 2.4464865755172744},
+	} {
+		got := testCase.got
+
+		if len(got.Parameters) != len(testCase.expectedParameters) || len(got.StandardErrors) != len(testCase.expectedParameters) {
+			t.Errorf("on test with index (%d) (%s) expected (%d) Parameters and StandardErrors, got (%d) and (%d)", testIndex, testCase.name, len(testCase.expectedParameters), len(got.Parameters), len(got.StandardErrors))
+			continue
+		}
+
+		for j := range got.Parameters {
+			if math.Abs(got.Parameters[j]-testCase.expectedParameters[j]) > 1e-8*math.Abs(testCase.expectedParameters[j]) {
+				t.Errorf("on test with index (%d) (%s) expected parameter %d (%f), got (%f)", testIndex, testCase.name, j, testCase.expectedParameters[j], got.Parameters[j])
+			}
+			if math.Abs(got.StandardErrors[j]-testCase.expectedStandardErrors[j]) > 1e-6*testCase.expectedStandardErrors[j] {
+				t.Errorf("on test with index (%d) (%s) expected standard error %d (%f), got (%f)", testIndex, testCase.name, j, testCase.expectedStandardErrors[j], got.StandardErrors[j])
+			}
+		}
+
+		if math.Abs(got.SumOfSquaredResiduals-testCase.expectedSumOfSquaredResiduals) > 1e-8*testCase.expectedSumOfSquaredResiduals {
+			t.Errorf("on test with index (%d) (%s) expected SumOfSquaredResiduals (%f), got (%f)", testIndex, testCase.name, testCase.expectedSumOfSquaredResiduals, got.SumOfSquaredResiduals)
+		}
+
+		if math.Abs(got.ResidualStandardError-math.Sqrt(testCase.expectedSumOfSquaredResiduals/got.DegreesOfFreedom)) > 1e-8 {
+			t.Errorf("on test with index (%d) (%s) expected ResidualStandardError (%f), got (%f)", testIndex, testCase.name, math.Sqrt(testCase.expectedSumOfSquaredResiduals/got.DegreesOfFreedom), got.ResidualStandardError)
+		}
+
+		if math.Abs(got.RSquared-testCase.expectedRSquared) > 1e-10 {
+			t.Errorf("on test with index (%d) (%s) expected RSquared (%f), got (%f)", testIndex, testCase.name, testCase.expectedRSquared, got.RSquared)
+		}
+
+		if got.DegreesOfFreedom != float64(len(curveX)-len(testCase.expectedParameters)) {
+			t.Errorf("on test with index (%d) (%s) expected DegreesOfFreedom (%d), got (%f)", testIndex, testCase.name, len(curveX)-len(testCase.expectedParameters), got.DegreesOfFreedom)
+		}
+
+		if math.Abs(got.Residuals[0]-testCase.expectedFirstResidual) > 1e-6 {
+			t.Errorf("on test with index (%d) (%s) expected first residual (%f), got (%f)", testIndex, testCase.name, testCase.expectedFirstResidual, got.Residuals[0])
+		}
+
+		if math.Abs(got.Evaluate(curveX[0])-(curveY[0]-got.Residuals[0])) > 1e-10 {
+			t.Errorf("on test with index (%d) (%s) expected Evaluate(%f) = (%f), got (%f)", testIndex, testCase.name, curveX[0], curveY[0]-got.Residuals[0], got.Evaluate(curveX[0]))
+		}
+	}
+
+	if polynomial.Iterations != 0 || logarithmic.Iterations != 0 || exponential.Iterations == 0 || powerLaw.Iterations == 0 {
+		t.Errorf("expected Iterations only for FitExponential and FitPowerLaw, got (%d), (%d), (%d) and (%d)", polynomial.Iterations, logarithmic.Iterations, exponential.Iterations, powerLaw.Iterations)
+	}
+}
+
+func TestFitPolynomialOfDegreeOneMatchesLinearRegression(t *testing.T) {
+	set, _ := stats.MakePairedSampleSetFrom(regressionX, regressionY)
+
+	line, _ := stats.LinearRegression(set)
+	polynomial, err := stats.FitPolynomial(set, 1)
+	if err != nil {
+		t.Fatalf("on FitPolynomial got unexpected error: %s", err)
+	}
+
+	if math.Abs(polynomial.Parameters[0]-line.Intercept) > 1e-10 || math.Abs(polynomial.Parameters[1]-line.Slope) > 1e-10 {
+		t.Errorf("expected Parameters (%f, %f), got (%f, %f)", line.Intercept, line.Slope, polynomial.Parameters[0], polynomial.Parameters[1])
+	}
+
+	if math.Abs(polynomial.StandardErrors[0]-line.InterceptStandardError) > 1e-10 || math.Abs(polynomial.StandardErrors[1]-line.SlopeStandardError) > 1e-10 {
+		t.Errorf("expected StandardErrors (%f, %f), got (%f, %f)", line.InterceptStandardError, line.SlopeStandardError, polynomial.StandardErrors[0], polynomial.StandardErrors[1])
+	}
+
+	if math.Abs(polynomial.AdjustedRSquared-line.AdjustedRSquared) > 1e-12 {
+		t.Errorf("expected AdjustedRSquared (%f), got (%f)", line.AdjustedRSquared, polynomial.AdjustedRSquared)
+	}
+}
+
+func TestFitPolynomialOfXFarFromZero(t *testing.T) {
+	set, _ := stats.MakePairedSampleSetFrom(curveX, curveY)
+
+	shiftedX := make([]float64, len(curveX))
+	for i, x := range curveX {
+		shiftedX[i] = x + 1e6
+	}
+	shifted, _ := stats.MakePairedSampleSetFrom(shiftedX, curveY)
+
+	fit, _ := stats.FitPolynomial(set, 2)
+	shiftedFit, err := stats.FitPolynomial(shifted, 2)
+	if err != nil {
+		t.Fatalf("on FitPolynomial of x shifted by 1e6 got unexpected error: %s", err)
+	}
+
+	// shifting x changes every parameter but the coefficient of the highest power, and none of the residuals
+	if relativeError(shiftedFit.Parameters[2], fit.Parameters[2]) > 1e-9 || relativeError(shiftedFit.StandardErrors[2], fit.StandardErrors[2]) > 1e-9 {
+		t.Errorf("expected Parameters[2] (%f) and StandardErrors[2] (%f), got (%f) and (%f)", fit.Parameters[2], fit.StandardErrors[2], shiftedFit.Parameters[2], shiftedFit.StandardErrors[2])
+	}
+
+	for i, residual := range fit.Residuals {
+		if math.Abs(shiftedFit.Residuals[i]-residual) > 1e-9 {
+			t.Errorf("expected Residuals %v, got %v", fit.Residuals, shiftedFit.Residuals)
+			break
+		}
+	}
+
+	// Parameters[1] + 2 Parameters[2] x is the slope at x, which does not depend on the shift
+	if slope := shiftedFit.Parameters[1] + 2*shiftedFit.Parameters[2]*1e6; math.Abs(slope-fit.Parameters[1]) > 1e-6 {
+		t.Errorf("expected slope at 1e6 (%f), got (%f)", fit.Parameters[1], slope)
+	}
+}
+
+func TestFitPolynomialWrapsRegressionErrors(t *testing.T) {
+	// 0 and 5e-324 are distinct, but are too close to fit a polynomial of degree 4 through the five distinct x values
+	set, _ := stats.MakePairedSampleSetFrom([]float64{0, 5e-324, 1, 1, 2, 2, 3, 3}, []float64{1, 2, 3, 5, 4, 6, 2, 1})
+
+	_, err := stats.FitPolynomial(set, 4)
+	if err == nil || errors.Unwrap(err) == nil {
+		t.Errorf("expected a wrapped error from MultipleLinearRegression, got (%v)", err)
+	}
+}
+
+func TestNonlinearLeastSquares(t *testing.T) {
+	set, _ := stats.MakePairedSampleSetFrom(puromycinConcentration, puromycinRate)
+	initialParameters := []float64{200, 0.1}
+
+	fit, err := stats.NonlinearLeastSquares(set, michaelisMenten, initialParameters, stats.NonlinearLeastSquaresOptions{})
+	if err != nil {
+		t.Fatalf("on NonlinearLeastSquares got unexpected error: %s", err)
+	}
+
+	for j, expected := range []struct {
+		parameter     float64
+		standardError float64
+		interval      stats.Interval
+	}{
+		{212.68374314253606, 6.947155160040721, stats.Interval{Lower: 197.20451681982064, Upper: 228.16296946525148}},
+		{0.06412128168156705, 0.00828094949848035, stats.Interval{}},
+	} {
+		if math.Abs(fit.Parameters[j]-expected.parameter) > 1e-7*expected.parameter {
+			t.Errorf("expected parameter %d (%f), got (%f)", j, expected.parameter, fit.Parameters[j])
+		}
+
+		// the Jacobian is computed by central differences
+		if math.Abs(fit.StandardErrors[j]-expected.standardError) > 1e-6*expected.standardError {
+			t.Errorf("expected standard error %d (%f), got (%f)", j, expected.standardError, fit.StandardErrors[j])
+		}
+	}
+
+	if math.Abs(fit.SumOfSquaredResiduals-1195.4488144393595) > 1e-6 || math.Abs(fit.ResidualStandardError-10.933658191288767) > 1e-8 {
+		t.Errorf("expected SumOfSquaredResiduals and ResidualStandardError (1195.448814, 10.933658), got (%f, %f)", fit.SumOfSquaredResiduals, fit.ResidualStandardError)
+	}
+
+	if initialParameters[0] != 200 || initialParameters[1] != 0.1 {
+		t.Errorf("expected initial parameters to be unchanged, got %v", initialParameters)
+	}
+
+	intervals, err := fit.ParameterConfidenceIntervals(0.95)
+	if err != nil {
+		t.Fatalf("on ParameterConfidenceIntervals got unexpected error: %s", err)
+	}
+
+	if math.Abs(intervals[0].Lower-197.20451681982064) > 1e-4 || math.Abs(intervals[0].Upper-228.16296946525148) > 1e-4 || intervals[0].Method != stats.IntervalStudentT || intervals[0].Level != 0.95 {
+		t.Errorf("expected interval for parameter 0 (197.204517, 228.162969), got %s", &intervals[0])
+	}
+
+	if _, err := fit.ParameterConfidenceIntervals(1); err == nil {
+		t.Errorf("on confidence level (1) expected error, got none")
+	}
+
+	withGradient, err := stats.NonlinearLeastSquares(set, michaelisMenten, initialParameters, stats.NonlinearLeastSquaresOptions{
+		Gradient: func(x float64, p []float64, gradient []float64) {
+			gradient[0], gradient[1] = x/(p[1]+x), -p[0]*x/((p[1]+x)*(p[1]+x))
+		},
+	})
+	if err != nil {
+		t.Fatalf("on NonlinearLeastSquares with Gradient got unexpected error: %s", err)
+	}
+
+	if math.Abs(withGradient.Parameters[1]-fit.Parameters[1]) > 1e-8 || math.Abs(withGradient.StandardErrors[1]-0.00828094949848035) > 1e-12 {
+		t.Errorf("with Gradient expected parameter 1 (%f) and standard error (0.008281), got (%f) and (%f)", fit.Parameters[1], withGradient.Parameters[1], withGradient.StandardErrors[1])
+	}
+}
+
+func TestNonlinearLeastSquaresOfModelsThatAreNotFinite(t *testing.T) {
+	set, _ := stats.MakePairedSampleSetFrom(curveX, curveY)
+
+	for testIndex, testCase := range []struct {
+		name              string
+		model             stats.NonlinearModel
+		initialParameters []float64
+		options           stats.NonlinearLeastSquaresOptions
+	}{
+		// the central difference at 0 is NaN
+		{"sqrt(p) x from 0", func(x float64, p []float64) float64 { return math.Sqrt(p[0]) * x }, []float64{0}, stats.NonlinearLeastSquaresOptions{MaximumIterations: 5}},
+		// every step is rejected, and the tolerance is too small for any step to be negligible
+		{"NaN away from the initial parameters", func(x float64, p []float64) float64 {
+			if p[0] != 1 {
+				return math.NaN()
+			}
+			return x
+		}, []float64{1}, stats.NonlinearLeastSquaresOptions{
+			Tolerance: 1e-300,
+			Gradient:  func(x float64, p []float64, gradient []float64) { gradient[0] = x },
+		}},
+	} {
+		if _, err := stats.NonlinearLeastSquares(set, testCase.model, testCase.initialParameters, testCase.options); err == nil {
+			t.Errorf("on test with index (%d) (%s) expected error, got none", testIndex, testCase.name)
+		}
+	}
+}
+
+func TestCurveFittingErrors(t *testing.T) {
+	set, _ := stats.MakePairedSampleSetFrom(curveX, curveY)
+	threeDistinctX, _ := stats.MakePairedSampleSetFrom([]float64{1, 1, 2, 2, 3, 3, 3}, []float64{1, 2, 3, 4, 5, 6, 7})
+	withZeros, _ := stats.MakePairedSampleSetFrom([]float64{0, 1, 2, 3}, []float64{0, 1, 2, 3})
+	puromycin, _ := stats.MakePairedSampleSetFrom(puromycinConcentration, puromycinRate)
+
+	for testIndex, testCase := range []struct {
+		name string
+		fit  func() (*stats.CurveFit, error)
+	}{
+		{"FitPolynomial of degree 0", func() (*stats.CurveFit, error) { return stats.FitPolynomial(set, 0) }},
+		{"FitPolynomial of degree 9 to 10 pairs", func() (*stats.CurveFit, error) { return stats.FitPolynomial(set, 9) }},
+		{"FitPolynomial of degree 3 to 3 distinct x", func() (*stats.CurveFit, error) { return stats.FitPolynomial(threeDistinctX, 3) }},
+		{"FitLogarithmic with x of 0", func() (*stats.CurveFit, error) { return stats.FitLogarithmic(withZeros) }},
+		{"FitExponential with y of 0", func() (*stats.CurveFit, error) { return stats.FitExponential(withZeros) }},
+		{"FitPowerLaw with x and y of 0", func() (*stats.CurveFit, error) { return stats.FitPowerLaw(withZeros) }},
+		{"NonlinearLeastSquares with nil model", func() (*stats.CurveFit, error) {
+			return stats.NonlinearLeastSquares(puromycin, nil, []float64{200, 0.1}, stats.NonlinearLeastSquaresOptions{})
+		}},
+		{"NonlinearLeastSquares with no parameters", func() (*stats.CurveFit, error) {
+			return stats.NonlinearLeastSquares(puromycin, michaelisMenten, nil, stats.NonlinearLeastSquaresOptions{})
+		}},
+		{"NonlinearLeastSquares with negative tolerance", func() (*stats.CurveFit, error) {
+			return stats.NonlinearLeastSquares(puromycin, michaelisMenten, []float64{200, 0.1}, stats.NonlinearLeastSquaresOptions{Tolerance: -1})
+		}},
+		{"NonlinearLeastSquares not finite at the initial parameters", func() (*stats.CurveFit, error) {
+			return stats.NonlinearLeastSquares(puromycin, michaelisMenten, []float64{200, -0.02}, stats.NonlinearLeastSquaresOptions{})
+		}},
+		{"NonlinearLeastSquares of a model that ignores a parameter", func() (*stats.CurveFit, error) {
+			return stats.NonlinearLeastSquares(puromycin, func(x float64, p []float64) float64 { return p[0] * x }, []float64{200, 0.1}, stats.NonlinearLeastSquaresOptions{})
+		}},
+		{"NonlinearLeastSquares that does not converge", func() (*stats.CurveFit, error) {
+			return stats.NonlinearLeastSquares(puromycin, michaelisMenten, []float64{200, 0.1}, stats.NonlinearLeastSquaresOptions{MaximumIterations: 1})
+		}},
+	} {
+		if _, err := testCase.fit(); err == nil {
+			t.Errorf("on test with index (%d) (%s) expected error, got none", testIndex, testCase.name)
+		}
+	}
+}
@@ -0,0 +1,724 @@
+package stats
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+var ErrorIncompatibleSketches = errors.New("sketches have different relative accuracies")
+
+// A DDSketch estimates quantiles with a relative-error guarantee, as described by Masson, Rim and Lee in "DDSketch:
+// A Fast and Fully-Mergeable Quantile Sketch with Relative-Error Guarantees".  A value x is mapped to the bucket
+// ceil(log(|x|) / log(gamma)), where gamma = (1 + a) / (1 - a) for relative accuracy a, so that any quantile
+// returned is within a * |x| of the value x at that quantile.  Positive and negative values are counted in separate
+// stores, and values too close to zero to be mapped are counted in a zero bucket.
+type DDSketch struct {
+	mutex                     sync.Mutex
+	relativeAccuracy          float64
+	gamma                     float64
+	logGamma                  float64
+	minimumIndexableValue     float64
+	storeKind                 ddSketchStoreKind
+	maximumNumberOfBins       int
+	positiveValueStore        ddSketchStore
+	negativeValueStore        ddSketchStore
+	countOfValuesInZeroBucket float64
+	sumOfAllValuesAdded       float64
+	minimumValueAdded         float64
+	maximumValueAdded         float64
+}
+
+type ddSketchStoreKind byte
+
+const (
+	ddSketchUnboundedDenseStore ddSketchStoreKind = iota
+	ddSketchCollapsingLowestDenseStore
+	ddSketchSparseStore
+)
+
+// NewDDSketch creates an empty sketch with the supplied relative accuracy (in the range 0..1, exclusive), backed by
+// dense stores that grow to cover the full range of values added.
+func NewDDSketch(relativeAccuracy float64) (*DDSketch, error) {
+	return newDDSketch(relativeAccuracy, ddSketchUnboundedDenseStore, 0)
+}
+
+// NewCollapsingDDSketch creates an empty sketch whose dense stores each hold at most maximumNumberOfBins buckets.
+// When a store would exceed that number, its lowest buckets are collapsed together, so the relative accuracy
+// guarantee holds for the higher quantiles of the magnitude of values but may not hold for the lowest.
+func NewCollapsingDDSketch(relativeAccuracy float64, maximumNumberOfBins int) (*DDSketch, error) {
+	if maximumNumberOfBins < 1 {
+		return nil, fmt.Errorf("maximum number of bins must be at least 1")
+	}
+
+	return newDDSketch(relativeAccuracy, ddSketchCollapsingLowestDenseStore, maximumNumberOfBins)
+}
+
+// NewSparseDDSketch creates an empty sketch backed by sparse stores, which use memory only for non-empty buckets.
+// This is preferable to the dense stores when values are spread thinly over a very wide range.
+func NewSparseDDSketch(relativeAccuracy float64) (*DDSketch, error) {
+	return newDDSketch(relativeAccuracy, ddSketchSparseStore, 0)
+}
+
+func newDDSketch(relativeAccuracy float64, storeKind ddSketchStoreKind, maximumNumberOfBins int) (*DDSketch, error) {
+	if !(relativeAccuracy > 0 && relativeAccuracy < 1) {
+		return nil, fmt.Errorf("relative accuracy must be greater than 0 and less than 1")
+	}
+
+	gamma := (1 + relativeAccuracy) / (1 - relativeAccuracy)
+	logGamma := math.Log(gamma)
+
+	return &DDSketch{
+		relativeAccuracy:      relativeAccuracy,
+		gamma:                 gamma,
+		logGamma:              logGamma,
+		minimumIndexableValue: math.Max(math.Exp(float64(math.MinInt32+1)*logGamma), 2.2250738585072014e-308*gamma),
+		storeKind:             storeKind,
+		maximumNumberOfBins:   maximumNumberOfBins,
+		positiveValueStore:    newDDSketchStore(storeKind, maximumNumberOfBins),
+		negativeValueStore:    newDDSketchStore(storeKind, maximumNumberOfBins),
+		minimumValueAdded:     math.Inf(1),
+		maximumValueAdded:     math.Inf(-1),
+	}, nil
+}
+
+// RelativeAccuracy returns the relative accuracy with which the sketch was created.
+func (sketch *DDSketch) RelativeAccuracy() float64 {
+	return sketch.relativeAccuracy
+}
+
+// Add adds a value to the sketch.  NaN and infinite values are ignored.
+func (sketch *DDSketch) Add(value float64) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return
+	}
+
+	sketch.mutex.Lock()
+	defer sketch.mutex.Unlock()
+
+	switch {
+	case value > sketch.minimumIndexableValue:
+		sketch.positiveValueStore.add(sketch.indexFor(value), 1)
+	case value < -sketch.minimumIndexableValue:
+		sketch.negativeValueStore.add(sketch.indexFor(-value), 1)
+	default:
+		sketch.countOfValuesInZeroBucket++
+	}
+
+	sketch.sumOfAllValuesAdded += value
+	sketch.minimumValueAdded = math.Min(sketch.minimumValueAdded, value)
+	sketch.maximumValueAdded = math.Max(sketch.maximumValueAdded, value)
+}
+
+// AddMany adds each of the provided values to the sketch.
+func (sketch *DDSketch) AddMany(values []float64) {
+	for _, v := range values {
+		sketch.Add(v)
+	}
+}
+
+// Merge adds all of the values summarized by another sketch to this one.  The other sketch is unchanged.  The
+// sketches must have been created with the same relative accuracy, but may use different stores.
+func (sketch *DDSketch) Merge(other *DDSketch) error {
+	other.mutex.Lock()
+	copyOfOther := other.copyOfSketch()
+	other.mutex.Unlock()
+
+	sketch.mutex.Lock()
+	defer sketch.mutex.Unlock()
+
+	if copyOfOther.gamma != sketch.gamma {
+		return ErrorIncompatibleSketches
+	}
+
+	copyOfOther.positiveValueStore.forEachBin(func(index int, count float64) {
+		sketch.positiveValueStore.add(index, count)
+	})
+	copyOfOther.negativeValueStore.forEachBin(func(index int, count float64) {
+		sketch.negativeValueStore.add(index, count)
+	})
+
+	sketch.countOfValuesInZeroBucket += copyOfOther.countOfValuesInZeroBucket
+	sketch.sumOfAllValuesAdded += copyOfOther.sumOfAllValuesAdded
+	sketch.minimumValueAdded = math.Min(sketch.minimumValueAdded, copyOfOther.minimumValueAdded)
+	sketch.maximumValueAdded = math.Max(sketch.maximumValueAdded, copyOfOther.maximumValueAdded)
+
+	return nil
+}
+
+// Count returns the number of values added to the sketch.
+func (sketch *DDSketch) Count() int {
+	sketch.mutex.Lock()
+	defer sketch.mutex.Unlock()
+
+	return int(sketch.count())
+}
+
+// Minimum returns the smallest value added to the sketch, or NaN if the sketch is empty.
+func (sketch *DDSketch) Minimum() float64 {
+	sketch.mutex.Lock()
+	defer sketch.mutex.Unlock()
+
+	if sketch.count() == 0 {
+		return math.NaN()
+	}
+
+	return sketch.minimumValueAdded
+}
+
+// Maximum returns the largest value added to the sketch, or NaN if the sketch is empty.
+func (sketch *DDSketch) Maximum() float64 {
+	sketch.mutex.Lock()
+	defer sketch.mutex.Unlock()
+
+	if sketch.count() == 0 {
+		return math.NaN()
+	}
+
+	return sketch.maximumValueAdded
+}
+
+// Mean returns the mean of the values added to the sketch, or NaN if the sketch is empty.
+func (sketch *DDSketch) Mean() float64 {
+	sketch.mutex.Lock()
+	defer sketch.mutex.Unlock()
+
+	return sketch.sumOfAllValuesAdded / sketch.count()
+}
+
+// Quantile returns the estimated value at quantile q (in the range 0..1).  It returns NaN if the sketch is empty or
+// q is outside of the range 0..1.
+func (sketch *DDSketch) Quantile(q float64) float64 {
+	sketch.mutex.Lock()
+	defer sketch.mutex.Unlock()
+
+	count := sketch.count()
+	if count == 0 || !(q >= 0 && q <= 1) {
+		return math.NaN()
+	}
+
+	rank := q * (count - 1)
+	countOfNegativeValues := sketch.negativeValueStore.totalCount()
+
+	var value float64
+	switch {
+	case rank < countOfNegativeValues:
+		value = -sketch.valueFor(sketch.negativeValueStore.keyAtRank(countOfNegativeValues - 1 - rank))
+	case rank < countOfNegativeValues+sketch.countOfValuesInZeroBucket:
+		value = 0
+	default:
+		value = sketch.valueFor(sketch.positiveValueStore.keyAtRank(rank - countOfNegativeValues - sketch.countOfValuesInZeroBucket))
+	}
+
+	return math.Max(sketch.minimumValueAdded, math.Min(value, sketch.maximumValueAdded))
+}
+
+func (sketch *DDSketch) count() float64 {
+	return sketch.countOfValuesInZeroBucket + sketch.positiveValueStore.totalCount() + sketch.negativeValueStore.totalCount()
+}
+
+func (sketch *DDSketch) indexFor(value float64) int {
+	return int(math.Ceil(math.Log(value) / sketch.logGamma))
+}
+
+// valueFor returns the representative value of the bucket at index, which lies within the relative accuracy of
+// every value mapped to that bucket.
+func (sketch *DDSketch) valueFor(index int) float64 {
+	return math.Exp(float64(index)*sketch.logGamma) * 2 / (1 + sketch.gamma)
+}
+
+func (sketch *DDSketch) copyOfSketch() *DDSketch {
+	return &DDSketch{
+		relativeAccuracy:          sketch.relativeAccuracy,
+		gamma:                     sketch.gamma,
+		logGamma:                  sketch.logGamma,
+		minimumIndexableValue:     sketch.minimumIndexableValue,
+		storeKind:                 sketch.storeKind,
+		maximumNumberOfBins:       sketch.maximumNumberOfBins,
+		positiveValueStore:        sketch.positiveValueStore.copyOfStore(),
+		negativeValueStore:        sketch.negativeValueStore.copyOfStore(),
+		countOfValuesInZeroBucket: sketch.countOfValuesInZeroBucket,
+		sumOfAllValuesAdded:       sketch.sumOfAllValuesAdded,
+		minimumValueAdded:         sketch.minimumValueAdded,
+		maximumValueAdded:         sketch.maximumValueAdded,
+	}
+}
+
+const ddSketchEncodingVersion = 1
+
+// MarshalBinary encodes the sketch in a compact binary form that can be decoded with UnmarshalBinary or
+// DecodeDDSketch.  Bucket indexes are delta-encoded as varints.  A sketch with a dense store spanning more bucket
+// indexes than DecodeDDSketch accepts is encoded as a sparse sketch, so it decodes to a sketch with the same counts
+// backed by sparse stores.
+func (sketch *DDSketch) MarshalBinary() ([]byte, error) {
+	sketch.mutex.Lock()
+	defer sketch.mutex.Unlock()
+
+	storeKind, maximumNumberOfBins := sketch.storeKind, sketch.maximumNumberOfBins
+	if storeKind != ddSketchSparseStore {
+		for _, store := range []ddSketchStore{sketch.positiveValueStore, sketch.negativeValueStore} {
+			if rangeOfDDSketchStoreIndexes(store) > maximumRangeOfDecodedDenseDDSketchStore {
+				storeKind, maximumNumberOfBins = ddSketchSparseStore, 0
+			}
+		}
+	}
+
+	encoded := []byte{ddSketchEncodingVersion, byte(storeKind)}
+	encoded = binary.AppendUvarint(encoded, uint64(maximumNumberOfBins))
+
+	for _, f := range []float64{sketch.relativeAccuracy, sketch.countOfValuesInZeroBucket, sketch.sumOfAllValuesAdded, sketch.minimumValueAdded, sketch.maximumValueAdded} {
+		encoded = binary.LittleEndian.AppendUint64(encoded, math.Float64bits(f))
+	}
+
+	for _, store := range []ddSketchStore{sketch.positiveValueStore, sketch.negativeValueStore} {
+		encoded = appendEncodedDDSketchStore(encoded, store)
+	}
+
+	return encoded, nil
+}
+
+func appendEncodedDDSketchStore(encoded []byte, store ddSketchStore) []byte {
+	numberOfBins := 0
+	store.forEachBin(func(int, float64) { numberOfBins++ })
+
+	encoded = binary.AppendUvarint(encoded, uint64(numberOfBins))
+
+	previousIndex := 0
+	store.forEachBin(func(index int, count float64) {
+		encoded = binary.AppendVarint(encoded, int64(index-previousIndex))
+		encoded = binary.LittleEndian.AppendUint64(encoded, math.Float64bits(count))
+		previousIndex = index
+	})
+
+	return encoded
+}
+
+// rangeOfDDSketchStoreIndexes returns the number of bucket indexes from the lowest to the highest non-empty bucket of
+// store, or zero if it is empty.
+func rangeOfDDSketchStoreIndexes(store ddSketchStore) int {
+	lowestIndex, highestIndex, isEmpty := 0, 0, true
+	store.forEachBin(func(index int, count float64) {
+		if isEmpty {
+			lowestIndex, isEmpty = index, false
+		}
+		highestIndex = index
+	})
+
+	if isEmpty {
+		return 0
+	}
+
+	return highestIndex - lowestIndex + 1
+}
+
+// UnmarshalBinary replaces the contents of the sketch with a sketch encoded by MarshalBinary.
+func (sketch *DDSketch) UnmarshalBinary(encoded []byte) error {
+	decoded, err := DecodeDDSketch(encoded)
+	if err != nil {
+		return err
+	}
+
+	sketch.mutex.Lock()
+	defer sketch.mutex.Unlock()
+
+	sketch.relativeAccuracy = decoded.relativeAccuracy
+	sketch.gamma = decoded.gamma
+	sketch.logGamma = decoded.logGamma
+	sketch.minimumIndexableValue = decoded.minimumIndexableValue
+	sketch.storeKind = decoded.storeKind
+	sketch.maximumNumberOfBins = decoded.maximumNumberOfBins
+	sketch.positiveValueStore = decoded.positiveValueStore
+	sketch.negativeValueStore = decoded.negativeValueStore
+	sketch.countOfValuesInZeroBucket = decoded.countOfValuesInZeroBucket
+	sketch.sumOfAllValuesAdded = decoded.sumOfAllValuesAdded
+	sketch.minimumValueAdded = decoded.minimumValueAdded
+	sketch.maximumValueAdded = decoded.maximumValueAdded
+
+	return nil
+}
+
+// maximumRangeOfDecodedDenseDDSketchStore limits the range of bucket indexes that a decoded dense store may span, so
+// that a malformed encoding cannot cause an arbitrarily large allocation.  It is more than a relative accuracy of 0.1%
+// needs to cover every positive float64.
+const maximumRangeOfDecodedDenseDDSketchStore = 1 << 20
+
+// DecodeDDSketch creates a sketch from its encoding by MarshalBinary.  An error is returned if the encoding is
+// truncated or malformed: for example, if a bucket index is outside of the range of the sketch, if the indexes of a
+// store are not increasing, or if a count is not a whole number.  An error is also returned if a dense store
+// spans more bucket indexes than its maximum number of bins or than 1<<20, which MarshalBinary never encodes.
+func DecodeDDSketch(encoded []byte) (*DDSketch, error) {
+	decoder := &ddSketchDecoder{remaining: encoded}
+
+	version := decoder.byte()
+	storeKind := ddSketchStoreKind(decoder.byte())
+	maximumNumberOfBins := decoder.uvarint()
+
+	if decoder.err == nil && version != ddSketchEncodingVersion {
+		return nil, fmt.Errorf("unsupported sketch encoding version (%d)", version)
+	}
+
+	if decoder.err == nil && storeKind > ddSketchSparseStore {
+		return nil, fmt.Errorf("unsupported sketch store kind (%d)", storeKind)
+	}
+
+	if decoder.err == nil && ((storeKind == ddSketchCollapsingLowestDenseStore) != (maximumNumberOfBins > 0) || maximumNumberOfBins > math.MaxInt32) {
+		return nil, fmt.Errorf("invalid maximum number of bins (%d) for the sketch store kind", maximumNumberOfBins)
+	}
+
+	relativeAccuracy := decoder.float64()
+	countOfValuesInZeroBucket := decoder.float64()
+	sumOfAllValuesAdded := decoder.float64()
+	minimumValueAdded := decoder.float64()
+	maximumValueAdded := decoder.float64()
+
+	if decoder.err != nil {
+		return nil, decoder.err
+	}
+
+	if countOfValuesInZeroBucket != 0 && !isValidDDSketchCount(countOfValuesInZeroBucket) || math.IsNaN(minimumValueAdded) || math.IsNaN(maximumValueAdded) {
+		return nil, fmt.Errorf("encoded sketch has an invalid count in the zero bucket, or a NaN minimum or maximum")
+	}
+
+	sketch, err := newDDSketch(relativeAccuracy, storeKind, int(maximumNumberOfBins))
+	if err != nil {
+		return nil, err
+	}
+
+	// every value that can be added to the sketch maps to an index in this range
+	lowestIndex := math.Ceil(math.Log(sketch.minimumIndexableValue) / sketch.logGamma)
+	highestIndex := math.Ceil(math.Log(math.MaxFloat64) / sketch.logGamma)
+	if !(lowestIndex > -(1<<53) && highestIndex < 1<<53) {
+		return nil, fmt.Errorf("relative accuracy (%g) is too small", relativeAccuracy)
+	}
+
+	maximumRangeOfIndexes := 0
+	switch storeKind {
+	case ddSketchUnboundedDenseStore:
+		maximumRangeOfIndexes = maximumRangeOfDecodedDenseDDSketchStore
+	case ddSketchCollapsingLowestDenseStore:
+		maximumRangeOfIndexes = int(math.Min(float64(maximumNumberOfBins), maximumRangeOfDecodedDenseDDSketchStore))
+	}
+
+	for _, store := range []ddSketchStore{sketch.positiveValueStore, sketch.negativeValueStore} {
+		if err := decodeDDSketchStoreBins(decoder, store, int(lowestIndex), int(highestIndex), maximumRangeOfIndexes); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(decoder.remaining) != 0 {
+		return nil, fmt.Errorf("unexpected data after encoded sketch")
+	}
+
+	sketch.countOfValuesInZeroBucket = countOfValuesInZeroBucket
+	sketch.sumOfAllValuesAdded = sumOfAllValuesAdded
+	sketch.minimumValueAdded = minimumValueAdded
+	sketch.maximumValueAdded = maximumValueAdded
+
+	return sketch, nil
+}
+
+// decodeDDSketchStoreBins adds the bins encoded by appendEncodedDDSketchStore to store.  Every index must be in the
+// range lowestIndex..highestIndex, and if maximumRangeOfIndexes is not zero, the indexes must span no more than that
+// many buckets.
+func decodeDDSketchStoreBins(decoder *ddSketchDecoder, store ddSketchStore, lowestIndex int, highestIndex int, maximumRangeOfIndexes int) error {
+	numberOfBins := decoder.uvarint()
+
+	index, firstIndex := 0, 0
+	for i := uint64(0); i < numberOfBins && decoder.err == nil; i++ {
+		delta := decoder.varint()
+		count := decoder.float64()
+		if decoder.err != nil {
+			break
+		}
+
+		if i > 0 && delta <= 0 {
+			return fmt.Errorf("encoded sketch bins are not in increasing order of index")
+		}
+
+		// the bounds are within ±2^53, so neither subtraction overflows
+		if delta < int64(lowestIndex-index) || delta > int64(highestIndex-index) {
+			return fmt.Errorf("encoded sketch has a bin index outside of the range of the sketch")
+		}
+
+		index += int(delta)
+		if i == 0 {
+			firstIndex = index
+		}
+
+		if maximumRangeOfIndexes > 0 && index-firstIndex >= maximumRangeOfIndexes {
+			return fmt.Errorf("encoded sketch bins span more than %d indexes", maximumRangeOfIndexes)
+		}
+
+		if !isValidDDSketchCount(count) {
+			return fmt.Errorf("encoded sketch has a bin whose count is not a positive whole number")
+		}
+
+		store.add(index, count)
+	}
+
+	return decoder.err
+}
+
+// isValidDDSketchCount returns true if count is a positive whole number, as every count of a sketch is.
+func isValidDDSketchCount(count float64) bool {
+	return count >= 1 && !math.IsInf(count, 1) && count == math.Trunc(count)
+}
+
+type ddSketchDecoder struct {
+	remaining []byte
+	err       error
+}
+
+var errorTruncatedDDSketchEncoding = errors.New("encoded sketch is truncated or malformed")
+
+func (decoder *ddSketchDecoder) byte() byte {
+	if decoder.err != nil || len(decoder.remaining) < 1 {
+		decoder.err = errorTruncatedDDSketchEncoding
+		return 0
+	}
+
+	b := decoder.remaining[0]
+	decoder.remaining = decoder.remaining[1:]
+
+	return b
+}
+
+func (decoder *ddSketchDecoder) float64() float64 {
+	if decoder.err != nil || len(decoder.remaining) < 8 {
+		decoder.err = errorTruncatedDDSketchEncoding
+		return 0
+	}
+
+	f := math.Float64frombits(binary.LittleEndian.Uint64(decoder.remaining))
+	decoder.remaining = decoder.remaining[8:]
+
+	return f
+}
+
+func (decoder *ddSketchDecoder) uvarint() uint64 {
+	if decoder.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(decoder.remaining)
+	if n <= 0 {
+		decoder.err = errorTruncatedDDSketchEncoding
+		return 0
+	}
+
+	decoder.remaining = decoder.remaining[n:]
+
+	return v
+}
+
+func (decoder *ddSketchDecoder) varint() int64 {
+	if decoder.err != nil {
+		return 0
+	}
+
+	v, n := binary.Varint(decoder.remaining)
+	if n <= 0 {
+		decoder.err = errorTruncatedDDSketchEncoding
+		return 0
+	}
+
+	decoder.remaining = decoder.remaining[n:]
+
+	return v
+}
+
+// A ddSketchStore counts the values mapped to each bucket index.
+type ddSketchStore interface {
+	add(index int, count float64)
+	totalCount() float64
+	keyAtRank(rank float64) int
+	forEachBin(visit func(index int, count float64))
+	copyOfStore() ddSketchStore
+}
+
+func newDDSketchStore(kind ddSketchStoreKind, maximumNumberOfBins int) ddSketchStore {
+	switch kind {
+	case ddSketchCollapsingLowestDenseStore:
+		return &denseDDSketchStore{maximumNumberOfBins: maximumNumberOfBins}
+	case ddSketchSparseStore:
+		return &sparseDDSketchStore{bins: make(map[int]float64)}
+	}
+
+	return &denseDDSketchStore{}
+}
+
+// A denseDDSketchStore holds a contiguous slice of counts covering the bucket indexes from minimumIndex to
+// maximumIndex.  If maximumNumberOfBins is non-zero, the lowest buckets are collapsed into one so that the range
+// never exceeds that many buckets.
+type denseDDSketchStore struct {
+	bins                []float64
+	offset              int
+	minimumIndex        int
+	maximumIndex        int
+	count               float64
+	maximumNumberOfBins int
+}
+
+const denseDDSketchStoreMinimumLength = 64
+
+func (store *denseDDSketchStore) add(index int, count float64) {
+	if count <= 0 {
+		return
+	}
+
+	if store.count == 0 {
+		store.bins = make([]float64, denseDDSketchStoreMinimumLength)
+		store.offset = index - denseDDSketchStoreMinimumLength/2
+		store.minimumIndex, store.maximumIndex = index, index
+	} else {
+		newMinimumIndex, newMaximumIndex := store.minimumIndex, store.maximumIndex
+		if index < newMinimumIndex {
+			newMinimumIndex = index
+		}
+		if index > newMaximumIndex {
+			newMaximumIndex = index
+		}
+
+		if store.maximumNumberOfBins > 0 && newMaximumIndex-newMinimumIndex+1 > store.maximumNumberOfBins {
+			newMinimumIndex = newMaximumIndex - store.maximumNumberOfBins + 1
+			if index < newMinimumIndex {
+				index = newMinimumIndex
+			}
+
+			collapsedCount := 0.0
+			for i := store.minimumIndex; i <= store.maximumIndex && i < newMinimumIndex; i++ {
+				collapsedCount += store.bins[i-store.offset]
+				store.bins[i-store.offset] = 0
+			}
+
+			store.ensureBinsCover(newMinimumIndex, newMaximumIndex)
+			store.bins[newMinimumIndex-store.offset] += collapsedCount
+		} else {
+			store.ensureBinsCover(newMinimumIndex, newMaximumIndex)
+		}
+
+		store.minimumIndex, store.maximumIndex = newMinimumIndex, newMaximumIndex
+	}
+
+	store.bins[index-store.offset] += count
+	store.count += count
+}
+
+func (store *denseDDSketchStore) ensureBinsCover(lowestIndex int, highestIndex int) {
+	if lowestIndex >= store.offset && highestIndex < store.offset+len(store.bins) {
+		return
+	}
+
+	lengthOfRange := highestIndex - lowestIndex + 1
+	newLength := 2 * lengthOfRange
+	if newLength < denseDDSketchStoreMinimumLength {
+		newLength = denseDDSketchStoreMinimumLength
+	}
+
+	newBins := make([]float64, newLength)
+	newOffset := lowestIndex - (newLength-lengthOfRange)/2
+
+	for i := store.minimumIndex; i <= store.maximumIndex; i++ {
+		if i >= newOffset && i < newOffset+newLength {
+			newBins[i-newOffset] = store.bins[i-store.offset]
+		}
+	}
+
+	store.bins, store.offset = newBins, newOffset
+}
+
+func (store *denseDDSketchStore) totalCount() float64 {
+	return store.count
+}
+
+func (store *denseDDSketchStore) keyAtRank(rank float64) int {
+	cumulativeCount := 0.0
+	for i := store.minimumIndex; i <= store.maximumIndex; i++ {
+		cumulativeCount += store.bins[i-store.offset]
+		if cumulativeCount > rank {
+			return i
+		}
+	}
+
+	return store.maximumIndex
+}
+
+func (store *denseDDSketchStore) forEachBin(visit func(index int, count float64)) {
+	if store.count == 0 {
+		return
+	}
+
+	for i := store.minimumIndex; i <= store.maximumIndex; i++ {
+		if count := store.bins[i-store.offset]; count != 0 {
+			visit(i, count)
+		}
+	}
+}
+
+func (store *denseDDSketchStore) copyOfStore() ddSketchStore {
+	copyOfStore := *store
+	copyOfStore.bins = make([]float64, len(store.bins))
+	copy(copyOfStore.bins, store.bins)
+
+	return &copyOfStore
+}
+
+type sparseDDSketchStore struct {
+	bins  map[int]float64
+	count float64
+}
+
+func (store *sparseDDSketchStore) add(index int, count float64) {
+	if count <= 0 {
+		return
+	}
+
+	store.bins[index] += count
+	store.count += count
+}
+
+func (store *sparseDDSketchStore) totalCount() float64 {
+	return store.count
+}
+
+func (store *sparseDDSketchStore) sortedIndexes() []int {
+	indexes := make([]int, 0, len(store.bins))
+	for index := range store.bins {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+
+	return indexes
+}
+
+func (store *sparseDDSketchStore) keyAtRank(rank float64) int {
+	indexes := store.sortedIndexes()
+
+	cumulativeCount := 0.0
+	for _, index := range indexes {
+		cumulativeCount += store.bins[index]
+		if cumulativeCount > rank {
+			return index
+		}
+	}
+
+	return indexes[len(indexes)-1]
+}
+
+func (store *sparseDDSketchStore) forEachBin(visit func(index int, count float64)) {
+	for _, index := range store.sortedIndexes() {
+		visit(index, store.bins[index])
+	}
+}
+
+func (store *sparseDDSketchStore) copyOfStore() ddSketchStore {
+	copyOfStore := &sparseDDSketchStore{bins: make(map[int]float64, len(store.bins)), count: store.count}
+	for index, count := range store.bins {
+		copyOfStore.bins[index] = count
+	}
+
+	return copyOfStore
+}
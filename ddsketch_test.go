@@ -0,0 +1,291 @@
+package stats_test
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+func verifyDDSketchQuantilesAreWithinRelativeAccuracy(t *testing.T, sketch *stats.DDSketch, sortedValues []float64, quantiles []float64) {
+	t.Helper()
+
+	for _, q := range quantiles {
+		rank := q * float64(len(sortedValues)-1)
+		lower := sortedValues[int(math.Floor(rank))]
+		upper := sortedValues[int(math.Ceil(rank))]
+
+		got := sketch.Quantile(q)
+
+		allowedError := sketch.RelativeAccuracy()*math.Max(math.Abs(lower), math.Abs(upper)) + 1e-12
+		if got < lower-allowedError || got > upper+allowedError {
+			t.Errorf("for quantile (%f) expected value within relative accuracy of [%f, %f], got (%f)", q, lower, upper, got)
+		}
+	}
+}
+
+func TestDDSketchRelativeAccuracy(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+
+	values := make([]float64, 50000)
+	for i := range values {
+		values[i] = math.Exp(rng.NormFloat64() * 3)
+		if i%5 == 0 {
+			values[i] = -values[i]
+		}
+		if i%101 == 0 {
+			values[i] = 0
+		}
+	}
+
+	sortedValues := append([]float64{}, values...)
+	sort.Float64s(sortedValues)
+
+	dense, _ := stats.NewDDSketch(0.01)
+	sparse, _ := stats.NewSparseDDSketch(0.01)
+
+	dense.AddMany(values)
+	sparse.AddMany(values)
+
+	quantiles := []float64{0, 0.001, 0.01, 0.1, 0.18, 0.2, 0.25, 0.5, 0.75, 0.9, 0.99, 0.999, 1}
+
+	verifyDDSketchQuantilesAreWithinRelativeAccuracy(t, dense, sortedValues, quantiles)
+	verifyDDSketchQuantilesAreWithinRelativeAccuracy(t, sparse, sortedValues, quantiles)
+
+	if dense.Count() != len(values) {
+		t.Errorf("expected Count (%d), got (%d)", len(values), dense.Count())
+	}
+
+	if dense.Minimum() != sortedValues[0] || dense.Maximum() != sortedValues[len(sortedValues)-1] {
+		t.Errorf("expected Minimum (%f) and Maximum (%f), got (%f) and (%f)", sortedValues[0], sortedValues[len(sortedValues)-1], dense.Minimum(), dense.Maximum())
+	}
+}
+
+func TestCollapsingDDSketchKeepsHighQuantilesAccurate(t *testing.T) {
+	sketch, err := stats.NewCollapsingDDSketch(0.02, 100)
+	if err != nil {
+		t.Fatalf("on NewCollapsingDDSketch() got error: %s", err.Error())
+	}
+
+	sortedValues := make([]float64, 0, 100000)
+	for i := 1; i <= 100000; i++ {
+		sortedValues = append(sortedValues, float64(i))
+	}
+
+	sketch.AddMany(sortedValues)
+
+	verifyDDSketchQuantilesAreWithinRelativeAccuracy(t, sketch, sortedValues, []float64{0.5, 0.9, 0.99, 0.999, 1})
+
+	if got := sketch.Quantile(0.001); got <= 100 {
+		t.Errorf("for quantile (0.001) expected the value to come from a collapsed bucket above (100), got (%f)", got)
+	}
+}
+
+func TestDDSketchMergeAndEncoding(t *testing.T) {
+	first, _ := stats.NewDDSketch(0.01)
+	second, _ := stats.NewCollapsingDDSketch(0.01, 2048)
+
+	sortedValues := make([]float64, 0, 2000)
+	for i := 1; i <= 1000; i++ {
+		first.Add(float64(i))
+		second.Add(float64(-i))
+		sortedValues = append(sortedValues, float64(i), float64(-i))
+	}
+	sort.Float64s(sortedValues)
+
+	encoded, err := second.MarshalBinary()
+	if err != nil {
+		t.Fatalf("on MarshalBinary() got error: %s", err.Error())
+	}
+
+	decoded, err := stats.DecodeDDSketch(encoded)
+	if err != nil {
+		t.Fatalf("on DecodeDDSketch() got error: %s", err.Error())
+	}
+
+	if decoded.Count() != second.Count() || decoded.Quantile(0.5) != second.Quantile(0.5) || decoded.Mean() != second.Mean() {
+		t.Errorf("expected decoded sketch to match encoded sketch, but Count, Quantile(0.5) and Mean are (%d, %f, %f) and (%d, %f, %f)",
+			decoded.Count(), decoded.Quantile(0.5), decoded.Mean(), second.Count(), second.Quantile(0.5), second.Mean())
+	}
+
+	if err := first.Merge(decoded); err != nil {
+		t.Fatalf("on Merge() got error: %s", err.Error())
+	}
+
+	if first.Count() != 2000 {
+		t.Errorf("expected Count (2000) after Merge, got (%d)", first.Count())
+	}
+
+	verifyDDSketchQuantilesAreWithinRelativeAccuracy(t, first, sortedValues, []float64{0, 0.01, 0.25, 0.5, 0.75, 0.99, 1})
+
+	var roundTripped stats.DDSketch
+	encoded, _ = first.MarshalBinary()
+	if err := roundTripped.UnmarshalBinary(encoded); err != nil {
+		t.Fatalf("on UnmarshalBinary() got error: %s", err.Error())
+	}
+
+	verifyDDSketchQuantilesAreWithinRelativeAccuracy(t, &roundTripped, sortedValues, []float64{0, 0.01, 0.25, 0.5, 0.75, 0.99, 1})
+
+	incompatible, _ := stats.NewDDSketch(0.05)
+	if err := first.Merge(incompatible); err != stats.ErrorIncompatibleSketches {
+		t.Errorf("on Merge() of sketch with different relative accuracy, expected ErrorIncompatibleSketches, got (%v)", err)
+	}
+
+	if _, err := stats.DecodeDDSketch(encoded[:len(encoded)-3]); err == nil {
+		t.Errorf("on DecodeDDSketch() of truncated encoding, expected error, got none")
+	}
+}
+
+func TestDDSketchEncodingOfWideDenseStores(t *testing.T) {
+	// at a relative accuracy of 1e-5, the values from 1e-10 to 1e10 span about 2.3 million bucket indexes
+	unbounded, _ := stats.NewDDSketch(1e-5)
+	collapsing, _ := stats.NewCollapsingDDSketch(1e-5, 1<<22)
+
+	for testIndex, sketch := range []*stats.DDSketch{unbounded, collapsing} {
+		for value := 1e-10; value <= 1e10; value *= 10 {
+			sketch.Add(value)
+			sketch.Add(-value)
+		}
+
+		encoded, err := sketch.MarshalBinary()
+		if err != nil {
+			t.Fatalf("on test with index (%d) MarshalBinary() got error: %s", testIndex, err)
+		}
+
+		decoded, err := stats.DecodeDDSketch(encoded)
+		if err != nil {
+			t.Errorf("on test with index (%d) DecodeDDSketch() got error: %s", testIndex, err)
+			continue
+		}
+
+		if decoded.Count() != sketch.Count() || decoded.Minimum() != sketch.Minimum() || decoded.Maximum() != sketch.Maximum() {
+			t.Errorf("on test with index (%d) expected decoded Count, Minimum and Maximum (%d, %g, %g), got (%d, %g, %g)", testIndex, sketch.Count(), sketch.Minimum(), sketch.Maximum(), decoded.Count(), decoded.Minimum(), decoded.Maximum())
+		}
+
+		for _, q := range []float64{0, 0.1, 0.25, 0.5, 0.6, 0.9, 1} {
+			if decoded.Quantile(q) != sketch.Quantile(q) {
+				t.Errorf("on test with index (%d) expected decoded Quantile(%f) (%g), got (%g)", testIndex, q, sketch.Quantile(q), decoded.Quantile(q))
+			}
+		}
+	}
+}
+
+func TestDDSketchErrors(t *testing.T) {
+	for _, relativeAccuracy := range []float64{0, 1, -0.1, math.NaN()} {
+		if _, err := stats.NewDDSketch(relativeAccuracy); err == nil {
+			t.Errorf("on NewDDSketch(%f) expected error, got none", relativeAccuracy)
+		}
+	}
+
+	if _, err := stats.NewCollapsingDDSketch(0.01, 0); err == nil {
+		t.Errorf("on NewCollapsingDDSketch() with zero bins expected error, got none")
+	}
+
+	sketch, _ := stats.NewDDSketch(0.01)
+	if !math.IsNaN(sketch.Quantile(0.5)) {
+		t.Errorf("on Quantile() of empty sketch, expected NaN, got (%f)", sketch.Quantile(0.5))
+	}
+}
+
+type encodedDDSketchBin struct {
+	indexDelta int64
+	count      float64
+}
+
+// encodeDDSketch builds an encoding in the form written by MarshalBinary, so that it may be made malformed.
+func encodeDDSketch(storeKind byte, maximumNumberOfBins uint64, header [5]float64, positiveBins []encodedDDSketchBin, negativeBins []encodedDDSketchBin) []byte {
+	encoded := []byte{1, storeKind}
+	encoded = binary.AppendUvarint(encoded, maximumNumberOfBins)
+
+	for _, f := range header {
+		encoded = binary.LittleEndian.AppendUint64(encoded, math.Float64bits(f))
+	}
+
+	for _, bins := range [][]encodedDDSketchBin{positiveBins, negativeBins} {
+		encoded = binary.AppendUvarint(encoded, uint64(len(bins)))
+		for _, bin := range bins {
+			encoded = binary.AppendVarint(encoded, bin.indexDelta)
+			encoded = binary.LittleEndian.AppendUint64(encoded, math.Float64bits(bin.count))
+		}
+	}
+
+	return encoded
+}
+
+func TestDecodeDDSketchOfMalformedEncodings(t *testing.T) {
+	// relative accuracy, count in the zero bucket, sum, minimum and maximum
+	header := [5]float64{0.01, 0, 30, 10, 20}
+	valid := []encodedDDSketchBin{{115, 1}, {35, 1}}
+
+	if sketch, err := stats.DecodeDDSketch(encodeDDSketch(0, 0, header, valid, nil)); err != nil || sketch.Count() != 2 {
+		t.Fatalf("on DecodeDDSketch() of a valid encoding expected a sketch with Count (2), got (%v) and error (%v)", sketch, err)
+	}
+
+	for testIndex, testCase := range []struct {
+		name    string
+		encoded []byte
+	}{
+		{"empty", []byte{}},
+		{"unknown store kind", encodeDDSketch(3, 0, header, valid, nil)},
+		{"unbounded store with maximum number of bins", encodeDDSketch(0, 10, header, valid, nil)},
+		{"collapsing store without maximum number of bins", encodeDDSketch(1, 0, header, valid, nil)},
+		{"collapsing store with too many bins", encodeDDSketch(1, 1<<40, header, valid, nil)},
+		{"index beyond the range of the sketch", encodeDDSketch(0, 0, header, []encodedDDSketchBin{{math.MaxInt64, 1}}, nil)},
+		{"index delta that overflows", encodeDDSketch(2, 0, header, []encodedDDSketchBin{{100, 1}, {math.MaxInt64, 1}}, nil)},
+		{"index below the range of the sketch", encodeDDSketch(2, 0, header, []encodedDDSketchBin{{math.MinInt64, 1}}, nil)},
+		{"decreasing indexes", encodeDDSketch(2, 0, header, []encodedDDSketchBin{{100, 1}, {-1, 1}}, nil)},
+		{"repeated index", encodeDDSketch(0, 0, header, []encodedDDSketchBin{{100, 1}, {0, 1}}, nil)},
+		{"unbounded store spanning too many indexes", encodeDDSketch(0, 0, [5]float64{0.0001, 0, 0, 1e-10, 1e10}, []encodedDDSketchBin{{-1000, 1}, {1 << 20, 1}}, nil)},
+		{"collapsing store spanning more than its bins", encodeDDSketch(1, 16, header, []encodedDDSketchBin{{100, 1}, {16, 1}}, nil)},
+		{"negative count", encodeDDSketch(0, 0, header, nil, []encodedDDSketchBin{{100, -1}})},
+		{"zero count", encodeDDSketch(0, 0, header, []encodedDDSketchBin{{100, 0}}, nil)},
+		{"NaN count", encodeDDSketch(2, 0, header, []encodedDDSketchBin{{100, math.NaN()}}, nil)},
+		{"infinite count", encodeDDSketch(0, 0, header, []encodedDDSketchBin{{100, math.Inf(1)}}, nil)},
+		{"fractional count", encodeDDSketch(0, 0, header, []encodedDDSketchBin{{100, 1.5}}, nil)},
+		{"fractional count in the zero bucket", encodeDDSketch(0, 0, [5]float64{0.01, 1e-76, 0, 0, 0}, nil, nil)},
+		{"negative count in the zero bucket", encodeDDSketch(0, 0, [5]float64{0.01, -1, 0, 0, 0}, nil, nil)},
+		{"NaN maximum", encodeDDSketch(0, 0, [5]float64{0.01, 1, 0, 0, math.NaN()}, nil, nil)},
+		{"relative accuracy too small for indexes", encodeDDSketch(0, 0, [5]float64{1e-300, 0, 0, 0, 0}, nil, nil)},
+		{"more bins than encoded", encodeDDSketch(0, 0, header, valid, nil)[:60]},
+		{"trailing data", append(encodeDDSketch(0, 0, header, valid, nil), 0)},
+	} {
+		if _, err := stats.DecodeDDSketch(testCase.encoded); err == nil {
+			t.Errorf("on test with index (%d) (%s) expected error, got none", testIndex, testCase.name)
+		}
+	}
+}
+
+func FuzzDecodeDDSketch(f *testing.F) {
+	for _, sketch := range []func() (*stats.DDSketch, error){
+		func() (*stats.DDSketch, error) { return stats.NewDDSketch(0.01) },
+		func() (*stats.DDSketch, error) { return stats.NewCollapsingDDSketch(0.02, 64) },
+		func() (*stats.DDSketch, error) { return stats.NewSparseDDSketch(0.05) },
+	} {
+		s, _ := sketch()
+		s.AddMany([]float64{-1000, -1, 0, 0.001, 1, 2, 3, 1e6})
+		encoded, _ := s.MarshalBinary()
+		f.Add(encoded)
+	}
+
+	f.Fuzz(func(t *testing.T, encoded []byte) {
+		sketch, err := stats.DecodeDDSketch(encoded)
+		if err != nil {
+			return
+		}
+
+		// a sketch that decodes must encode and decode again
+		reencoded, err := sketch.MarshalBinary()
+		if err != nil {
+			t.Fatalf("on MarshalBinary() of decoded sketch got error: %s", err)
+		}
+
+		if _, err := stats.DecodeDDSketch(reencoded); err != nil {
+			t.Fatalf("on DecodeDDSketch() of re-encoded sketch got error: %s", err)
+		}
+
+		sketch.Quantile(0.5)
+	})
+}
@@ -0,0 +1,379 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// BinomialDistribution is the distribution of the number of successes in a fixed number of independent trials,
+// each of which succeeds with the same probability.
+type BinomialDistribution struct {
+	numberOfTrials       int
+	probabilityOfSuccess float64
+}
+
+// NewBinomialDistribution returns the binomial distribution for the given number of trials, which must not be
+// negative, and probability of success, which must be in [0, 1].
+func NewBinomialDistribution(numberOfTrials int, probabilityOfSuccess float64) (*BinomialDistribution, error) {
+	if numberOfTrials < 0 {
+		return nil, fmt.Errorf("number of trials must not be negative")
+	}
+
+	if probabilityIsNotValid(probabilityOfSuccess) {
+		return nil, fmt.Errorf("probability of success must be in [0, 1]")
+	}
+
+	return &BinomialDistribution{numberOfTrials: numberOfTrials, probabilityOfSuccess: probabilityOfSuccess}, nil
+}
+
+func (d *BinomialDistribution) PMF(k int) float64 {
+	if k < 0 || k > d.numberOfTrials {
+		return 0
+	}
+
+	n, kf, p := float64(d.numberOfTrials), float64(k), d.probabilityOfSuccess
+
+	return math.Exp(logBinomialCoefficient(n, kf) + xLogY(kf, p) + xLog1pY(n-kf, -p))
+}
+
+func (d *BinomialDistribution) CDF(x float64) float64 {
+	k := math.Floor(x)
+	n := float64(d.numberOfTrials)
+
+	switch {
+	case math.IsNaN(x):
+		return math.NaN()
+	case k < 0:
+		return 0
+	case k >= n:
+		return 1
+	}
+
+	return RegularizedIncompleteBeta(n-k, k+1, 1-d.probabilityOfSuccess)
+}
+
+func (d *BinomialDistribution) Quantile(p float64) float64 {
+	if probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	if p == 1 {
+		if d.probabilityOfSuccess == 0 {
+			return 0
+		}
+		return float64(d.numberOfTrials)
+	}
+
+	guess := d.Mean() + standardNormalQuantile(p)*math.Sqrt(d.Variance())
+
+	return discreteQuantileBySearch(d.CDF, p, guess, 0, float64(d.numberOfTrials))
+}
+
+func (d *BinomialDistribution) Mean() float64 {
+	return float64(d.numberOfTrials) * d.probabilityOfSuccess
+}
+
+func (d *BinomialDistribution) Variance() float64 {
+	return float64(d.numberOfTrials) * d.probabilityOfSuccess * (1 - d.probabilityOfSuccess)
+}
+
+func (d *BinomialDistribution) Rand(rng *rand.Rand) float64 {
+	return float64(binomialVariate(rng, d.numberOfTrials, d.probabilityOfSuccess))
+}
+
+// PoissonDistribution is the Poisson distribution with a given mean.
+type PoissonDistribution struct {
+	mean float64
+}
+
+// NewPoissonDistribution returns the Poisson distribution with the given mean, which must be greater than 0.
+func NewPoissonDistribution(mean float64) (*PoissonDistribution, error) {
+	if !(mean > 0) || math.IsInf(mean, 1) {
+		return nil, fmt.Errorf("mean must be greater than 0 and finite")
+	}
+
+	return &PoissonDistribution{mean: mean}, nil
+}
+
+func (d *PoissonDistribution) PMF(k int) float64 {
+	if k < 0 {
+		return 0
+	}
+
+	kf := float64(k)
+	lgammaOfKPlusOne, _ := math.Lgamma(kf + 1)
+
+	return math.Exp(kf*math.Log(d.mean) - d.mean - lgammaOfKPlusOne)
+}
+
+func (d *PoissonDistribution) CDF(x float64) float64 {
+	k := math.Floor(x)
+
+	switch {
+	case math.IsNaN(x):
+		return math.NaN()
+	case k < 0:
+		return 0
+	case math.IsInf(k, 1):
+		return 1
+	}
+
+	return RegularizedUpperIncompleteGamma(k+1, d.mean)
+}
+
+func (d *PoissonDistribution) Quantile(p float64) float64 {
+	if probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	if p == 1 {
+		return math.Inf(1)
+	}
+
+	guess := d.mean + standardNormalQuantile(p)*math.Sqrt(d.mean)
+
+	return discreteQuantileBySearch(d.CDF, p, guess, 0, math.Inf(1))
+}
+
+func (d *PoissonDistribution) Mean() float64 {
+	return d.mean
+}
+
+func (d *PoissonDistribution) Variance() float64 {
+	return d.mean
+}
+
+func (d *PoissonDistribution) Rand(rng *rand.Rand) float64 {
+	return float64(poissonVariate(rng, d.mean))
+}
+
+// GeometricDistribution is the distribution of the number of failures before the first success in a sequence of
+// independent trials, each of which succeeds with the same probability.
+type GeometricDistribution struct {
+	probabilityOfSuccess float64
+}
+
+// NewGeometricDistribution returns the geometric distribution with the given probability of success, which must be
+// in (0, 1].
+func NewGeometricDistribution(probabilityOfSuccess float64) (*GeometricDistribution, error) {
+	if !(probabilityOfSuccess > 0 && probabilityOfSuccess <= 1) {
+		return nil, fmt.Errorf("probability of success must be in (0, 1]")
+	}
+
+	return &GeometricDistribution{probabilityOfSuccess: probabilityOfSuccess}, nil
+}
+
+func (d *GeometricDistribution) PMF(k int) float64 {
+	if k < 0 {
+		return 0
+	}
+
+	return d.probabilityOfSuccess * math.Exp(xLog1pY(float64(k), -d.probabilityOfSuccess))
+}
+
+func (d *GeometricDistribution) CDF(x float64) float64 {
+	k := math.Floor(x)
+
+	switch {
+	case math.IsNaN(x):
+		return math.NaN()
+	case k < 0:
+		return 0
+	}
+
+	return -math.Expm1((k + 1) * math.Log1p(-d.probabilityOfSuccess))
+}
+
+func (d *GeometricDistribution) Quantile(p float64) float64 {
+	if probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	if p == 1 {
+		if d.probabilityOfSuccess == 1 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+
+	guess := math.Log1p(-p)/math.Log1p(-d.probabilityOfSuccess) - 1
+
+	return discreteQuantileBySearch(d.CDF, p, guess, 0, math.Inf(1))
+}
+
+func (d *GeometricDistribution) Mean() float64 {
+	return (1 - d.probabilityOfSuccess) / d.probabilityOfSuccess
+}
+
+func (d *GeometricDistribution) Variance() float64 {
+	return (1 - d.probabilityOfSuccess) / (d.probabilityOfSuccess * d.probabilityOfSuccess)
+}
+
+func (d *GeometricDistribution) Rand(rng *rand.Rand) float64 {
+	if d.probabilityOfSuccess == 1 {
+		return 0
+	}
+
+	return math.Floor(-rng.ExpFloat64() / math.Log1p(-d.probabilityOfSuccess))
+}
+
+// NegativeBinomialDistribution is the distribution of the number of failures before a given number of successes in
+// a sequence of independent trials, each of which succeeds with the same probability.  The number of successes need
+// not be an integer, which allows the distribution to be used as an overdispersed alternative to the Poisson.
+type NegativeBinomialDistribution struct {
+	numberOfSuccesses    float64
+	probabilityOfSuccess float64
+}
+
+// NewNegativeBinomialDistribution returns the negative binomial distribution with the given number of successes,
+// which must be greater than 0, and probability of success, which must be in (0, 1].
+func NewNegativeBinomialDistribution(numberOfSuccesses float64, probabilityOfSuccess float64) (*NegativeBinomialDistribution, error) {
+	if !(numberOfSuccesses > 0) || math.IsInf(numberOfSuccesses, 1) {
+		return nil, fmt.Errorf("number of successes must be greater than 0 and finite")
+	}
+
+	if !(probabilityOfSuccess > 0 && probabilityOfSuccess <= 1) {
+		return nil, fmt.Errorf("probability of success must be in (0, 1]")
+	}
+
+	return &NegativeBinomialDistribution{numberOfSuccesses: numberOfSuccesses, probabilityOfSuccess: probabilityOfSuccess}, nil
+}
+
+func (d *NegativeBinomialDistribution) PMF(k int) float64 {
+	if k < 0 {
+		return 0
+	}
+
+	kf, r, p := float64(k), d.numberOfSuccesses, d.probabilityOfSuccess
+	lgammaOfKPlusR, _ := math.Lgamma(kf + r)
+	lgammaOfKPlusOne, _ := math.Lgamma(kf + 1)
+	lgammaOfR, _ := math.Lgamma(r)
+
+	return math.Exp(lgammaOfKPlusR - lgammaOfKPlusOne - lgammaOfR + r*math.Log(p) + xLog1pY(kf, -p))
+}
+
+func (d *NegativeBinomialDistribution) CDF(x float64) float64 {
+	k := math.Floor(x)
+
+	switch {
+	case math.IsNaN(x):
+		return math.NaN()
+	case k < 0:
+		return 0
+	case math.IsInf(k, 1):
+		return 1
+	}
+
+	return RegularizedIncompleteBeta(d.numberOfSuccesses, k+1, d.probabilityOfSuccess)
+}
+
+func (d *NegativeBinomialDistribution) Quantile(p float64) float64 {
+	if probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	if p == 1 {
+		if d.probabilityOfSuccess == 1 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+
+	guess := d.Mean() + standardNormalQuantile(p)*math.Sqrt(d.Variance())
+
+	return discreteQuantileBySearch(d.CDF, p, guess, 0, math.Inf(1))
+}
+
+func (d *NegativeBinomialDistribution) Mean() float64 {
+	return d.numberOfSuccesses * (1 - d.probabilityOfSuccess) / d.probabilityOfSuccess
+}
+
+func (d *NegativeBinomialDistribution) Variance() float64 {
+	return d.numberOfSuccesses * (1 - d.probabilityOfSuccess) / (d.probabilityOfSuccess * d.probabilityOfSuccess)
+}
+
+// Rand draws from the gamma–Poisson mixture that is equivalent to the negative binomial distribution.
+func (d *NegativeBinomialDistribution) Rand(rng *rand.Rand) float64 {
+	if d.probabilityOfSuccess == 1 {
+		return 0
+	}
+
+	mean := gammaVariate(rng, d.numberOfSuccesses) * (1 - d.probabilityOfSuccess) / d.probabilityOfSuccess
+	if mean <= 0 {
+		return 0
+	}
+
+	return float64(poissonVariate(rng, mean))
+}
+
+func logBinomialCoefficient(n float64, k float64) float64 {
+	lgammaOfNPlusOne, _ := math.Lgamma(n + 1)
+	lgammaOfKPlusOne, _ := math.Lgamma(k + 1)
+	lgammaOfNMinusKPlusOne, _ := math.Lgamma(n - k + 1)
+
+	return lgammaOfNPlusOne - lgammaOfKPlusOne - lgammaOfNMinusKPlusOne
+}
+
+// Below these sizes, binomial and Poisson variates are drawn directly rather than by reducing the problem size.
+const (
+	binomialVariateDirectMaximumTrials = 64
+	poissonVariateDirectMaximumMean    = 16
+)
+
+// binomialVariate draws from the binomial distribution by repeatedly drawing the order statistic of the middle trial
+// from the beta distribution and continuing with the trials on whichever side of it the probability of success lies
+// (Knuth, TAOCP Vol. 2, §3.4.1), then simulating the remaining trials.
+func binomialVariate(rng *rand.Rand, numberOfTrials int, probabilityOfSuccess float64) int {
+	successes := 0
+	n, p := numberOfTrials, probabilityOfSuccess
+
+	for n > binomialVariateDirectMaximumTrials {
+		a := 1 + n/2
+		b := n + 1 - a
+		x := betaVariate(rng, float64(a), float64(b))
+
+		if x >= p {
+			n, p = a-1, p/x
+		} else {
+			successes += a
+			n, p = b-1, (p-x)/(1-x)
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		if rng.Float64() < p {
+			successes++
+		}
+	}
+
+	return successes
+}
+
+// poissonVariate draws from the Poisson distribution by repeatedly drawing the arrival time of a large number of
+// events from the gamma distribution (Knuth, TAOCP Vol. 2, §3.4.1), then counting the remaining arrivals by
+// multiplying uniform variates.
+func poissonVariate(rng *rand.Rand, mean float64) int {
+	count := 0
+
+	for mean > poissonVariateDirectMaximumMean {
+		m := int(math.Floor(mean * 7 / 8))
+		x := gammaVariate(rng, float64(m))
+
+		if x >= mean {
+			return count + binomialVariate(rng, m-1, mean/x)
+		}
+
+		count += m
+		mean -= x
+	}
+
+	threshold := math.Exp(-mean)
+	product := 1 - rng.Float64()
+	for product > threshold {
+		count++
+		product *= 1 - rng.Float64()
+	}
+
+	return count
+}
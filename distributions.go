@@ -0,0 +1,112 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// A Distribution is a probability distribution over the real numbers.  CDF returns the probability that a variate
+// is less than or equal to x.  Quantile returns the smallest x for which CDF(x) is at least p, and returns NaN if p
+// is not in [0, 1].  Mean and Variance return NaN when the moment is undefined and +Inf when it is infinite.  Rand
+// draws a variate using rng, which must not be nil.
+type Distribution interface {
+	CDF(x float64) float64
+	Quantile(p float64) float64
+	Mean() float64
+	Variance() float64
+	Rand(rng *rand.Rand) float64
+}
+
+// A ContinuousDistribution is a Distribution with a probability density function.
+type ContinuousDistribution interface {
+	Distribution
+	PDF(x float64) float64
+}
+
+// A DiscreteDistribution is a Distribution over the integers, with a probability mass function.
+type DiscreteDistribution interface {
+	Distribution
+	PMF(k int) float64
+}
+
+// MakeStatisticalSampleSetFromDistribution returns a set of n variates drawn from distribution using rng.  Using an
+// rng with a fixed seed makes the set reproducible.
+func MakeStatisticalSampleSetFromDistribution(distribution Distribution, n int, rng *rand.Rand) (*StatisticalSampleSet, error) {
+	if distribution == nil {
+		return nil, fmt.Errorf("distribution must not be nil")
+	}
+
+	if rng == nil {
+		return nil, fmt.Errorf("random number generator must not be nil")
+	}
+
+	if n < 1 {
+		return nil, fmt.Errorf("there must be at least one sample in the set")
+	}
+
+	samples := make([]float64, n)
+	for i := range samples {
+		samples[i] = distribution.Rand(rng)
+	}
+
+	return MakeStatisticalSampleSetFrom(samples)
+}
+
+func probabilityIsNotValid(p float64) bool {
+	return !(p >= 0 && p <= 1)
+}
+
+// discreteQuantileBySearch returns the smallest integer k in [lowest, highest] for which cdf(k) >= p, starting the
+// search from guess.  highest is +Inf for distributions with unbounded support.
+func discreteQuantileBySearch(cdf func(k float64) float64, p float64, guess float64, lowest float64, highest float64) float64 {
+	if math.IsNaN(guess) {
+		guess = lowest
+	}
+
+	k := math.Max(lowest, math.Min(highest, math.Floor(guess)))
+
+	// allow for rounding in cdf, so that p = cdf(k) for some k yields k rather than k + 1
+	target := p * (1 - 64*2.220446049250313e-16)
+
+	if cdf(k) >= target {
+		step := float64(1)
+		for k > lowest {
+			candidate := math.Max(lowest, k-step)
+			if cdf(candidate) < target {
+				lower, upper := candidate, k
+				for upper-lower > 1 {
+					middle := math.Floor((lower + upper) / 2)
+					if cdf(middle) >= target {
+						upper = middle
+					} else {
+						lower = middle
+					}
+				}
+				return upper
+			}
+			k = candidate
+			step *= 2
+		}
+		return k
+	}
+
+	step := float64(1)
+	for {
+		candidate := math.Min(highest, k+step)
+		if cdf(candidate) >= target || candidate == highest {
+			lower, upper := k, candidate
+			for upper-lower > 1 {
+				middle := math.Floor((lower + upper) / 2)
+				if cdf(middle) >= target {
+					upper = middle
+				} else {
+					lower = middle
+				}
+			}
+			return upper
+		}
+		k = candidate
+		step *= 2
+	}
+}
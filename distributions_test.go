@@ -0,0 +1,293 @@
+package stats_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+func mustDistribution[T stats.Distribution](t *testing.T, distribution T, err error) T {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("on constructing distribution got error: %s", err.Error())
+	}
+	return distribution
+}
+
+func TestDistributionsAgainstR(t *testing.T) {
+	normal, err := stats.NewNormalDistribution(0, 1)
+	mustDistribution(t, normal, err)
+	studentT, err := stats.NewStudentTDistribution(5)
+	mustDistribution(t, studentT, err)
+	cauchy, err := stats.NewStudentTDistribution(1)
+	mustDistribution(t, cauchy, err)
+	chiSquared1, err := stats.NewChiSquaredDistribution(1)
+	mustDistribution(t, chiSquared1, err)
+	chiSquared10, err := stats.NewChiSquaredDistribution(10)
+	mustDistribution(t, chiSquared10, err)
+	f, err := stats.NewFDistribution(4, 10)
+	mustDistribution(t, f, err)
+	f22, err := stats.NewFDistribution(2, 2)
+	mustDistribution(t, f22, err)
+	gamma, err := stats.NewGammaDistribution(3, 1)
+	mustDistribution(t, gamma, err)
+	beta, err := stats.NewBetaDistribution(2, 5)
+	mustDistribution(t, beta, err)
+	exponential, err := stats.NewExponentialDistribution(2)
+	mustDistribution(t, exponential, err)
+	weibull, err := stats.NewWeibullDistribution(2, 1)
+	mustDistribution(t, weibull, err)
+	logNormal, err := stats.NewLogNormalDistribution(0, 1)
+	mustDistribution(t, logNormal, err)
+	pareto, err := stats.NewParetoDistribution(1, 3)
+	mustDistribution(t, pareto, err)
+	uniform, err := stats.NewUniformDistribution(-1, 3)
+	mustDistribution(t, uniform, err)
+	binomial, err := stats.NewBinomialDistribution(10, 0.3)
+	mustDistribution(t, binomial, err)
+	poisson, err := stats.NewPoissonDistribution(2.5)
+	mustDistribution(t, poisson, err)
+	geometric, err := stats.NewGeometricDistribution(0.3)
+	mustDistribution(t, geometric, err)
+	negativeBinomial, err := stats.NewNegativeBinomialDistribution(3, 0.5)
+	mustDistribution(t, negativeBinomial, err)
+
+	for testIndex, testCase := range []struct {
+		description string
+		got         float64
+		expected    float64
+	}{
+		{"pnorm(1.96)", normal.CDF(1.96), 0.9750021},
+		{"qnorm(0.975)", normal.Quantile(0.975), 1.959964},
+		{"dnorm(0)", normal.PDF(0), 0.3989423},
+		{"pt(2, 5)", studentT.CDF(2), 0.9490303},
+		{"qt(0.975, 5)", studentT.Quantile(0.975), 2.570582},
+		{"dt(0, 1)", cauchy.PDF(0), 0.3183099},
+		{"qchisq(0.95, 1)", chiSquared1.Quantile(0.95), 3.841459},
+		{"pchisq(10, 10)", chiSquared10.CDF(10), 0.5595067},
+		{"qchisq(0.95, 10)", chiSquared10.Quantile(0.95), 18.30704},
+		{"qf(0.95, 4, 10)", f.Quantile(0.95), 3.478050},
+		{"df(1, 2, 2)", f22.PDF(1), 0.25},
+		{"pf(1, 2, 2)", f22.CDF(1), 0.5},
+		{"pgamma(2, 3)", gamma.CDF(2), 0.3233236},
+		{"qgamma(0.5, 3)", gamma.Quantile(0.5), 2.674060},
+		{"dgamma(1, 3)", gamma.PDF(1), 0.1839397},
+		{"pbeta(0.3, 2, 5)", beta.CDF(0.3), 0.579825},
+		{"qbeta(0.5, 2, 5)", beta.Quantile(0.5), 0.2644500},
+		{"pexp(1, 2)", exponential.CDF(1), 0.8646647},
+		{"qexp(0.5, 2)", exponential.Quantile(0.5), 0.3465736},
+		{"pweibull(1, 2, 1)", weibull.CDF(1), 0.6321206},
+		{"plnorm(1)", logNormal.CDF(1), 0.5},
+		{"qlnorm(0.975)", logNormal.Quantile(0.975), 7.099071},
+		{"Pareto CDF at 2", pareto.CDF(2), 0.875},
+		{"Pareto quantile at 0.875", pareto.Quantile(0.875), 2},
+		{"punif(0, -1, 3)", uniform.CDF(0), 0.25},
+		{"pbinom(3, 10, 0.3)", binomial.CDF(3), 0.6496107},
+		{"dbinom(3, 10, 0.3)", binomial.PMF(3), 0.2668279},
+		{"qbinom(0.5, 10, 0.3)", binomial.Quantile(0.5), 3},
+		{"qbinom(0.6496107, 10, 0.3)", binomial.Quantile(binomial.CDF(3)), 3},
+		{"ppois(3, 2.5)", poisson.CDF(3), 0.7575761},
+		{"dpois(2, 2.5)", poisson.PMF(2), 0.2565156},
+		{"qpois(0.5, 2.5)", poisson.Quantile(0.5), 2},
+		{"qpois(0.99, 2.5)", poisson.Quantile(0.99), 7},
+		{"pgeom(2, 0.3)", geometric.CDF(2), 0.657},
+		{"qgeom(0.657, 0.3)", geometric.Quantile(0.657), 2},
+		{"pnbinom(2, 3, 0.5)", negativeBinomial.CDF(2), 0.5},
+		{"dnbinom(2, 3, 0.5)", negativeBinomial.PMF(2), 0.1875},
+		{"qnbinom(0.5, 3, 0.5)", negativeBinomial.Quantile(0.5), 2},
+	} {
+		if math.Abs(testCase.got-testCase.expected) > 1e-6*math.Max(1, math.Abs(testCase.expected)) {
+			t.Errorf("on test with index (%d), %s: expected (%f), got (%f)", testIndex, testCase.description, testCase.expected, testCase.got)
+		}
+	}
+
+	if !math.IsNaN(cauchy.Mean()) {
+		t.Errorf("expected Mean() of Student's t with one degree of freedom to be NaN, got (%f)", cauchy.Mean())
+	}
+
+	if pareto.Variance() != 0.75 {
+		t.Errorf("expected Variance() of Pareto distribution to be (0.75), got (%f)", pareto.Variance())
+	}
+
+	if !math.IsNaN(normal.Quantile(1.5)) {
+		t.Errorf("on Quantile(1.5) expected NaN, got (%f)", normal.Quantile(1.5))
+	}
+}
+
+func TestDistributionQuantileInvertsCDF(t *testing.T) {
+	var distributions []stats.ContinuousDistribution
+	for _, construct := range []func() (stats.ContinuousDistribution, error){
+		func() (stats.ContinuousDistribution, error) { return stats.NewNormalDistribution(10, 3) },
+		func() (stats.ContinuousDistribution, error) { return stats.NewLogNormalDistribution(1, 0.5) },
+		func() (stats.ContinuousDistribution, error) { return stats.NewStudentTDistribution(2.5) },
+		func() (stats.ContinuousDistribution, error) { return stats.NewChiSquaredDistribution(0.5) },
+		func() (stats.ContinuousDistribution, error) { return stats.NewFDistribution(3, 7) },
+		func() (stats.ContinuousDistribution, error) { return stats.NewExponentialDistribution(0.1) },
+		func() (stats.ContinuousDistribution, error) { return stats.NewGammaDistribution(0.3, 4) },
+		func() (stats.ContinuousDistribution, error) { return stats.NewGammaDistribution(50, 0.5) },
+		func() (stats.ContinuousDistribution, error) { return stats.NewBetaDistribution(0.5, 0.5) },
+		func() (stats.ContinuousDistribution, error) { return stats.NewWeibullDistribution(0.7, 2) },
+		func() (stats.ContinuousDistribution, error) { return stats.NewParetoDistribution(2, 1.5) },
+		func() (stats.ContinuousDistribution, error) { return stats.NewUniformDistribution(5, 6) },
+	} {
+		distribution, err := construct()
+		if err != nil {
+			t.Fatalf("on constructing distribution got error: %s", err.Error())
+		}
+		distributions = append(distributions, distribution)
+	}
+
+	for distributionIndex, distribution := range distributions {
+		for _, p := range []float64{0.001, 0.05, 0.3, 0.5, 0.8, 0.99, 0.9999} {
+			x := distribution.Quantile(p)
+			if got := distribution.CDF(x); math.Abs(got-p) > 1e-9 {
+				t.Errorf("on distribution with index (%d), expected CDF(Quantile(%f)) to be (%f), got (%f)", distributionIndex, p, p, got)
+			}
+
+			// the density is the derivative of the distribution function, away from any singularities at the ends
+			if p < 0.05 || p > 0.99 {
+				continue
+			}
+
+			h := 1e-6 * math.Abs(x)
+			numericalDerivative := (distribution.CDF(x+h) - distribution.CDF(x-h)) / (2 * h)
+			if math.Abs(numericalDerivative/distribution.PDF(x)-1) > 1e-4 {
+				t.Errorf("on distribution with index (%d) at (%f), expected PDF (%f), got (%f)", distributionIndex, x, numericalDerivative, distribution.PDF(x))
+			}
+		}
+	}
+}
+
+func TestDiscreteDistributionPMFSumsToCDF(t *testing.T) {
+	var distributions []stats.DiscreteDistribution
+	for _, construct := range []func() (stats.DiscreteDistribution, error){
+		func() (stats.DiscreteDistribution, error) { return stats.NewBinomialDistribution(1000, 0.02) },
+		func() (stats.DiscreteDistribution, error) { return stats.NewPoissonDistribution(40) },
+		func() (stats.DiscreteDistribution, error) { return stats.NewGeometricDistribution(0.05) },
+		func() (stats.DiscreteDistribution, error) { return stats.NewNegativeBinomialDistribution(2.5, 0.2) },
+	} {
+		distribution, err := construct()
+		if err != nil {
+			t.Fatalf("on constructing distribution got error: %s", err.Error())
+		}
+		distributions = append(distributions, distribution)
+	}
+
+	for distributionIndex, distribution := range distributions {
+		cumulative := float64(0)
+		for k := 0; k < 100; k++ {
+			cumulative += distribution.PMF(k)
+			if got := distribution.CDF(float64(k) + 0.5); math.Abs(got-cumulative) > 1e-9 {
+				t.Fatalf("on distribution with index (%d), expected CDF(%d) (%f), got (%f)", distributionIndex, k, cumulative, got)
+			}
+
+			if p := distribution.CDF(float64(k)); p < 1-1e-9 {
+				if got := distribution.Quantile(p); got != float64(k) {
+					t.Fatalf("on distribution with index (%d), expected Quantile(%f) (%d), got (%f)", distributionIndex, p, k, got)
+				}
+
+				between := (p + distribution.CDF(float64(k+1))) / 2
+				if got := distribution.Quantile(between); got != float64(k+1) {
+					t.Fatalf("on distribution with index (%d), expected Quantile(%f) (%d), got (%f)", distributionIndex, between, k+1, got)
+				}
+			}
+		}
+	}
+}
+
+func TestBinomialQuantileOfOne(t *testing.T) {
+	for testIndex, testCase := range []struct {
+		numberOfTrials       int
+		probabilityOfSuccess float64
+		expected             float64
+	}{
+		{1000000, 0.3, 1000000},
+		{10, 0.3, 10},
+		{10, 0, 0},
+		{10, 1, 10},
+		{0, 0.5, 0},
+	} {
+		binomial, _ := stats.NewBinomialDistribution(testCase.numberOfTrials, testCase.probabilityOfSuccess)
+
+		if got := binomial.Quantile(1); got != testCase.expected {
+			t.Errorf("on test with index (%d) expected Quantile(1) (%f), got (%f)", testIndex, testCase.expected, got)
+		}
+	}
+}
+
+func TestRandomVariatesMatchMoments(t *testing.T) {
+	rng := rand.New(rand.NewSource(17))
+
+	var distributions []stats.Distribution
+	for _, construct := range []func() (stats.Distribution, error){
+		func() (stats.Distribution, error) { return stats.NewNormalDistribution(-4, 2) },
+		func() (stats.Distribution, error) { return stats.NewLogNormalDistribution(0, 0.5) },
+		func() (stats.Distribution, error) { return stats.NewStudentTDistribution(6) },
+		func() (stats.Distribution, error) { return stats.NewChiSquaredDistribution(3) },
+		func() (stats.Distribution, error) { return stats.NewFDistribution(5, 12) },
+		func() (stats.Distribution, error) { return stats.NewExponentialDistribution(3) },
+		func() (stats.Distribution, error) { return stats.NewGammaDistribution(0.4, 2) },
+		func() (stats.Distribution, error) { return stats.NewBetaDistribution(2, 3) },
+		func() (stats.Distribution, error) { return stats.NewWeibullDistribution(1.5, 2) },
+		func() (stats.Distribution, error) { return stats.NewParetoDistribution(1, 5) },
+		func() (stats.Distribution, error) { return stats.NewUniformDistribution(0, 10) },
+		func() (stats.Distribution, error) { return stats.NewBinomialDistribution(500, 0.3) },
+		func() (stats.Distribution, error) { return stats.NewPoissonDistribution(120) },
+		func() (stats.Distribution, error) { return stats.NewPoissonDistribution(3) },
+		func() (stats.Distribution, error) { return stats.NewGeometricDistribution(0.25) },
+		func() (stats.Distribution, error) { return stats.NewNegativeBinomialDistribution(4, 0.4) },
+	} {
+		distribution, err := construct()
+		if err != nil {
+			t.Fatalf("on constructing distribution got error: %s", err.Error())
+		}
+		distributions = append(distributions, distribution)
+	}
+
+	const n = 20000
+
+	for distributionIndex, distribution := range distributions {
+		set, err := stats.MakeStatisticalSampleSetFromDistribution(distribution, n, rng)
+		if err != nil {
+			t.Fatalf("on distribution with index (%d) got error: %s", distributionIndex, err.Error())
+		}
+
+		standardErrorOfMean := math.Sqrt(distribution.Variance() / n)
+		if math.Abs(set.Mean()-distribution.Mean()) > 5*standardErrorOfMean {
+			t.Errorf("on distribution with index (%d), expected sample mean near (%f), got (%f)", distributionIndex, distribution.Mean(), set.Mean())
+		}
+
+		if relativeError := math.Abs(set.SampleVariance()/distribution.Variance() - 1); relativeError > 0.1 {
+			t.Errorf("on distribution with index (%d), expected sample variance near (%f), got (%f)", distributionIndex, distribution.Variance(), set.SampleVariance())
+		}
+	}
+}
+
+func TestDistributionConstructorErrors(t *testing.T) {
+	for testIndex, err := range []error{
+		second(stats.NewNormalDistribution(0, 0)),
+		second(stats.NewStudentTDistribution(math.NaN())),
+		second(stats.NewGammaDistribution(1, -1)),
+		second(stats.NewBetaDistribution(0, 1)),
+		second(stats.NewUniformDistribution(1, 1)),
+		second(stats.NewBinomialDistribution(-1, 0.5)),
+		second(stats.NewBinomialDistribution(10, 1.5)),
+		second(stats.NewGeometricDistribution(0)),
+		second(stats.NewNegativeBinomialDistribution(0, 0.5)),
+	} {
+		if err == nil {
+			t.Errorf("on test with index (%d) expected error, got none", testIndex)
+		}
+	}
+
+	normal, _ := stats.NewNormalDistribution(0, 1)
+	if _, err := stats.MakeStatisticalSampleSetFromDistribution(normal, 10, nil); err == nil {
+		t.Errorf("on MakeStatisticalSampleSetFromDistribution() with nil rng, expected error, got none")
+	}
+}
+
+func second[T any](_ T, err error) error {
+	return err
+}
@@ -0,0 +1,225 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// An EffectSize is an estimate of the magnitude of the difference between two populations, with a confidence
+// interval.  Unlike a p-value, it does not depend on the size of the sets, except through the width of the interval.
+type EffectSize struct {
+	Estimate           float64
+	ConfidenceInterval Interval
+}
+
+// CohensD returns Cohen's d, the difference between the means of a and b divided by their pooled standard deviation.
+// The confidence interval uses the normal approximation to the sampling distribution of d (Hedges and Olkin, 1985),
+// which is adequate when each set has at least 10 values.  Each set must have at least two values, and the confidence
+// level must be greater than 0 and less than 1.
+func CohensD(a *StatisticalSampleSet, b *StatisticalSampleSet, confidenceLevel float64) (*EffectSize, error) {
+	d, n1, n2, err := standardizedMeanDifferenceWithPooledStdev(a, b, confidenceLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	standardError := math.Sqrt((n1+n2)/(n1*n2) + d*d/(2*(n1+n2)))
+
+	return normalApproximationEffectSize(d, standardError, confidenceLevel), nil
+}
+
+// HedgesG returns Hedges' g, which is Cohen's d multiplied by the exact correction for its bias in small sets.  The
+// confidence interval is that of Cohen's d, multiplied by the same correction.
+func HedgesG(a *StatisticalSampleSet, b *StatisticalSampleSet, confidenceLevel float64) (*EffectSize, error) {
+	d, n1, n2, err := standardizedMeanDifferenceWithPooledStdev(a, b, confidenceLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	degreesOfFreedom := n1 + n2 - 2
+	correction := math.Exp(LogGamma(degreesOfFreedom/2) - LogGamma((degreesOfFreedom-1)/2) - 0.5*math.Log(degreesOfFreedom/2))
+
+	standardError := math.Sqrt((n1+n2)/(n1*n2) + d*d/(2*(n1+n2)))
+
+	return normalApproximationEffectSize(correction*d, correction*standardError, confidenceLevel), nil
+}
+
+// GlassDelta returns Glass's delta, the difference between the means of a and b divided by the standard deviation of
+// b, which is treated as the control group.  It is preferable to Cohen's d when the sets have different variances.
+// The confidence interval uses the normal approximation to the sampling distribution of delta.
+func GlassDelta(a *StatisticalSampleSet, b *StatisticalSampleSet, confidenceLevel float64) (*EffectSize, error) {
+	if err := errorIfEffectSizeParametersAreNotValid(a, b, confidenceLevel); err != nil {
+		return nil, err
+	}
+
+	n1, n2 := float64(a.Count()), float64(b.Count())
+	delta := (a.Mean() - b.Mean()) / b.SampleStdev()
+
+	standardError := math.Sqrt((n1+n2)/(n1*n2) + delta*delta/(2*(n2-1)))
+
+	return normalApproximationEffectSize(delta, standardError, confidenceLevel), nil
+}
+
+// CommonLanguageEffectSize returns McGraw and Wong's common-language effect size: the probability that a value drawn
+// at random from the population of a is larger than one drawn from the population of b, assuming that both
+// populations are normally distributed.  The confidence interval is computed for the standardized difference between
+// the means using the normal approximation, then transformed to a probability.  VarghaDelaneyA estimates the same
+// probability without assuming normality.
+func CommonLanguageEffectSize(a *StatisticalSampleSet, b *StatisticalSampleSet, confidenceLevel float64) (*EffectSize, error) {
+	if err := errorIfEffectSizeParametersAreNotValid(a, b, confidenceLevel); err != nil {
+		return nil, err
+	}
+
+	n1, n2 := float64(a.Count()), float64(b.Count())
+	variance1, variance2 := a.SampleVariance(), b.SampleVariance()
+	sumOfVariances := variance1 + variance2
+
+	z := (a.Mean() - b.Mean()) / math.Sqrt(sumOfVariances)
+
+	standardError := math.Sqrt((variance1/n1+variance2/n2)/sumOfVariances +
+		z*z*(variance1*variance1/(n1-1)+variance2*variance2/(n2-1))/(2*sumOfVariances*sumOfVariances))
+
+	standardized := normalApproximationEffectSize(z, standardError, confidenceLevel)
+
+	return &EffectSize{
+		Estimate: standardNormalCDF(z),
+		ConfidenceInterval: Interval{
+			Lower:  standardNormalCDF(standardized.ConfidenceInterval.Lower),
+			Upper:  standardNormalCDF(standardized.ConfidenceInterval.Upper),
+			Level:  confidenceLevel,
+			Method: IntervalNormalApproximation,
+		},
+	}, nil
+}
+
+// CliffsDelta returns Cliff's delta, the probability that a value drawn at random from the population of a is larger
+// than one drawn from the population of b, minus the probability that it is smaller.  It ranges from -1 to 1 and
+// makes no assumption about the populations.  The confidence interval is Cliff's asymmetric interval using his
+// unbiased estimate of the variance of delta (Cliff, "Dominance Statistics", 1993).  Computing it requires time
+// proportional to (n + m) log(n + m) rather than to the number of pairs.
+func CliffsDelta(a *StatisticalSampleSet, b *StatisticalSampleSet, confidenceLevel float64) (*EffectSize, error) {
+	if err := errorIfEffectSizeParametersAreNotValid(a, b, confidenceLevel); err != nil {
+		return nil, err
+	}
+
+	sortedA, sortedB := a.snapshotOfSortedValues(), b.snapshotOfSortedValues()
+	n1, n2 := float64(len(sortedA)), float64(len(sortedB))
+
+	dominanceOfEachValueOfA, numberOfTiedPairs := meanDominanceOfEachValue(sortedA, sortedB)
+	dominanceOfEachValueOfB, _ := meanDominanceOfEachValue(sortedB, sortedA)
+
+	delta := float64(0)
+	for _, dominance := range dominanceOfEachValueOfA {
+		delta += dominance
+	}
+	delta /= n1
+
+	sumOfSquaredDeviationsOfA := float64(0)
+	for _, dominance := range dominanceOfEachValueOfA {
+		sumOfSquaredDeviationsOfA += (dominance - delta) * (dominance - delta)
+	}
+
+	// the dominance of each value of b is measured against a, so it is the negation of its dominance in delta
+	sumOfSquaredDeviationsOfB := float64(0)
+	for _, dominance := range dominanceOfEachValueOfB {
+		sumOfSquaredDeviationsOfB += (dominance + delta) * (dominance + delta)
+	}
+
+	// each untied pair contributes 1 to the sum of the squared dominances
+	sumOfSquaredDeviationsOfPairs := n1*n2 - numberOfTiedPairs - n1*n2*delta*delta
+
+	variance := (n2*n2*sumOfSquaredDeviationsOfA + n1*n1*sumOfSquaredDeviationsOfB - sumOfSquaredDeviationsOfPairs) / (n1 * n2 * (n1 - 1) * (n2 - 1))
+	variance = math.Max(variance, (1-delta*delta)/(n1*n2-1))
+
+	z := standardNormalQuantile((1 + confidenceLevel) / 2)
+	standardError := math.Sqrt(variance)
+
+	interval := Interval{Lower: delta, Upper: delta, Level: confidenceLevel, Method: IntervalCliffAsymmetric}
+
+	if denominator := 1 - delta*delta + z*z*variance; denominator > 0 {
+		center := delta - delta*delta*delta
+		margin := z * standardError * math.Sqrt((1-delta*delta)*(1-delta*delta)+z*z*variance)
+
+		interval.Lower = math.Max(-1, (center-margin)/denominator)
+		interval.Upper = math.Min(1, (center+margin)/denominator)
+	}
+
+	return &EffectSize{Estimate: delta, ConfidenceInterval: interval}, nil
+}
+
+// VarghaDelaneyA returns the Vargha–Delaney A statistic, the probability that a value drawn at random from the
+// population of a is larger than one drawn from the population of b, plus half the probability that they are equal.
+// It is the Mann–Whitney U statistic divided by the number of pairs, and equals (1 + delta) / 2, where delta is
+// Cliff's delta; its confidence interval is transformed from that of CliffsDelta.  Values of 0.56, 0.64 and 0.71 are
+// conventionally considered small, medium and large effects.
+func VarghaDelaneyA(a *StatisticalSampleSet, b *StatisticalSampleSet, confidenceLevel float64) (*EffectSize, error) {
+	delta, err := CliffsDelta(a, b, confidenceLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EffectSize{
+		Estimate: (1 + delta.Estimate) / 2,
+		ConfidenceInterval: Interval{
+			Lower:  (1 + delta.ConfidenceInterval.Lower) / 2,
+			Upper:  (1 + delta.ConfidenceInterval.Upper) / 2,
+			Level:  confidenceLevel,
+			Method: IntervalCliffAsymmetric,
+		},
+	}, nil
+}
+
+func errorIfEffectSizeParametersAreNotValid(a *StatisticalSampleSet, b *StatisticalSampleSet, confidenceLevel float64) error {
+	if err := errorIfConfidenceLevelIsNotValid(confidenceLevel); err != nil {
+		return err
+	}
+
+	if a.Count() < 2 || b.Count() < 2 {
+		return fmt.Errorf("there must be at least two samples in each set")
+	}
+
+	return nil
+}
+
+func standardizedMeanDifferenceWithPooledStdev(a *StatisticalSampleSet, b *StatisticalSampleSet, confidenceLevel float64) (d float64, n1 float64, n2 float64, err error) {
+	if err := errorIfEffectSizeParametersAreNotValid(a, b, confidenceLevel); err != nil {
+		return 0, 0, 0, err
+	}
+
+	n1, n2 = float64(a.Count()), float64(b.Count())
+	pooledVariance := ((n1-1)*a.SampleVariance() + (n2-1)*b.SampleVariance()) / (n1 + n2 - 2)
+
+	return (a.Mean() - b.Mean()) / math.Sqrt(pooledVariance), n1, n2, nil
+}
+
+func normalApproximationEffectSize(estimate float64, standardError float64, confidenceLevel float64) *EffectSize {
+	margin := standardNormalQuantile((1+confidenceLevel)/2) * standardError
+
+	return &EffectSize{
+		Estimate: estimate,
+		ConfidenceInterval: Interval{
+			Lower:  estimate - margin,
+			Upper:  estimate + margin,
+			Level:  confidenceLevel,
+			Method: IntervalNormalApproximation,
+		},
+	}
+}
+
+// meanDominanceOfEachValue returns, for each value x in sortedValues, the number of values in sortedOthers that are
+// less than x minus the number that are greater, divided by the number of others.  It also returns the number of
+// pairs of values that are equal.
+func meanDominanceOfEachValue(sortedValues []float64, sortedOthers []float64) (dominances []float64, numberOfTiedPairs float64) {
+	dominances = make([]float64, len(sortedValues))
+
+	for i, value := range sortedValues {
+		less := sort.SearchFloat64s(sortedOthers, value)
+		lessOrEqual := sort.SearchFloat64s(sortedOthers, math.Nextafter(value, math.Inf(1)))
+		greater := len(sortedOthers) - lessOrEqual
+
+		dominances[i] = float64(less-greater) / float64(len(sortedOthers))
+		numberOfTiedPairs += float64(lessOrEqual - less)
+	}
+
+	return dominances, numberOfTiedPairs
+}
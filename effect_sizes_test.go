@@ -0,0 +1,130 @@
+package stats_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+func TestEffectSizesOfSleepData(t *testing.T) {
+	group1, _ := stats.MakeStatisticalSampleSetFrom(sleepGroup1)
+	group2, _ := stats.MakeStatisticalSampleSetFrom(sleepGroup2)
+
+	// Cliff's delta is computed here from its definition over all 100 pairs, which include two ties
+	for testIndex, testCase := range []struct {
+		name           string
+		compute        func(a *stats.StatisticalSampleSet, b *stats.StatisticalSampleSet, level float64) (*stats.EffectSize, error)
+		expected       stats.EffectSize
+		expectedMethod stats.IntervalMethod
+	}{
+		{"CohensD", stats.CohensD, stats.EffectSize{Estimate: -0.8321811, ConfidenceInterval: stats.Interval{Lower: -1.7458547, Upper: 0.0814925}}, stats.IntervalNormalApproximation},
+		{"HedgesG", stats.HedgesG, stats.EffectSize{Estimate: -0.7969352, ConfidenceInterval: stats.Interval{Lower: -1.6719115, Upper: 0.0780410}}, stats.IntervalNormalApproximation},
+		{"GlassDelta", stats.GlassDelta, stats.EffectSize{Estimate: -0.7891127, ConfidenceInterval: stats.Interval{Lower: -1.7384202, Upper: 0.1601947}}, stats.IntervalNormalApproximation},
+		{"CommonLanguageEffectSize", stats.CommonLanguageEffectSize, stats.EffectSize{Estimate: 0.2781182, ConfidenceInterval: stats.Interval{Lower: 0.1079105, Upper: 0.5242547}}, stats.IntervalNormalApproximation},
+		{"CliffsDelta", stats.CliffsDelta, stats.EffectSize{Estimate: -0.49, ConfidenceInterval: stats.Interval{Lower: -0.7931064, Upper: 0.0076294}}, stats.IntervalCliffAsymmetric},
+		{"VarghaDelaneyA", stats.VarghaDelaneyA, stats.EffectSize{Estimate: 0.255, ConfidenceInterval: stats.Interval{Lower: 0.1034468, Upper: 0.5038147}}, stats.IntervalCliffAsymmetric},
+	} {
+		got, err := testCase.compute(group1, group2, 0.95)
+		if err != nil {
+			t.Errorf("on test with index (%d) (%s) got unexpected error: %s", testIndex, testCase.name, err)
+			continue
+		}
+
+		if math.Abs(got.Estimate-testCase.expected.Estimate) > 1e-6 {
+			t.Errorf("on test with index (%d) (%s) expected Estimate (%f), got (%f)", testIndex, testCase.name, testCase.expected.Estimate, got.Estimate)
+		}
+
+		if math.Abs(got.ConfidenceInterval.Lower-testCase.expected.ConfidenceInterval.Lower) > 1e-6 || math.Abs(got.ConfidenceInterval.Upper-testCase.expected.ConfidenceInterval.Upper) > 1e-6 {
+			t.Errorf("on test with index (%d) (%s) expected interval %s, got %s", testIndex, testCase.name, &testCase.expected.ConfidenceInterval, &got.ConfidenceInterval)
+		}
+
+		if got.ConfidenceInterval.Level != 0.95 || got.ConfidenceInterval.Method != testCase.expectedMethod {
+			t.Errorf("on test with index (%d) (%s) expected Level (0.95) and Method (%s), got (%f) and (%s)", testIndex, testCase.name, testCase.expectedMethod, got.ConfidenceInterval.Level, got.ConfidenceInterval.Method)
+		}
+	}
+
+	// the Vargha–Delaney A statistic is the Mann–Whitney U statistic divided by the number of pairs
+	mannWhitney, _ := stats.MannWhitneyUTest(group1, group2, stats.AlternativeTwoSided, 0.95)
+	a, _ := stats.VarghaDelaneyA(group1, group2, 0.95)
+	if math.Abs(a.Estimate-mannWhitney.Statistic/100) > 1e-12 {
+		t.Errorf("expected VarghaDelaneyA Estimate to be U / 100 (%f), got (%f)", mannWhitney.Statistic/100, a.Estimate)
+	}
+}
+
+func TestCliffsDeltaOfSeparatedSets(t *testing.T) {
+	a, _ := stats.MakeStatisticalSampleSetFrom([]float64{5, 6, 7})
+	b, _ := stats.MakeStatisticalSampleSetFrom([]float64{1, 2, 3, 4})
+
+	delta, err := stats.CliffsDelta(a, b, 0.95)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	if delta.Estimate != 1 || delta.ConfidenceInterval.Lower != 1 || delta.ConfidenceInterval.Upper != 1 {
+		t.Errorf("expected Estimate (1) and interval [1, 1], got (%f) and %s", delta.Estimate, &delta.ConfidenceInterval)
+	}
+
+	reversed, _ := stats.CliffsDelta(b, a, 0.95)
+	if reversed.Estimate != -1 {
+		t.Errorf("expected Estimate (-1) with sets reversed, got (%f)", reversed.Estimate)
+	}
+}
+
+func TestEffectSizeConfidenceIntervalCoverage(t *testing.T) {
+	control, _ := stats.NewNormalDistribution(0, 1)
+	treatment, _ := stats.NewNormalDistribution(0.5, 1)
+	rng := rand.New(rand.NewSource(31))
+
+	// for these populations, d is 0.5 and delta is 2 * Phi(0.5 / sqrt(2)) - 1
+	trueCliffsDelta := 2*control.CDF(0.5/math.Sqrt2) - 1
+
+	coveredD, coveredDelta := 0, 0
+	for i := 0; i < 400; i++ {
+		a, _ := stats.MakeStatisticalSampleSetFromDistribution(treatment, 30, rng)
+		b, _ := stats.MakeStatisticalSampleSetFromDistribution(control, 30, rng)
+
+		d, _ := stats.CohensD(a, b, 0.95)
+		if d.ConfidenceInterval.Lower <= 0.5 && 0.5 <= d.ConfidenceInterval.Upper {
+			coveredD++
+		}
+
+		delta, _ := stats.CliffsDelta(a, b, 0.95)
+		if delta.ConfidenceInterval.Lower <= trueCliffsDelta && trueCliffsDelta <= delta.ConfidenceInterval.Upper {
+			coveredDelta++
+		}
+	}
+
+	for _, coverage := range []struct {
+		name    string
+		covered int
+	}{{"CohensD", coveredD}, {"CliffsDelta", coveredDelta}} {
+		if coverage.covered < 365 || coverage.covered > 395 {
+			t.Errorf("on %s expected about 380 of 400 intervals to cover the true effect size, got (%d)", coverage.name, coverage.covered)
+		}
+	}
+}
+
+func TestEffectSizeErrors(t *testing.T) {
+	single, _ := stats.MakeStatisticalSampleSetFrom([]float64{1})
+	set, _ := stats.MakeStatisticalSampleSetFrom([]float64{1, 2, 3})
+
+	for _, compute := range []func(a *stats.StatisticalSampleSet, b *stats.StatisticalSampleSet, level float64) (*stats.EffectSize, error){
+		stats.CohensD, stats.HedgesG, stats.GlassDelta, stats.CommonLanguageEffectSize, stats.CliffsDelta, stats.VarghaDelaneyA,
+	} {
+		for testIndex, testCase := range []struct {
+			a, b  *stats.StatisticalSampleSet
+			level float64
+		}{
+			{single, set, 0.95},
+			{set, single, 0.95},
+			{set, set, 0},
+			{set, set, 1},
+		} {
+			if _, err := compute(testCase.a, testCase.b, testCase.level); err == nil {
+				t.Errorf("on test with index (%d) expected error, got none", testIndex)
+			}
+		}
+	}
+}
@@ -0,0 +1,644 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// The exact null distribution of the two sample Kolmogorov–Smirnov statistic is used when the product of the sizes
+// of the sets is less than this and there are no ties.
+const maximumSizeProductForExactTwoSampleKolmogorovSmirnovTest = 10000
+
+// The exact null distribution of the one sample Kolmogorov–Smirnov statistic is used when the set has fewer than
+// this many values and there are no ties.
+const maximumSamplesForExactOneSampleKolmogorovSmirnovTest = 100
+
+// A KolmogorovSmirnovTestResult is the result of a one or two sample Kolmogorov–Smirnov test.  Statistic is the
+// largest distance between the empirical distribution function of the first set and the empirical distribution
+// function of the second set (or the supplied cumulative distribution function), in the direction given by
+// Alternative: for AlternativeGreater it is the largest amount by which the first exceeds the second, for
+// AlternativeLess the largest amount by which the second exceeds the first, and for AlternativeTwoSided the largest
+// absolute difference.  LocationOfMaximumGap is the value at which that distance occurs.  Exact is true when the
+// p-value was computed from the exact null distribution rather than the asymptotic one.
+type KolmogorovSmirnovTestResult struct {
+	Statistic            float64
+	PValue               float64
+	Alternative          HypothesisAlternative
+	Exact                bool
+	LocationOfMaximumGap float64
+}
+
+// An AndersonDarlingTestResult is the result of a one sample Anderson–Darling test.  Statistic is A², and
+// LocationOfMaximumGap is the value at which the empirical distribution function of the set is furthest from the
+// supplied cumulative distribution function.
+type AndersonDarlingTestResult struct {
+	Statistic            float64
+	PValue               float64
+	LocationOfMaximumGap float64
+}
+
+// A KSampleAndersonDarlingTestResult is the result of a k-sample Anderson–Darling test.  Statistic is the midrank
+// statistic A²akN of Scholz and Stephens (1987), and StandardizedStatistic is (A²akN - (k - 1)) / σN, from which the
+// p-value is interpolated.  LocationOfMaximumGap is the value at which the empirical distribution function of one of
+// the sets is furthest from the empirical distribution function of all of the sets combined.
+type KSampleAndersonDarlingTestResult struct {
+	Statistic             float64
+	StandardizedStatistic float64
+	PValue                float64
+	LocationOfMaximumGap  float64
+}
+
+// TwoSampleKolmogorovSmirnovTest tests whether a and b are drawn from the same continuous distribution.  The exact
+// p-value is computed for small sets without ties; otherwise the asymptotic distribution is used.
+func TwoSampleKolmogorovSmirnovTest(a *StatisticalSampleSet, b *StatisticalSampleSet, alternative HypothesisAlternative) (*KolmogorovSmirnovTestResult, error) {
+	if err := errorIfAlternativeIsNotValid(alternative); err != nil {
+		return nil, err
+	}
+
+	sortedA, sortedB := a.snapshotOfSortedValues(), b.snapshotOfSortedValues()
+	m, n := float64(len(sortedA)), float64(len(sortedB))
+
+	var largestPositiveGap, largestNegativeGap float64
+	var locationOfLargestPositiveGap, locationOfLargestNegativeGap float64
+	thereAreTies := false
+
+	for i, j := 0, 0; i < len(sortedA) || j < len(sortedB); {
+		var value float64
+		if j >= len(sortedB) || (i < len(sortedA) && sortedA[i] <= sortedB[j]) {
+			value = sortedA[i]
+		} else {
+			value = sortedB[j]
+		}
+
+		valuesAtThisPoint := 0
+		for i < len(sortedA) && sortedA[i] == value {
+			i++
+			valuesAtThisPoint++
+		}
+		for j < len(sortedB) && sortedB[j] == value {
+			j++
+			valuesAtThisPoint++
+		}
+
+		if valuesAtThisPoint > 1 {
+			thereAreTies = true
+		}
+
+		gap := float64(i)/m - float64(j)/n
+		if gap > largestPositiveGap {
+			largestPositiveGap, locationOfLargestPositiveGap = gap, value
+		}
+		if -gap > largestNegativeGap {
+			largestNegativeGap, locationOfLargestNegativeGap = -gap, value
+		}
+	}
+
+	result := &KolmogorovSmirnovTestResult{
+		Alternative: alternative,
+		Exact:       m*n < maximumSizeProductForExactTwoSampleKolmogorovSmirnovTest && !thereAreTies,
+	}
+
+	switch {
+	case alternative == AlternativeGreater,
+		alternative == AlternativeTwoSided && largestPositiveGap >= largestNegativeGap:
+		result.Statistic, result.LocationOfMaximumGap = largestPositiveGap, locationOfLargestPositiveGap
+	default:
+		result.Statistic, result.LocationOfMaximumGap = largestNegativeGap, locationOfLargestNegativeGap
+	}
+
+	switch {
+	case result.Exact:
+		result.PValue = exactTwoSampleKolmogorovSmirnovPValue(result.Statistic, len(sortedA), len(sortedB), alternative)
+	case alternative == AlternativeTwoSided:
+		result.PValue = 1 - kolmogorovDistributionCDF(math.Sqrt(m*n/(m+n))*result.Statistic)
+	default:
+		result.PValue = math.Exp(-2 * m * n / (m + n) * result.Statistic * result.Statistic)
+	}
+
+	return result, nil
+}
+
+// OneSampleKolmogorovSmirnovTest tests whether set is drawn from the continuous distribution with the cumulative
+// distribution function cdf.  The exact p-value is computed for small sets without ties; otherwise the asymptotic
+// distribution is used.
+func OneSampleKolmogorovSmirnovTest(set *StatisticalSampleSet, cdf func(float64) float64, alternative HypothesisAlternative) (*KolmogorovSmirnovTestResult, error) {
+	if err := errorIfAlternativeIsNotValid(alternative); err != nil {
+		return nil, err
+	}
+
+	sortedValues := set.snapshotOfSortedValues()
+	cumulativeProbabilities, err := cumulativeProbabilitiesOfSortedValues(sortedValues, cdf)
+	if err != nil {
+		return nil, err
+	}
+
+	n := float64(len(sortedValues))
+
+	var largestPositiveGap, largestNegativeGap float64
+	var locationOfLargestPositiveGap, locationOfLargestNegativeGap float64
+	thereAreTies := false
+
+	for i, value := range sortedValues {
+		if i > 0 && value == sortedValues[i-1] {
+			thereAreTies = true
+		}
+
+		if gap := float64(i+1)/n - cumulativeProbabilities[i]; gap > largestPositiveGap {
+			largestPositiveGap, locationOfLargestPositiveGap = gap, value
+		}
+		if gap := cumulativeProbabilities[i] - float64(i)/n; gap > largestNegativeGap {
+			largestNegativeGap, locationOfLargestNegativeGap = gap, value
+		}
+	}
+
+	result := &KolmogorovSmirnovTestResult{
+		Alternative: alternative,
+		Exact:       len(sortedValues) < maximumSamplesForExactOneSampleKolmogorovSmirnovTest && !thereAreTies,
+	}
+
+	switch {
+	case alternative == AlternativeGreater,
+		alternative == AlternativeTwoSided && largestPositiveGap >= largestNegativeGap:
+		result.Statistic, result.LocationOfMaximumGap = largestPositiveGap, locationOfLargestPositiveGap
+	default:
+		result.Statistic, result.LocationOfMaximumGap = largestNegativeGap, locationOfLargestNegativeGap
+	}
+
+	switch {
+	case result.Exact && alternative == AlternativeTwoSided:
+		result.PValue = 1 - exactKolmogorovStatisticCDF(len(sortedValues), result.Statistic)
+	case result.Exact:
+		result.PValue = exactOneSidedKolmogorovSmirnovPValue(len(sortedValues), result.Statistic)
+	case alternative == AlternativeTwoSided:
+		result.PValue = 1 - kolmogorovDistributionCDF(math.Sqrt(n)*result.Statistic)
+	default:
+		result.PValue = math.Exp(-2 * n * result.Statistic * result.Statistic)
+	}
+
+	result.PValue = math.Min(1, math.Max(0, result.PValue))
+
+	return result, nil
+}
+
+// OneSampleAndersonDarlingTest tests whether set is drawn from the continuous distribution with the cumulative
+// distribution function cdf, whose parameters must not have been estimated from set.  The p-value is computed using
+// the approximation of Marsaglia and Marsaglia (2004), which is accurate to about 1e-6.
+func OneSampleAndersonDarlingTest(set *StatisticalSampleSet, cdf func(float64) float64) (*AndersonDarlingTestResult, error) {
+	sortedValues := set.snapshotOfSortedValues()
+	cumulativeProbabilities, err := cumulativeProbabilitiesOfSortedValues(sortedValues, cdf)
+	if err != nil {
+		return nil, err
+	}
+
+	count := len(sortedValues)
+	n := float64(count)
+
+	result := &AndersonDarlingTestResult{}
+
+	sum := float64(0)
+	largestGap := float64(-1)
+	for i, value := range sortedValues {
+		sum += float64(2*i+1) * (math.Log(cumulativeProbabilities[i]) + math.Log1p(-cumulativeProbabilities[count-1-i]))
+
+		gap := math.Max(float64(i+1)/n-cumulativeProbabilities[i], cumulativeProbabilities[i]-float64(i)/n)
+		if gap > largestGap {
+			largestGap, result.LocationOfMaximumGap = gap, value
+		}
+	}
+
+	result.Statistic = -n - sum/n
+
+	if math.IsInf(result.Statistic, 1) {
+		result.PValue = 0
+	} else {
+		result.PValue = math.Min(1, math.Max(0, 1-andersonDarlingStatisticCDF(count, result.Statistic)))
+	}
+
+	return result, nil
+}
+
+// KSampleAndersonDarlingTest tests whether the sets are all drawn from the same continuous distribution, using the
+// midrank form of the statistic, which allows for ties.  At least two sets are required, containing at least four
+// values between them.  The p-value is interpolated from the critical values tabulated by Scholz and Stephens (1987);
+// values beyond the tabulated range are reported as 0.001 or 0.25, so the p-value is only precise between those.
+func KSampleAndersonDarlingTest(sets ...*StatisticalSampleSet) (*KSampleAndersonDarlingTestResult, error) {
+	k := len(sets)
+	if k < 2 {
+		return nil, fmt.Errorf("there must be at least two sets")
+	}
+
+	sortedSets := make([][]float64, k)
+	pooledCount := 0
+	for i, set := range sets {
+		sortedSets[i] = set.snapshotOfSortedValues()
+		pooledCount += len(sortedSets[i])
+	}
+
+	if pooledCount < 4 {
+		return nil, fmt.Errorf("there must be at least four values across all sets")
+	}
+
+	pooled := make([]float64, 0, pooledCount)
+	for _, sortedValues := range sortedSets {
+		pooled = append(pooled, sortedValues...)
+	}
+	sort.Float64s(pooled)
+
+	N := float64(pooledCount)
+
+	result := &KSampleAndersonDarlingTestResult{}
+
+	// positions in each set of the first value greater than the current distinct pooled value
+	positionsInSets := make([]int, k)
+
+	statistic := float64(0)
+	largestGap := float64(-1)
+	for start := 0; start < pooledCount; {
+		value := pooled[start]
+
+		end := start
+		for end < pooledCount && pooled[end] == value {
+			end++
+		}
+
+		l := float64(end - start)
+		b := float64(start) + l/2
+
+		denominator := b*(N-b) - N*l/4
+		if denominator <= 0 {
+			// only possible when every value is tied
+			return nil, fmt.Errorf("all values are tied")
+		}
+
+		for i, sortedValues := range sortedSets {
+			firstPosition := positionsInSets[i]
+			for positionsInSets[i] < len(sortedValues) && sortedValues[positionsInSets[i]] == value {
+				positionsInSets[i]++
+			}
+
+			ni := float64(len(sortedValues))
+			f := float64(positionsInSets[i] - firstPosition)
+			midrankCount := float64(positionsInSets[i]) - f/2
+
+			statistic += l / N * (N*midrankCount - b*ni) * (N*midrankCount - b*ni) / denominator / ni
+
+			if gap := math.Abs(float64(positionsInSets[i])/ni - float64(end)/N); gap > largestGap {
+				largestGap, result.LocationOfMaximumGap = gap, value
+			}
+		}
+
+		start = end
+	}
+
+	statistic *= (N - 1) / N
+	result.Statistic = statistic
+
+	H := float64(0)
+	for _, sortedValues := range sortedSets {
+		H += 1 / float64(len(sortedValues))
+	}
+
+	// h is the sum of 1 / i for i in [1, N - 1], and g the sum of 1 / ((N - i) j) for 1 <= i < j <= N - 1
+	h, g := float64(0), float64(0)
+	for i := 1; i <= pooledCount-1; i++ {
+		h += 1 / float64(i)
+	}
+	partialHarmonicSum := float64(0)
+	for j := 2; j <= pooledCount-1; j++ {
+		partialHarmonicSum += 1 / float64(pooledCount+1-j)
+		g += partialHarmonicSum / float64(j)
+	}
+
+	kf := float64(k)
+	coefficientA := (4*g-6)*(kf-1) + (10-6*g)*H
+	coefficientB := (2*g-4)*kf*kf + 8*h*kf + (2*g-14*h-4)*H - 8*h + 4*g - 6
+	coefficientC := (6*h+2*g-2)*kf*kf + (4*h-4*g+6)*kf + (2*h-6)*H + 4*h
+	coefficientD := (2*h+6)*kf*kf - 4*h*kf
+	variance := (coefficientA*N*N*N + coefficientB*N*N + coefficientC*N + coefficientD) / ((N - 1) * (N - 2) * (N - 3))
+
+	result.StandardizedStatistic = (statistic - (kf - 1)) / math.Sqrt(variance)
+	result.PValue = kSampleAndersonDarlingPValue(result.StandardizedStatistic, k-1)
+
+	return result, nil
+}
+
+func cumulativeProbabilitiesOfSortedValues(sortedValues []float64, cdf func(float64) float64) ([]float64, error) {
+	if cdf == nil {
+		return nil, fmt.Errorf("cumulative distribution function must not be nil")
+	}
+
+	cumulativeProbabilities := make([]float64, len(sortedValues))
+	for i, value := range sortedValues {
+		p := cdf(value)
+		if !(p >= 0 && p <= 1) {
+			return nil, fmt.Errorf("cumulative distribution function returned (%f) for (%f), which is not in [0, 1]", p, value)
+		}
+		cumulativeProbabilities[i] = p
+	}
+
+	return cumulativeProbabilities, nil
+}
+
+// exactTwoSampleKolmogorovSmirnovPValue computes the probability that the statistic is at least as large as the one
+// observed by counting the lattice paths from (0, 0) to (m, n) which stay within it, as in R's psmirnov2x.  Paths are
+// weighted so that the result is a probability rather than a count.
+func exactTwoSampleKolmogorovSmirnovPValue(statistic float64, m int, n int, alternative HypothesisAlternative) float64 {
+	md, nd := float64(m), float64(n)
+	q := (0.5 + math.Floor(statistic*md*nd-1e-7)) / (md * nd)
+
+	exceeds := func(i int, j int) bool {
+		gap := float64(i)/md - float64(j)/nd
+		switch alternative {
+		case AlternativeGreater:
+			return gap > q
+		case AlternativeLess:
+			return -gap > q
+		}
+		return math.Abs(gap) > q
+	}
+
+	u := make([]float64, n+1)
+	for j := range u {
+		if !exceeds(0, j) {
+			u[j] = 1
+		}
+	}
+
+	for i := 1; i <= m; i++ {
+		w := float64(i) / float64(i+n)
+
+		if exceeds(i, 0) {
+			u[0] = 0
+		} else {
+			u[0] = w * u[0]
+		}
+
+		for j := 1; j <= n; j++ {
+			if exceeds(i, j) {
+				u[j] = 0
+			} else {
+				u[j] = w*u[j] + u[j-1]
+			}
+		}
+	}
+
+	return math.Min(1, math.Max(0, 1-u[n]))
+}
+
+// kolmogorovDistributionCDF computes the limiting distribution of sqrt(n) times the two-sided Kolmogorov–Smirnov
+// statistic, using whichever of its two series converges more quickly.
+func kolmogorovDistributionCDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+
+	if x < 1 {
+		z := -(math.Pi * math.Pi / 8) / (x * x)
+		sum := float64(0)
+		for k := 1; k < 20; k += 2 {
+			sum += math.Exp(float64(k*k) * z)
+		}
+		return math.Sqrt(2*math.Pi) / x * sum
+	}
+
+	z := -2 * x * x
+	sign := float64(-1)
+	previous, current := float64(0), float64(1)
+	for k := 1; k < 100 && math.Abs(previous-current) > 1e-16; k++ {
+		previous = current
+		current += 2 * sign * math.Exp(z*float64(k*k))
+		sign = -sign
+	}
+
+	return current
+}
+
+// exactKolmogorovStatisticCDF computes the probability that the two-sided one sample Kolmogorov–Smirnov statistic
+// for n values is less than d, using the matrix method of Marsaglia, Tsang and Wang (2003).
+func exactKolmogorovStatisticCDF(n int, d float64) float64 {
+	k := int(float64(n)*d) + 1
+	m := 2*k - 1
+	h := float64(k) - float64(n)*d
+
+	H := make([]float64, m*m)
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			if i-j+1 >= 0 {
+				H[i*m+j] = 1
+			}
+		}
+	}
+
+	for i := 0; i < m; i++ {
+		H[i*m] -= math.Pow(h, float64(i+1))
+		H[(m-1)*m+i] -= math.Pow(h, float64(m-i))
+	}
+
+	if 2*h-1 > 0 {
+		H[(m-1)*m] += math.Pow(2*h-1, float64(m))
+	}
+
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			for g := 1; g <= i-j+1; g++ {
+				H[i*m+j] /= float64(g)
+			}
+		}
+	}
+
+	Q, exponentOfQ := kolmogorovMatrixPower(H, 0, m, n)
+
+	s := Q[(k-1)*m+k-1]
+	for i := 1; i <= n; i++ {
+		s = s * float64(i) / float64(n)
+		if s < 1e-140 {
+			s *= 1e140
+			exponentOfQ -= 140
+		}
+	}
+
+	return s * math.Pow(10, float64(exponentOfQ))
+}
+
+// kolmogorovMatrixPower raises the m by m matrix A, scaled by 10^exponentOfA, to the power n, rescaling as it goes
+// to avoid overflow.  It returns the result along with its decimal exponent.
+func kolmogorovMatrixPower(A []float64, exponentOfA int, m int, n int) ([]float64, int) {
+	if n == 1 {
+		V := make([]float64, len(A))
+		copy(V, A)
+		return V, exponentOfA
+	}
+
+	V, exponentOfV := kolmogorovMatrixPower(A, exponentOfA, m, n/2)
+	B := multiplySquareMatrices(V, V, m)
+	exponentOfB := 2 * exponentOfV
+
+	if n%2 == 0 {
+		V, exponentOfV = B, exponentOfB
+	} else {
+		V, exponentOfV = multiplySquareMatrices(A, B, m), exponentOfA+exponentOfB
+	}
+
+	if V[(m/2)*m+(m/2)] > 1e140 {
+		for i := range V {
+			V[i] *= 1e-140
+		}
+		exponentOfV += 140
+	}
+
+	return V, exponentOfV
+}
+
+func multiplySquareMatrices(A []float64, B []float64, m int) []float64 {
+	C := make([]float64, m*m)
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			sum := float64(0)
+			for k := 0; k < m; k++ {
+				sum += A[i*m+k] * B[k*m+j]
+			}
+			C[i*m+j] = sum
+		}
+	}
+
+	return C
+}
+
+// exactOneSidedKolmogorovSmirnovPValue computes the probability that a one-sided one sample Kolmogorov–Smirnov
+// statistic for n values is at least d, using the formula of Birnbaum and Tingey (1951).
+func exactOneSidedKolmogorovSmirnovPValue(n int, d float64) float64 {
+	if d <= 0 {
+		return 1
+	}
+	if d >= 1 {
+		return 0
+	}
+
+	nf := float64(n)
+	lgammaOfNPlusOne, _ := math.Lgamma(nf + 1)
+
+	sum := float64(0)
+	for j := 0; j <= int(math.Floor(nf*(1-d))); j++ {
+		jf := float64(j)
+		lgammaOfJPlusOne, _ := math.Lgamma(jf + 1)
+		lgammaOfNMinusJPlusOne, _ := math.Lgamma(nf - jf + 1)
+		logOfBinomialCoefficient := lgammaOfNPlusOne - lgammaOfJPlusOne - lgammaOfNMinusJPlusOne
+
+		sum += math.Exp(logOfBinomialCoefficient + (nf-jf)*math.Log(1-d-jf/nf) + (jf-1)*math.Log(d+jf/nf))
+	}
+
+	return d * sum
+}
+
+// andersonDarlingStatisticCDF computes the probability that A² for n values is less than z, using the limiting
+// distribution and the finite sample correction of Marsaglia and Marsaglia (2004).
+func andersonDarlingStatisticCDF(n int, z float64) float64 {
+	if z <= 0 {
+		return 0
+	}
+
+	var x float64
+	if z < 2 {
+		x = math.Exp(-1.2337141/z) / math.Sqrt(z) * (2.00012 + (0.247105-(0.0649821-(0.0347962-(0.011672-0.00168691*z)*z)*z)*z)*z)
+	} else {
+		x = math.Exp(-math.Exp(1.0776 - (2.30695-(0.43424-(0.082433-(0.008056-0.0003146*z)*z)*z)*z)*z))
+	}
+
+	return x + andersonDarlingFiniteSampleCorrection(n, x)
+}
+
+func andersonDarlingFiniteSampleCorrection(n int, x float64) float64 {
+	nf := float64(n)
+
+	if x > 0.8 {
+		return (-130.2137 + (745.2337-(1705.091-(1950.646-(1116.360-255.7844*x)*x)*x)*x)*x) / nf
+	}
+
+	c := 0.01265 + 0.1757/nf
+	if x < c {
+		t := x / c
+		t = math.Sqrt(t) * (1 - t) * (49*t - 102)
+		return t * (0.0037/(nf*nf) + 0.00078/nf + 0.00006) / nf
+	}
+
+	t := (x - c) / (0.8 - c)
+	t = -0.00022633 + (6.54034-(14.6538-(14.458-(8.259-1.91864*t)*t)*t)*t)*t
+
+	return t * (0.04213 + 0.01365/nf) / nf
+}
+
+// Critical values of the standardized k-sample Anderson–Darling statistic are b0 + b1 / sqrt(m) + b2 / m, where m is
+// one fewer than the number of sets, at the corresponding significance levels (Scholz and Stephens, 1987, Table 1).
+var kSampleAndersonDarlingSignificanceLevels = []float64{0.25, 0.1, 0.05, 0.025, 0.01, 0.005, 0.001}
+var kSampleAndersonDarlingB0 = []float64{0.675, 1.281, 1.645, 1.96, 2.326, 2.573, 3.085}
+var kSampleAndersonDarlingB1 = []float64{-0.245, 0.25, 0.678, 1.149, 1.822, 2.364, 3.615}
+var kSampleAndersonDarlingB2 = []float64{-0.105, -0.305, -0.362, -0.391, -0.396, -0.345, -0.154}
+
+// kSampleAndersonDarlingPValue fits a quadratic to the logarithm of the significance levels as a function of the
+// critical values by least squares, and evaluates it at the standardized statistic.
+func kSampleAndersonDarlingPValue(standardizedStatistic float64, m int) float64 {
+	mf := float64(m)
+	numberOfLevels := len(kSampleAndersonDarlingSignificanceLevels)
+
+	criticalValues := make([]float64, numberOfLevels)
+	for i := range criticalValues {
+		criticalValues[i] = kSampleAndersonDarlingB0[i] + kSampleAndersonDarlingB1[i]/math.Sqrt(mf) + kSampleAndersonDarlingB2[i]/mf
+	}
+
+	if standardizedStatistic <= criticalValues[0] {
+		return kSampleAndersonDarlingSignificanceLevels[0]
+	}
+	if standardizedStatistic >= criticalValues[numberOfLevels-1] {
+		return kSampleAndersonDarlingSignificanceLevels[numberOfLevels-1]
+	}
+
+	// normal equations for the least squares fit of y = c0 + c1 x + c2 x^2
+	var powerSums [5]float64
+	var momentSums [3]float64
+	for i, x := range criticalValues {
+		y := math.Log(kSampleAndersonDarlingSignificanceLevels[i])
+		xPower := float64(1)
+		for p := 0; p < 5; p++ {
+			powerSums[p] += xPower
+			if p < 3 {
+				momentSums[p] += xPower * y
+			}
+			xPower *= x
+		}
+	}
+
+	system := [3][4]float64{
+		{powerSums[0], powerSums[1], powerSums[2], momentSums[0]},
+		{powerSums[1], powerSums[2], powerSums[3], momentSums[1]},
+		{powerSums[2], powerSums[3], powerSums[4], momentSums[2]},
+	}
+
+	for column := 0; column < 3; column++ {
+		pivot := column
+		for row := column + 1; row < 3; row++ {
+			if math.Abs(system[row][column]) > math.Abs(system[pivot][column]) {
+				pivot = row
+			}
+		}
+		system[column], system[pivot] = system[pivot], system[column]
+
+		for row := column + 1; row < 3; row++ {
+			factor := system[row][column] / system[column][column]
+			for c := column; c < 4; c++ {
+				system[row][c] -= factor * system[column][c]
+			}
+		}
+	}
+
+	var coefficients [3]float64
+	for row := 2; row >= 0; row-- {
+		sum := system[row][3]
+		for c := row + 1; c < 3; c++ {
+			sum -= system[row][c] * coefficients[c]
+		}
+		coefficients[row] = sum / system[row][row]
+	}
+
+	return math.Exp(coefficients[0] + coefficients[1]*standardizedStatistic + coefficients[2]*standardizedStatistic*standardizedStatistic)
+}
@@ -0,0 +1,215 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+func uniformCDF(x float64) float64 {
+	return math.Min(1, math.Max(0, x))
+}
+
+// bruteForceTwoSampleKolmogorovSmirnovPValue computes the exact p-value by evaluating the statistic for every way
+// of splitting the pooled values into sets of the original sizes.
+func bruteForceTwoSampleKolmogorovSmirnovPValue(t *testing.T, a []float64, b []float64, alternative stats.HypothesisAlternative, observed float64) float64 {
+	pooled := append(append([]float64{}, a...), b...)
+
+	atLeastAsExtreme, total := 0, 0
+	for mask := 0; mask < 1<<len(pooled); mask++ {
+		var first, second []float64
+		for i, value := range pooled {
+			if mask&(1<<i) != 0 {
+				first = append(first, value)
+			} else {
+				second = append(second, value)
+			}
+		}
+
+		if len(first) != len(a) {
+			continue
+		}
+
+		firstSet, _ := stats.MakeStatisticalSampleSetFrom(first)
+		secondSet, _ := stats.MakeStatisticalSampleSetFrom(second)
+		result, err := stats.TwoSampleKolmogorovSmirnovTest(firstSet, secondSet, alternative)
+		if err != nil {
+			t.Fatalf("on TwoSampleKolmogorovSmirnovTest() got error: %s", err.Error())
+		}
+
+		total++
+		if result.Statistic >= observed-1e-9 {
+			atLeastAsExtreme++
+		}
+	}
+
+	return float64(atLeastAsExtreme) / float64(total)
+}
+
+func TestTwoSampleKolmogorovSmirnovTestExact(t *testing.T) {
+	a := []float64{0.61, 2.29, 0.06, 0.59, 1.73, 0.74}
+	b := []float64{1.51, 1.56, 2.39, 1.64, 2.05, 3.06, 1.77}
+	setA, _ := stats.MakeStatisticalSampleSetFrom(a)
+	setB, _ := stats.MakeStatisticalSampleSetFrom(b)
+
+	for _, testCase := range []struct {
+		alternative          stats.HypothesisAlternative
+		statistic            float64
+		locationOfMaximumGap float64
+	}{
+		{stats.AlternativeTwoSided, 4.0 / 6, 0.74},
+		{stats.AlternativeLess, 0, 0},
+		{stats.AlternativeGreater, 4.0 / 6, 0.74},
+	} {
+		result, err := stats.TwoSampleKolmogorovSmirnovTest(setA, setB, testCase.alternative)
+		if err != nil {
+			t.Errorf("on alternative (%s) got error: %s", testCase.alternative, err.Error())
+			continue
+		}
+
+		if !result.Exact {
+			t.Errorf("on alternative (%s) expected exact p-value, got asymptotic", testCase.alternative)
+		}
+
+		if math.Abs(result.Statistic-testCase.statistic) > 1e-12 {
+			t.Errorf("on alternative (%s) expected Statistic (%f), got (%f)", testCase.alternative, testCase.statistic, result.Statistic)
+		}
+
+		if result.LocationOfMaximumGap != testCase.locationOfMaximumGap {
+			t.Errorf("on alternative (%s) expected LocationOfMaximumGap (%f), got (%f)", testCase.alternative, testCase.locationOfMaximumGap, result.LocationOfMaximumGap)
+		}
+
+		expectedPValue := bruteForceTwoSampleKolmogorovSmirnovPValue(t, a, b, testCase.alternative, result.Statistic)
+		if math.Abs(result.PValue-expectedPValue) > 1e-12 {
+			t.Errorf("on alternative (%s) expected PValue (%f), got (%f)", testCase.alternative, expectedPValue, result.PValue)
+		}
+	}
+}
+
+func TestTwoSampleKolmogorovSmirnovTestAsymptotic(t *testing.T) {
+	a, b := make([]float64, 100), make([]float64, 100)
+	for i := range a {
+		a[i] = float64(i)
+		b[i] = float64(i) + 20.5
+	}
+	setA, _ := stats.MakeStatisticalSampleSetFrom(a)
+	setB, _ := stats.MakeStatisticalSampleSetFrom(b)
+
+	result, err := stats.TwoSampleKolmogorovSmirnovTest(setA, setB, stats.AlternativeTwoSided)
+	if err != nil {
+		t.Fatalf("on TwoSampleKolmogorovSmirnovTest() got error: %s", err.Error())
+	}
+
+	if result.Exact {
+		t.Errorf("expected asymptotic p-value, got exact")
+	}
+
+	// the gap is 0.21 at every value of a from 20 onwards
+	if math.Abs(result.Statistic-0.21) > 1e-12 || result.LocationOfMaximumGap < 20 || result.LocationOfMaximumGap > 99 {
+		t.Errorf("expected Statistic (0.21) in [20, 99], got (%f) at (%f)", result.Statistic, result.LocationOfMaximumGap)
+	}
+
+	// lambda = sqrt(100 * 100 / 200) * 0.21, and the p-value is 2 * sum((-1)^(k-1) exp(-2 k^2 lambda^2))
+	expectedPValue := 2 * (math.Exp(-4.41) - math.Exp(-17.64) + math.Exp(-39.69))
+	if math.Abs(result.PValue-expectedPValue) > 1e-9 {
+		t.Errorf("expected PValue (%f), got (%f)", expectedPValue, result.PValue)
+	}
+
+	result, _ = stats.TwoSampleKolmogorovSmirnovTest(setA, setB, stats.AlternativeGreater)
+	if expectedPValue := math.Exp(-2 * 50 * 0.21 * 0.21); math.Abs(result.PValue-expectedPValue) > 1e-9 {
+		t.Errorf("on AlternativeGreater expected PValue (%f), got (%f)", expectedPValue, result.PValue)
+	}
+}
+
+func TestOneSampleKolmogorovSmirnovTest(t *testing.T) {
+	// the gap at the first value is 0.274, and no other gap is as large
+	values := make([]float64, 10)
+	for i := range values {
+		values[i] = 0.274 + float64(i)*0.0726
+	}
+	set, _ := stats.MakeStatisticalSampleSetFrom(values)
+
+	result, err := stats.OneSampleKolmogorovSmirnovTest(set, uniformCDF, stats.AlternativeTwoSided)
+	if err != nil {
+		t.Fatalf("on OneSampleKolmogorovSmirnovTest() got error: %s", err.Error())
+	}
+
+	if result.Statistic != 0.274 || result.LocationOfMaximumGap != 0.274 || !result.Exact {
+		t.Errorf("expected exact Statistic (0.274) at (0.274), got (%f) at (%f) with Exact (%t)", result.Statistic, result.LocationOfMaximumGap, result.Exact)
+	}
+
+	// Marsaglia, Tsang and Wang (2003) give K(10, 0.274) = 0.6284796154565043
+	if expectedPValue := 1 - 0.6284796154565043; math.Abs(result.PValue-expectedPValue) > 1e-12 {
+		t.Errorf("expected PValue (%.16f), got (%.16f)", expectedPValue, result.PValue)
+	}
+
+	// for one value, P(D- >= d) = 1 - d
+	single, _ := stats.MakeStatisticalSampleSetFrom([]float64{0.3})
+	result, _ = stats.OneSampleKolmogorovSmirnovTest(single, uniformCDF, stats.AlternativeLess)
+	if math.Abs(result.Statistic-0.3) > 1e-12 || math.Abs(result.PValue-0.7) > 1e-12 {
+		t.Errorf("on a single value expected Statistic (0.3) and PValue (0.7), got (%f) and (%f)", result.Statistic, result.PValue)
+	}
+
+	if _, err := stats.OneSampleKolmogorovSmirnovTest(set, func(float64) float64 { return 2 }, stats.AlternativeTwoSided); err == nil {
+		t.Errorf("on cumulative distribution function returning (2), expected error, got none")
+	}
+}
+
+func TestOneSampleAndersonDarlingTest(t *testing.T) {
+	values := make([]float64, 10)
+	for i := range values {
+		values[i] = 0.274 + float64(i)*0.0726
+	}
+	set, _ := stats.MakeStatisticalSampleSetFrom(values)
+
+	result, err := stats.OneSampleAndersonDarlingTest(set, uniformCDF)
+	if err != nil {
+		t.Fatalf("on OneSampleAndersonDarlingTest() got error: %s", err.Error())
+	}
+
+	expectedStatistic := float64(-10)
+	for i := 0; i < 10; i++ {
+		expectedStatistic -= float64(2*i+1) * (math.Log(values[i]) + math.Log(1-values[9-i])) / 10
+	}
+
+	if math.Abs(result.Statistic-expectedStatistic) > 1e-12 {
+		t.Errorf("expected Statistic (%f), got (%f)", expectedStatistic, result.Statistic)
+	}
+
+	// 1 - AD(10, A²) from the ADinf and errfix code published by Marsaglia and Marsaglia (2004)
+	if math.Abs(result.PValue-0.39015018713237337) > 1e-12 || result.LocationOfMaximumGap != 0.274 {
+		t.Errorf("expected PValue (0.390150) and LocationOfMaximumGap (0.274), got (%f) and (%f)", result.PValue, result.LocationOfMaximumGap)
+	}
+}
+
+func TestKSampleAndersonDarlingTest(t *testing.T) {
+	// the paraffin data of Scholz and Stephens (1987), which has ties
+	var sets []*stats.StatisticalSampleSet
+	for _, values := range [][]float64{
+		{38.7, 41.5, 43.8, 44.5, 45.5, 46.0, 47.7, 58.0},
+		{39.2, 39.3, 39.7, 41.4, 41.8, 42.9, 43.3, 45.8},
+		{34.0, 35.0, 39.0, 40.0, 43.0, 43.0, 44.0, 45.0},
+		{34.0, 34.8, 34.8, 35.4, 37.2, 37.8, 41.2, 42.8},
+	} {
+		set, _ := stats.MakeStatisticalSampleSetFrom(values)
+		sets = append(sets, set)
+	}
+
+	result, err := stats.KSampleAndersonDarlingTest(sets...)
+	if err != nil {
+		t.Fatalf("on KSampleAndersonDarlingTest() got error: %s", err.Error())
+	}
+
+	if math.Abs(result.StandardizedStatistic-4.480) > 1e-3 {
+		t.Errorf("expected StandardizedStatistic (4.480), got (%f)", result.StandardizedStatistic)
+	}
+
+	if math.Abs(result.PValue-0.0020) > 0.00025 {
+		t.Errorf("expected PValue (0.0020), got (%f)", result.PValue)
+	}
+
+	if _, err := stats.KSampleAndersonDarlingTest(sets[0]); err == nil {
+		t.Errorf("on a single set, expected error, got none")
+	}
+}
@@ -0,0 +1,529 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"sync"
+)
+
+var ErrorValueOutOfRange = errors.New("value is outside of the trackable range")
+
+// An HdrHistogram records non-negative integer values (for example, latencies in microseconds) in buckets whose width
+// preserves a fixed number of significant decimal digits across the trackable range, as described by Gil Tene's
+// HdrHistogram.  Recording a value is a constant time operation and memory use depends only on the range and
+// precision, not on the number of values recorded.
+type HdrHistogram struct {
+	mutex  sync.Mutex
+	counts *hdrHistogramCounts
+}
+
+type hdrHistogramCounts struct {
+	lowestDiscernibleValue      int64
+	highestTrackableValue       int64
+	significantFigures          int
+	unitMagnitude               int
+	subBucketHalfCountMagnitude int
+	subBucketCount              int
+	subBucketHalfCount          int
+	subBucketMask               int64
+	counts                      []int64
+	totalCount                  int64
+	minimumRecordedValue        int64
+	maximumRecordedValue        int64
+}
+
+// An HdrHistogramPercentile is one step of a percentile distribution.  ValueAtPercentile is the highest value
+// equivalent to the recorded value at the percentile, and CountAtOrBelowValue is the number of recorded values
+// less than or equal to it.
+type HdrHistogramPercentile struct {
+	Percentile          float64
+	ValueAtPercentile   int64
+	CountAtOrBelowValue int64
+}
+
+// NewHdrHistogram creates an empty histogram that tracks values from 0 to highestTrackableValue, distinguishing
+// values that differ by at least lowestDiscernibleValue, with significantFigures (1 through 5) decimal digits
+// of precision.  lowestDiscernibleValue must be at least 1 and highestTrackableValue must be at least twice
+// lowestDiscernibleValue.
+func NewHdrHistogram(lowestDiscernibleValue int64, highestTrackableValue int64, significantFigures int) (*HdrHistogram, error) {
+	counts, err := newHdrHistogramCounts(lowestDiscernibleValue, highestTrackableValue, significantFigures)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HdrHistogram{counts: counts}, nil
+}
+
+func newHdrHistogramCounts(lowestDiscernibleValue int64, highestTrackableValue int64, significantFigures int) (*hdrHistogramCounts, error) {
+	if lowestDiscernibleValue < 1 {
+		return nil, fmt.Errorf("lowest discernible value must be at least 1")
+	}
+
+	if highestTrackableValue < 2*lowestDiscernibleValue {
+		return nil, fmt.Errorf("highest trackable value must be at least twice the lowest discernible value")
+	}
+
+	if significantFigures < 1 || significantFigures > 5 {
+		return nil, fmt.Errorf("significant figures must be in the range 1..5")
+	}
+
+	largestValueWithSingleUnitResolution := 2 * math.Pow10(significantFigures)
+	subBucketCountMagnitude := int(math.Ceil(math.Log2(largestValueWithSingleUnitResolution)))
+
+	subBucketHalfCountMagnitude := subBucketCountMagnitude - 1
+	if subBucketHalfCountMagnitude < 0 {
+		subBucketHalfCountMagnitude = 0
+	}
+
+	unitMagnitude := bits.Len64(uint64(lowestDiscernibleValue)) - 1
+	if unitMagnitude+subBucketHalfCountMagnitude > 61 {
+		return nil, fmt.Errorf("lowest discernible value is too large for the number of significant figures")
+	}
+
+	subBucketCount := 1 << (subBucketHalfCountMagnitude + 1)
+
+	smallestUntrackableValue := int64(subBucketCount) << unitMagnitude
+	bucketsNeeded := 1
+	for smallestUntrackableValue <= highestTrackableValue {
+		if smallestUntrackableValue > math.MaxInt64/2 {
+			bucketsNeeded++
+			break
+		}
+		smallestUntrackableValue <<= 1
+		bucketsNeeded++
+	}
+
+	return &hdrHistogramCounts{
+		lowestDiscernibleValue:      lowestDiscernibleValue,
+		highestTrackableValue:       highestTrackableValue,
+		significantFigures:          significantFigures,
+		unitMagnitude:               unitMagnitude,
+		subBucketHalfCountMagnitude: subBucketHalfCountMagnitude,
+		subBucketCount:              subBucketCount,
+		subBucketHalfCount:          subBucketCount / 2,
+		subBucketMask:               int64(subBucketCount-1) << unitMagnitude,
+		counts:                      make([]int64, (bucketsNeeded+1)*(subBucketCount/2)),
+		minimumRecordedValue:        math.MaxInt64,
+		maximumRecordedValue:        0,
+	}, nil
+}
+
+// RecordValue records a single occurrence of value.  ErrorValueOutOfRange is returned if the value is negative or
+// greater than the highest trackable value.
+func (histogram *HdrHistogram) RecordValue(value int64) error {
+	return histogram.RecordValues(value, 1)
+}
+
+// RecordValues records numberOfOccurrences occurrences of value.  An error is returned if numberOfOccurrences is
+// negative, and nothing is recorded if it is zero.
+func (histogram *HdrHistogram) RecordValues(value int64, numberOfOccurrences int64) error {
+	if numberOfOccurrences < 0 {
+		return fmt.Errorf("number of occurrences (%d) must not be negative", numberOfOccurrences)
+	}
+
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	return histogram.counts.recordValues(value, numberOfOccurrences)
+}
+
+// RecordCorrectedValue records value and corrects for coordinated omission.  If value is larger than
+// expectedIntervalBetweenValues, then the values that would have been recorded while the recording thread was
+// stalled are also recorded: value - expectedIntervalBetweenValues, value - 2 * expectedIntervalBetweenValues,
+// and so forth, down to expectedIntervalBetweenValues.
+func (histogram *HdrHistogram) RecordCorrectedValue(value int64, expectedIntervalBetweenValues int64) error {
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	if err := histogram.counts.recordValues(value, 1); err != nil {
+		return err
+	}
+
+	if expectedIntervalBetweenValues <= 0 {
+		return nil
+	}
+
+	for missingValue := value - expectedIntervalBetweenValues; missingValue >= expectedIntervalBetweenValues; missingValue -= expectedIntervalBetweenValues {
+		if err := histogram.counts.recordValues(missingValue, 1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Merge records all of the values recorded by another histogram into this one.  The histograms need not have the
+// same range or precision, but ErrorValueOutOfRange is returned, and nothing is merged, if the other histogram has
+// recorded a value larger than this histogram can track.
+func (histogram *HdrHistogram) Merge(other *HdrHistogram) error {
+	other.mutex.Lock()
+	otherCounts := other.counts.copyOfCounts()
+	other.mutex.Unlock()
+
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	if otherCounts.totalCount == 0 {
+		return nil
+	}
+
+	if otherCounts.maximumRecordedValue > histogram.counts.highestTrackableValue {
+		return ErrorValueOutOfRange
+	}
+
+	for index, count := range otherCounts.counts {
+		if count != 0 {
+			histogram.counts.recordValuesWithoutChangingExtremes(otherCounts.valueFromIndex(index), count)
+		}
+	}
+
+	histogram.counts.updateExtremes(otherCounts.minimumRecordedValue)
+	histogram.counts.updateExtremes(otherCounts.maximumRecordedValue)
+
+	return nil
+}
+
+// TotalCount returns the number of values recorded, including those recorded to correct for coordinated omission.
+func (histogram *HdrHistogram) TotalCount() int64 {
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	return histogram.counts.totalCount
+}
+
+// Minimum returns the smallest value recorded, or 0 if no values have been recorded.
+func (histogram *HdrHistogram) Minimum() int64 {
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	return histogram.counts.minimum()
+}
+
+// Maximum returns the largest value recorded, or 0 if no values have been recorded.
+func (histogram *HdrHistogram) Maximum() int64 {
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	return histogram.counts.maximumRecordedValue
+}
+
+// Mean returns the mean of the recorded values, each approximated by the midpoint of its bucket.  It returns NaN
+// if no values have been recorded.
+func (histogram *HdrHistogram) Mean() float64 {
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	return histogram.counts.mean()
+}
+
+// Stdev returns the population standard deviation of the recorded values, each approximated by the midpoint of
+// its bucket.  It returns NaN if no values have been recorded.
+func (histogram *HdrHistogram) Stdev() float64 {
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	return histogram.counts.stdev()
+}
+
+// ValueAtPercentile returns the value at or below which the percentile (in the range 0..100) of recorded values
+// fall, at the precision of the histogram.  It returns 0 if no values have been recorded.
+func (histogram *HdrHistogram) ValueAtPercentile(percentile float64) int64 {
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	return histogram.counts.valueAtPercentile(percentile)
+}
+
+// Percentiles returns the percentile distribution of the recorded values.  The distance between reported
+// percentiles halves each time the remaining distance to 100 halves, with ticksPerHalfDistance steps reported
+// in each half, until the remaining distance is less than one recorded value's share of the total.  The final step
+// is always the 100th percentile.
+func (histogram *HdrHistogram) Percentiles(ticksPerHalfDistance int) []HdrHistogramPercentile {
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	return histogram.counts.percentiles(ticksPerHalfDistance)
+}
+
+// Summary returns a read-only snapshot of the histogram, which provides the same summary statistics as a
+// StatisticalSampleSet.  An error is returned if no values have been recorded.
+func (histogram *HdrHistogram) Summary() (*HdrHistogramSummary, error) {
+	histogram.mutex.Lock()
+	defer histogram.mutex.Unlock()
+
+	if histogram.counts.totalCount == 0 {
+		return nil, fmt.Errorf("there must be at least one value recorded in the histogram")
+	}
+
+	return &HdrHistogramSummary{counts: histogram.counts.copyOfCounts()}, nil
+}
+
+// An HdrHistogramSummary is a read-only snapshot of an HdrHistogram.
+type HdrHistogramSummary struct {
+	counts *hdrHistogramCounts
+}
+
+func (summary *HdrHistogramSummary) Count() int {
+	return int(summary.counts.totalCount)
+}
+
+func (summary *HdrHistogramSummary) Minimum() float64 {
+	return float64(summary.counts.minimum())
+}
+
+func (summary *HdrHistogramSummary) Maximum() float64 {
+	return float64(summary.counts.maximumRecordedValue)
+}
+
+func (summary *HdrHistogramSummary) Mean() float64 {
+	return summary.counts.mean()
+}
+
+func (summary *HdrHistogramSummary) Median() float64 {
+	return float64(summary.counts.valueAtPercentile(50))
+}
+
+func (summary *HdrHistogramSummary) PopulationStdev() float64 {
+	return summary.counts.stdev()
+}
+
+// ValueNearestPercentile returns the value at the percentile.  It panics if the percentile is not in the range 0..100.
+func (summary *HdrHistogramSummary) ValueNearestPercentile(percentile int) float64 {
+	if percentile < 0 || percentile > 100 {
+		panic("percentile must be in the range 0..100")
+	}
+
+	return float64(summary.counts.valueAtPercentile(float64(percentile)))
+}
+
+// ValueAtPercentile returns the value at a fractional percentile (in the range 0..100).
+func (summary *HdrHistogramSummary) ValueAtPercentile(percentile float64) int64 {
+	return summary.counts.valueAtPercentile(percentile)
+}
+
+func (histogram *hdrHistogramCounts) recordValues(value int64, numberOfOccurrences int64) error {
+	if value < 0 || value > histogram.highestTrackableValue {
+		return ErrorValueOutOfRange
+	}
+
+	if numberOfOccurrences == 0 {
+		return nil
+	}
+
+	histogram.recordValuesWithoutChangingExtremes(value, numberOfOccurrences)
+	histogram.updateExtremes(value)
+
+	return nil
+}
+
+func (histogram *hdrHistogramCounts) recordValuesWithoutChangingExtremes(value int64, numberOfOccurrences int64) {
+	histogram.counts[histogram.countsIndexFor(value)] += numberOfOccurrences
+	histogram.totalCount += numberOfOccurrences
+}
+
+func (histogram *hdrHistogramCounts) updateExtremes(value int64) {
+	if value < histogram.minimumRecordedValue {
+		histogram.minimumRecordedValue = value
+	}
+	if value > histogram.maximumRecordedValue {
+		histogram.maximumRecordedValue = value
+	}
+}
+
+func (histogram *hdrHistogramCounts) copyOfCounts() *hdrHistogramCounts {
+	copyOfHistogram := *histogram
+	copyOfHistogram.counts = make([]int64, len(histogram.counts))
+	copy(copyOfHistogram.counts, histogram.counts)
+
+	return &copyOfHistogram
+}
+
+func (histogram *hdrHistogramCounts) minimum() int64 {
+	if histogram.totalCount == 0 {
+		return 0
+	}
+
+	return histogram.minimumRecordedValue
+}
+
+func (histogram *hdrHistogramCounts) bucketIndexFor(value int64) int {
+	pow2Ceiling := 64 - bits.LeadingZeros64(uint64(value)|uint64(histogram.subBucketMask))
+	return pow2Ceiling - histogram.unitMagnitude - (histogram.subBucketHalfCountMagnitude + 1)
+}
+
+func (histogram *hdrHistogramCounts) subBucketIndexFor(value int64, bucketIndex int) int {
+	return int(value >> uint(bucketIndex+histogram.unitMagnitude))
+}
+
+func (histogram *hdrHistogramCounts) countsIndexFor(value int64) int {
+	bucketIndex := histogram.bucketIndexFor(value)
+	subBucketIndex := histogram.subBucketIndexFor(value, bucketIndex)
+
+	return ((bucketIndex + 1) << uint(histogram.subBucketHalfCountMagnitude)) + (subBucketIndex - histogram.subBucketHalfCount)
+}
+
+func (histogram *hdrHistogramCounts) valueFromIndex(index int) int64 {
+	bucketIndex := (index >> uint(histogram.subBucketHalfCountMagnitude)) - 1
+	subBucketIndex := (index & (histogram.subBucketHalfCount - 1)) + histogram.subBucketHalfCount
+
+	if bucketIndex < 0 {
+		subBucketIndex -= histogram.subBucketHalfCount
+		bucketIndex = 0
+	}
+
+	return int64(subBucketIndex) << uint(bucketIndex+histogram.unitMagnitude)
+}
+
+func (histogram *hdrHistogramCounts) sizeOfEquivalentValueRange(value int64) int64 {
+	bucketIndex := histogram.bucketIndexFor(value)
+	subBucketIndex := histogram.subBucketIndexFor(value, bucketIndex)
+
+	adjustedBucketIndex := bucketIndex
+	if subBucketIndex >= histogram.subBucketCount {
+		adjustedBucketIndex++
+	}
+
+	return int64(1) << uint(histogram.unitMagnitude+adjustedBucketIndex)
+}
+
+func (histogram *hdrHistogramCounts) lowestEquivalentValue(value int64) int64 {
+	bucketIndex := histogram.bucketIndexFor(value)
+	subBucketIndex := histogram.subBucketIndexFor(value, bucketIndex)
+
+	return int64(subBucketIndex) << uint(bucketIndex+histogram.unitMagnitude)
+}
+
+func (histogram *hdrHistogramCounts) highestEquivalentValue(value int64) int64 {
+	return histogram.lowestEquivalentValue(value) + histogram.sizeOfEquivalentValueRange(value) - 1
+}
+
+func (histogram *hdrHistogramCounts) medianEquivalentValue(value int64) int64 {
+	return histogram.lowestEquivalentValue(value) + histogram.sizeOfEquivalentValueRange(value)>>1
+}
+
+func (histogram *hdrHistogramCounts) mean() float64 {
+	if histogram.totalCount == 0 {
+		return math.NaN()
+	}
+
+	sum := 0.0
+	for index, count := range histogram.counts {
+		if count != 0 {
+			sum += float64(histogram.medianEquivalentValue(histogram.valueFromIndex(index))) * float64(count)
+		}
+	}
+
+	return sum / float64(histogram.totalCount)
+}
+
+func (histogram *hdrHistogramCounts) stdev() float64 {
+	if histogram.totalCount == 0 {
+		return math.NaN()
+	}
+
+	mean := histogram.mean()
+
+	summedVariances := 0.0
+	for index, count := range histogram.counts {
+		if count != 0 {
+			diff := float64(histogram.medianEquivalentValue(histogram.valueFromIndex(index))) - mean
+			summedVariances += diff * diff * float64(count)
+		}
+	}
+
+	return math.Sqrt(summedVariances / float64(histogram.totalCount))
+}
+
+func (histogram *hdrHistogramCounts) valueAtPercentile(percentile float64) int64 {
+	if histogram.totalCount == 0 {
+		return 0
+	}
+
+	percentile = math.Max(0, math.Min(percentile, 100))
+
+	countAtPercentile := int64(percentile/100*float64(histogram.totalCount) + 0.5)
+	if countAtPercentile < 1 {
+		countAtPercentile = 1
+	}
+
+	cumulativeCount := int64(0)
+	for index, count := range histogram.counts {
+		cumulativeCount += count
+		if cumulativeCount >= countAtPercentile {
+			return histogram.clampedToRecordedExtremes(histogram.highestEquivalentValue(histogram.valueFromIndex(index)))
+		}
+	}
+
+	return histogram.maximumRecordedValue
+}
+
+func (histogram *hdrHistogramCounts) clampedToRecordedExtremes(value int64) int64 {
+	if value < histogram.minimumRecordedValue {
+		return histogram.minimumRecordedValue
+	}
+	if value > histogram.maximumRecordedValue {
+		return histogram.maximumRecordedValue
+	}
+
+	return value
+}
+
+func (histogram *hdrHistogramCounts) percentiles(ticksPerHalfDistance int) []HdrHistogramPercentile {
+	if histogram.totalCount == 0 {
+		return nil
+	}
+
+	if ticksPerHalfDistance < 1 {
+		ticksPerHalfDistance = 1
+	}
+
+	steps := make([]HdrHistogramPercentile, 0)
+	cumulativeCount := int64(0)
+
+	// the next percentile to report is the tick'th of the ticksPerHalfDistance evenly spaced between
+	// 100 - 100/2^level and 100 - 100/2^(level+1), above which (2*ticksPerHalfDistance - tick) /
+	// (ticksPerHalfDistance * 2^(level+1)) of the values lie
+	level, tick := 0, 0
+
+	for index, count := range histogram.counts {
+		if count == 0 {
+			continue
+		}
+
+		cumulativeCount += count
+		valueAtThisIndex := histogram.clampedToRecordedExtremes(histogram.highestEquivalentValue(histogram.valueFromIndex(index)))
+
+		// the ticks approach 100 without reaching it, so in the last populated bucket they stop where fewer than one
+		// recorded value lies above them; any later tick would only repeat the final step.  Since the count above a
+		// tick halves at each level, this ends within 64 levels however many values were recorded.
+		countAboveThisValue := math.Max(1, float64(histogram.totalCount-cumulativeCount))
+
+		for {
+			fractionAboveTick := math.Ldexp(float64(2*ticksPerHalfDistance-tick)/float64(ticksPerHalfDistance), -(level + 1))
+			if fractionAboveTick*float64(histogram.totalCount) < countAboveThisValue {
+				break
+			}
+
+			// percentiles within about 1e-14 of 100 cannot be told apart from each other or from the final step
+			if percentile := 100 - 100*fractionAboveTick; percentile < 100 && (len(steps) == 0 || percentile > steps[len(steps)-1].Percentile) {
+				steps = append(steps, HdrHistogramPercentile{
+					Percentile:          percentile,
+					ValueAtPercentile:   valueAtThisIndex,
+					CountAtOrBelowValue: cumulativeCount,
+				})
+			}
+
+			if tick++; tick == ticksPerHalfDistance {
+				level, tick = level+1, 0
+			}
+		}
+	}
+
+	return append(steps, HdrHistogramPercentile{
+		Percentile:          100,
+		ValueAtPercentile:   histogram.maximumRecordedValue,
+		CountAtOrBelowValue: histogram.totalCount,
+	})
+}
@@ -0,0 +1,267 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+func TestHdrHistogramValueAtPercentile(t *testing.T) {
+	histogram, err := stats.NewHdrHistogram(1, 10000000, 3)
+	if err != nil {
+		t.Fatalf("on NewHdrHistogram() got error: %s", err.Error())
+	}
+
+	for i := int64(0); i < 1000000; i++ {
+		if err := histogram.RecordValue(i); err != nil {
+			t.Fatalf("on RecordValue(%d) got error: %s", i, err.Error())
+		}
+	}
+
+	for _, testCase := range []struct {
+		percentile    float64
+		expectedValue int64
+	}{
+		{50, 500223},
+		{75, 750079},
+		{90, 900095},
+		{95, 950271},
+		{99, 990207},
+		{99.9, 999423},
+		{99.99, 999935},
+	} {
+		if got := histogram.ValueAtPercentile(testCase.percentile); got != testCase.expectedValue {
+			t.Errorf("for percentile (%f) expected value (%d), got (%d)", testCase.percentile, testCase.expectedValue, got)
+		}
+	}
+
+	if histogram.TotalCount() != 1000000 {
+		t.Errorf("expected TotalCount (1000000), got (%d)", histogram.TotalCount())
+	}
+
+	if histogram.Minimum() != 0 || histogram.Maximum() != 999999 {
+		t.Errorf("expected Minimum (0) and Maximum (999999), got (%d) and (%d)", histogram.Minimum(), histogram.Maximum())
+	}
+
+	if math.Abs(histogram.Mean()-499999.5)/499999.5 > 0.001 {
+		t.Errorf("expected Mean within 0.1%% of (499999.5), got (%f)", histogram.Mean())
+	}
+
+	expectedStdev := math.Sqrt((1000000.0*1000000.0 - 1) / 12)
+	if math.Abs(histogram.Stdev()-expectedStdev)/expectedStdev > 0.001 {
+		t.Errorf("expected Stdev within 0.1%% of (%f), got (%f)", expectedStdev, histogram.Stdev())
+	}
+}
+
+func TestHdrHistogramRecordCorrectedValue(t *testing.T) {
+	histogram, _ := stats.NewHdrHistogram(1, 3600000000, 3)
+
+	for i := 0; i < 10000; i++ {
+		histogram.RecordCorrectedValue(1000, 10000)
+	}
+	histogram.RecordCorrectedValue(100000000, 10000)
+
+	if histogram.TotalCount() != 20000 {
+		t.Errorf("expected TotalCount (20000), got (%d)", histogram.TotalCount())
+	}
+
+	if got := histogram.ValueAtPercentile(50); got != 1000 {
+		t.Errorf("for percentile (50) expected value (1000), got (%d)", got)
+	}
+
+	if got := histogram.ValueAtPercentile(75); math.Abs(float64(got)-50000000)/50000000 > 0.001 {
+		t.Errorf("for percentile (75) expected value within 0.1%% of (50000000), got (%d)", got)
+	}
+}
+
+func TestHdrHistogramMergeAndSummary(t *testing.T) {
+	first, _ := stats.NewHdrHistogram(1, 100000, 3)
+	second, _ := stats.NewHdrHistogram(1, 1000, 2)
+
+	for i := int64(1); i <= 100; i++ {
+		first.RecordValue(i)
+		second.RecordValue(i + 100)
+	}
+
+	if err := first.Merge(second); err != nil {
+		t.Fatalf("on Merge() got error: %s", err.Error())
+	}
+
+	summary, err := first.Summary()
+	if err != nil {
+		t.Fatalf("on Summary() got error: %s", err.Error())
+	}
+
+	var s stats.SampleSetSummary = summary
+
+	if s.Count() != 200 || s.Minimum() != 1 || s.Maximum() != 200 {
+		t.Errorf("expected Count (200), Minimum (1) and Maximum (200), got (%d), (%f) and (%f)", s.Count(), s.Minimum(), s.Maximum())
+	}
+
+	if s.Median() != 100 {
+		t.Errorf("expected Median (100), got (%f)", s.Median())
+	}
+
+	if math.Abs(s.Mean()-100.5) > 0.5 {
+		t.Errorf("expected Mean near (100.5), got (%f)", s.Mean())
+	}
+
+	if s.ValueNearestPercentile(100) != 200 {
+		t.Errorf("expected ValueNearestPercentile(100) = (200), got (%f)", s.ValueNearestPercentile(100))
+	}
+
+	small, _ := stats.NewHdrHistogram(1, 100, 2)
+	if err := small.Merge(first); err != stats.ErrorValueOutOfRange {
+		t.Errorf("on Merge() into histogram with smaller range, expected ErrorValueOutOfRange, got (%v)", err)
+	}
+	if small.TotalCount() != 0 {
+		t.Errorf("expected failed Merge() to leave histogram empty, but TotalCount is (%d)", small.TotalCount())
+	}
+}
+
+func TestHdrHistogramRecordsHighestTrackableValue(t *testing.T) {
+	for testIndex, testCase := range []struct {
+		lowest, highest int64
+		figures         int
+	}{
+		{1, 2048, 3},
+		{1, 4096, 3},
+		{1, 1 << 20, 3},
+		{8, 16384, 3},
+		{1, 256, 2},
+		{1, 1000, 3},
+		{1, math.MaxInt64, 3},
+	} {
+		histogram, err := stats.NewHdrHistogram(testCase.lowest, testCase.highest, testCase.figures)
+		if err != nil {
+			t.Fatalf("on test with index (%d) NewHdrHistogram(%d, %d, %d) got error: %s", testIndex, testCase.lowest, testCase.highest, testCase.figures, err)
+		}
+
+		if err := histogram.RecordValue(testCase.highest); err != nil {
+			t.Errorf("on test with index (%d) RecordValue(%d) got error: %s", testIndex, testCase.highest, err)
+			continue
+		}
+
+		if histogram.TotalCount() != 1 || histogram.Maximum() != testCase.highest {
+			t.Errorf("on test with index (%d) expected TotalCount (1) and Maximum (%d), got (%d) and (%d)", testIndex, testCase.highest, histogram.TotalCount(), histogram.Maximum())
+		}
+	}
+}
+
+func TestHdrHistogramPercentiles(t *testing.T) {
+	histogram, _ := stats.NewHdrHistogram(1, 10000, 3)
+	for i := int64(1); i <= 1000; i++ {
+		histogram.RecordValue(i)
+	}
+
+	steps := histogram.Percentiles(5)
+	if len(steps) < 2 {
+		t.Fatalf("expected at least two percentile steps, got (%d)", len(steps))
+	}
+
+	if steps[0].Percentile != 0 || steps[0].ValueAtPercentile != 1 {
+		t.Errorf("expected first step at percentile (0) with value (1), got percentile (%f) with value (%d)", steps[0].Percentile, steps[0].ValueAtPercentile)
+	}
+
+	last := steps[len(steps)-1]
+	if last.Percentile != 100 || last.ValueAtPercentile != 1000 || last.CountAtOrBelowValue != 1000 {
+		t.Errorf("expected last step at percentile (100) with value (1000) and count (1000), got (%f), (%d) and (%d)", last.Percentile, last.ValueAtPercentile, last.CountAtOrBelowValue)
+	}
+
+	for i := 1; i < len(steps); i++ {
+		if steps[i].Percentile <= steps[i-1].Percentile || steps[i].ValueAtPercentile < steps[i-1].ValueAtPercentile {
+			t.Errorf("expected steps to be increasing, but step (%d) is (%+v) and step (%d) is (%+v)", i-1, steps[i-1], i, steps[i])
+		}
+	}
+}
+
+func TestHdrHistogramPercentilesIncludeLastBucket(t *testing.T) {
+	singleBucket, _ := stats.NewHdrHistogram(1, 10000, 3)
+	singleBucket.RecordValues(5, 2)
+
+	twoBuckets, _ := stats.NewHdrHistogram(1, 10000, 3)
+	twoBuckets.RecordValue(1)
+	twoBuckets.RecordValues(1000, 3)
+
+	for testIndex, testCase := range []struct {
+		histogram     *stats.HdrHistogram
+		expectedSteps []stats.HdrHistogramPercentile
+	}{
+		{singleBucket, []stats.HdrHistogramPercentile{{0, 5, 2}, {50, 5, 2}, {100, 5, 2}}},
+		{twoBuckets, []stats.HdrHistogramPercentile{{0, 1, 1}, {50, 1000, 4}, {75, 1000, 4}, {100, 1000, 4}}},
+	} {
+		steps := testCase.histogram.Percentiles(1)
+
+		if len(steps) != len(testCase.expectedSteps) {
+			t.Errorf("on test with index (%d) expected steps %v, got %v", testIndex, testCase.expectedSteps, steps)
+			continue
+		}
+
+		for i := range steps {
+			if steps[i] != testCase.expectedSteps[i] {
+				t.Errorf("on test with index (%d) expected steps %v, got %v", testIndex, testCase.expectedSteps, steps)
+				break
+			}
+		}
+	}
+}
+
+func TestHdrHistogramPercentilesOfVeryLargeCounts(t *testing.T) {
+	// with 1e17 values recorded, 100 - 100/1e17 rounds to 100
+	histogram, _ := stats.NewHdrHistogram(1, 10000, 3)
+	histogram.RecordValue(1)
+	histogram.RecordValues(1000, 1e17)
+
+	steps := histogram.Percentiles(1)
+
+	// one step at 0, one for each level until the percentiles round to 100, and one at 100
+	if len(steps) != 55 {
+		t.Fatalf("expected (55) steps, got (%d): %v", len(steps), steps)
+	}
+
+	if steps[0] != (stats.HdrHistogramPercentile{Percentile: 0, ValueAtPercentile: 1, CountAtOrBelowValue: 1}) || steps[54] != (stats.HdrHistogramPercentile{Percentile: 100, ValueAtPercentile: 1000, CountAtOrBelowValue: 1e17 + 1}) {
+		t.Errorf("expected first and last steps {0 1 1} and {100 1000 100000000000000001}, got %v and %v", steps[0], steps[54])
+	}
+
+	for i := 1; i < len(steps); i++ {
+		if steps[i].Percentile <= steps[i-1].Percentile {
+			t.Errorf("expected steps to be increasing, but step (%d) is (%+v) and step (%d) is (%+v)", i-1, steps[i-1], i, steps[i])
+		}
+	}
+}
+
+func TestHdrHistogramErrors(t *testing.T) {
+	for _, testCase := range []struct {
+		lowest, highest int64
+		figures         int
+	}{
+		{0, 1000, 3},
+		{10, 15, 3},
+		{1, 1000, 0},
+		{1, 1000, 6},
+	} {
+		if _, err := stats.NewHdrHistogram(testCase.lowest, testCase.highest, testCase.figures); err == nil {
+			t.Errorf("on NewHdrHistogram(%d, %d, %d) expected error, got none", testCase.lowest, testCase.highest, testCase.figures)
+		}
+	}
+
+	histogram, _ := stats.NewHdrHistogram(1, 1000, 3)
+	if err := histogram.RecordValue(-1); err != stats.ErrorValueOutOfRange {
+		t.Errorf("on RecordValue(-1) expected ErrorValueOutOfRange, got (%v)", err)
+	}
+	if err := histogram.RecordValue(1001); err != stats.ErrorValueOutOfRange {
+		t.Errorf("on RecordValue(1001) expected ErrorValueOutOfRange, got (%v)", err)
+	}
+
+	if err := histogram.RecordValues(10, -1); err == nil {
+		t.Errorf("on RecordValues(10, -1) expected error, got none")
+	}
+	if err := histogram.RecordValues(10, 0); err != nil || histogram.TotalCount() != 0 || histogram.Maximum() != 0 {
+		t.Errorf("on RecordValues(10, 0) expected no error, TotalCount (0) and Maximum (0), got (%v), (%d) and (%d)", err, histogram.TotalCount(), histogram.Maximum())
+	}
+
+	if _, err := histogram.Summary(); err == nil {
+		t.Errorf("on Summary() of empty histogram expected error, got none")
+	}
+}
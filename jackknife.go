@@ -0,0 +1,138 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+)
+
+// A JackknifeResult is the result of Jackknife.  Estimate is the statistic computed on the original set.
+// Replicates[i] is the statistic computed on the set with its (i+1)th smallest value removed.  Bias is the jackknife
+// estimate of the bias of the statistic, BiasCorrectedEstimate is Estimate minus Bias, and StandardError is the
+// jackknife estimate of the standard error of the statistic.
+type JackknifeResult struct {
+	Estimate              float64
+	Replicates            []float64
+	Bias                  float64
+	BiasCorrectedEstimate float64
+	StandardError         float64
+}
+
+// Jackknife computes the leave-one-out replicates of statistic and the resulting estimates of its bias and standard
+// error.  The set must have at least two values.  Rather than building a new set for each replicate, a single set is
+// updated as each value is removed in turn, so the count, sum, minimum, maximum, mean and variance of each replicate
+// are available in constant time and ordered statistics need no sorting.  The statistic is computed only once for
+// each distinct value, since removing any copy of a value yields the same set.  The set passed to statistic is valid
+// only for the duration of the call, and must not be modified or retained.
+//
+// The jackknife is reliable for smooth statistics such as the mean and variance, but its standard error is not
+// consistent for non-smooth statistics such as the median and other quantiles, for which Bootstrap is preferable.
+func Jackknife(set *StatisticalSampleSet, statistic SetStatistic) (*JackknifeResult, error) {
+	if statistic == nil {
+		return nil, fmt.Errorf("statistic must not be nil")
+	}
+
+	if set.Count() < 2 {
+		return nil, fmt.Errorf("there must be at least two samples in the set")
+	}
+
+	estimate := statistic(set)
+	if math.IsNaN(estimate) {
+		return nil, fmt.Errorf("statistic is NaN for the set")
+	}
+
+	distinctReplicates, multiplicities := leaveOneOutReplicatesOfDistinctValues(set, statistic)
+
+	replicates := make([]float64, 0, set.Count())
+	for i, replicate := range distinctReplicates {
+		if math.IsNaN(replicate) {
+			return nil, fmt.Errorf("statistic is NaN for the set with one value removed")
+		}
+
+		for j := 0; j < multiplicities[i]; j++ {
+			replicates = append(replicates, replicate)
+		}
+	}
+
+	n := float64(len(replicates))
+
+	sum := float64(0)
+	for _, replicate := range replicates {
+		sum += replicate
+	}
+	meanOfReplicates := sum / n
+
+	sumOfSquaredDeviations := float64(0)
+	for _, replicate := range replicates {
+		deviation := replicate - meanOfReplicates
+		sumOfSquaredDeviations += deviation * deviation
+	}
+
+	bias := (n - 1) * (meanOfReplicates - estimate)
+
+	return &JackknifeResult{
+		Estimate:              estimate,
+		Replicates:            replicates,
+		Bias:                  bias,
+		BiasCorrectedEstimate: estimate - bias,
+		StandardError:         math.Sqrt((n - 1) / n * sumOfSquaredDeviations),
+	}, nil
+}
+
+// leaveOneOutReplicatesOfDistinctValues returns the statistic computed on set with one copy of each distinct value
+// removed, in ascending order of the removed value, along with the number of copies of that value.  It returns nil if
+// the set has only one value.
+func leaveOneOutReplicatesOfDistinctValues(set *StatisticalSampleSet, statistic SetStatistic) (replicates []float64, multiplicities []int) {
+	set.mutex.Lock()
+	sortedValues := set.sortedValues()
+	sum := set.sumOfAllValuesInTheSet
+	mean := set.mean()
+	sumOfSquaredDeviations := set.varianceTracker.Variance()
+	set.mutex.Unlock()
+
+	n := len(sortedValues)
+	if n < 2 {
+		return nil, nil
+	}
+
+	// valuesWithOneRemoved holds sortedValues without the value at index removed, so moving on to a later index
+	// only requires copying the values between the two indices back into place.
+	valuesWithOneRemoved := make([]float64, n-1)
+	copy(valuesWithOneRemoved, sortedValues[1:])
+	removed := 0
+
+	for first := 0; first < n; {
+		last := first
+		for last+1 < n && sortedValues[last+1] == sortedValues[first] {
+			last++
+		}
+
+		for ; removed < first; removed++ {
+			valuesWithOneRemoved[removed] = sortedValues[removed]
+		}
+
+		value := sortedValues[first]
+		deviation := value - mean
+
+		replicateSet := &StatisticalSampleSet{
+			valuesSortedInAscendingOrder: valuesWithOneRemoved,
+			sumOfAllValuesInTheSet:       sum - value,
+			minimumValueInTheSet:         valuesWithOneRemoved[0],
+			maximumValueInTheSet:         valuesWithOneRemoved[n-2],
+		}
+		replicateSet.distributionTracker = newValueDistributionTracker(valuesWithOneRemoved)
+		replicateSet.modeTracker = newModalTracker(replicateSet.distributionTracker)
+		replicateSet.varianceTracker = &varianceTracker{
+			haveSummedDataPointVariances:    true,
+			setOfDataPoints:                 valuesWithOneRemoved,
+			sampleSetContainerForDataPoints: replicateSet,
+			summedDataPointVariances:        math.Max(0, sumOfSquaredDeviations-deviation*deviation*float64(n)/float64(n-1)),
+		}
+
+		replicates = append(replicates, statistic(replicateSet))
+		multiplicities = append(multiplicities, last-first+1)
+
+		first = last + 1
+	}
+
+	return replicates, multiplicities
+}
@@ -0,0 +1,106 @@
+package stats_test
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+func TestJackknifeReplicatesMatchRebuiltSets(t *testing.T) {
+	values := []float64{4.5, -1, 3, 3, 10, 0.25, 3, 7, -1, 12.5, 6}
+
+	set, _ := stats.MakeStatisticalSampleSetFrom(values[:6])
+	set.AddMany(values[6:])
+
+	sortedValues := append([]float64{}, values...)
+	sort.Float64s(sortedValues)
+
+	for testIndex, statistic := range []stats.SetStatistic{
+		func(s *stats.StatisticalSampleSet) float64 { return s.Mean() },
+		func(s *stats.StatisticalSampleSet) float64 { return s.SampleVariance() },
+		func(s *stats.StatisticalSampleSet) float64 { return s.PopulationStdev() },
+		func(s *stats.StatisticalSampleSet) float64 { return s.Median() },
+		func(s *stats.StatisticalSampleSet) float64 { return s.Quantile(0.9, stats.QuantileDefault) },
+		func(s *stats.StatisticalSampleSet) float64 { return float64(s.Count()) },
+		func(s *stats.StatisticalSampleSet) float64 { return s.Minimum() },
+		func(s *stats.StatisticalSampleSet) float64 { return s.Maximum() },
+		func(s *stats.StatisticalSampleSet) float64 { return s.Range() },
+		func(s *stats.StatisticalSampleSet) float64 { count, _ := s.Mode(); return float64(count) },
+		func(s *stats.StatisticalSampleSet) float64 { _, _, iqr := s.InterQuartileRange(); return iqr },
+	} {
+		result, err := stats.Jackknife(set, statistic)
+		if err != nil {
+			t.Errorf("on test with index (%d) got unexpected error: %s", testIndex, err)
+			continue
+		}
+
+		if len(result.Replicates) != len(values) {
+			t.Errorf("on test with index (%d) expected (%d) replicates, got (%d)", testIndex, len(values), len(result.Replicates))
+			continue
+		}
+
+		for i := range sortedValues {
+			valuesWithOneRemoved := append(append([]float64{}, sortedValues[:i]...), sortedValues[i+1:]...)
+			rebuiltSet, _ := stats.MakeStatisticalSampleSetFrom(valuesWithOneRemoved)
+			expected := statistic(rebuiltSet)
+
+			if math.Abs(result.Replicates[i]-expected) > 1e-12*math.Max(1, math.Abs(expected)) {
+				t.Errorf("on test with index (%d) expected replicate (%d) to be (%f), got (%f)", testIndex, i, expected, result.Replicates[i])
+			}
+		}
+	}
+}
+
+func TestJackknifeEstimatesOfBiasAndStandardError(t *testing.T) {
+	set, _ := stats.MakeStatisticalSampleSetFrom(sleepGroup2)
+
+	mean, err := stats.Jackknife(set, func(s *stats.StatisticalSampleSet) float64 { return s.Mean() })
+	if err != nil {
+		t.Fatalf("on mean got unexpected error: %s", err)
+	}
+
+	// the jackknife standard error of the mean is the usual standard error, and the mean is unbiased
+	if expected := set.SampleStdev() / math.Sqrt(float64(set.Count())); math.Abs(mean.StandardError-expected) > 1e-12 {
+		t.Errorf("on mean expected StandardError (%f), got (%f)", expected, mean.StandardError)
+	}
+
+	if math.Abs(mean.Bias) > 1e-12 || mean.Estimate != set.Mean() {
+		t.Errorf("on mean expected Estimate (%f) and Bias (0), got (%f) and (%f)", set.Mean(), mean.Estimate, mean.Bias)
+	}
+
+	// bias-correcting the population variance yields the sample variance
+	variance, err := stats.Jackknife(set, func(s *stats.StatisticalSampleSet) float64 { return s.PopulationVariance() })
+	if err != nil {
+		t.Fatalf("on population variance got unexpected error: %s", err)
+	}
+
+	if math.Abs(variance.BiasCorrectedEstimate-set.SampleVariance()) > 1e-12 {
+		t.Errorf("on population variance expected BiasCorrectedEstimate (%f), got (%f)", set.SampleVariance(), variance.BiasCorrectedEstimate)
+	}
+
+	if math.Abs(variance.Estimate-variance.Bias-variance.BiasCorrectedEstimate) > 1e-12 {
+		t.Errorf("on population variance expected BiasCorrectedEstimate to be Estimate minus Bias, got (%f), (%f) and (%f)", variance.Estimate, variance.Bias, variance.BiasCorrectedEstimate)
+	}
+}
+
+func TestJackknifeErrors(t *testing.T) {
+	single, _ := stats.MakeStatisticalSampleSetFrom([]float64{1})
+	two, _ := stats.MakeStatisticalSampleSetFrom([]float64{1, 2})
+	meanOf := func(s *stats.StatisticalSampleSet) float64 { return s.Mean() }
+
+	for testIndex, testCase := range []struct {
+		set       *stats.StatisticalSampleSet
+		statistic stats.SetStatistic
+	}{
+		{two, nil},
+		{single, meanOf},
+		{two, func(s *stats.StatisticalSampleSet) float64 { return s.SampleVariance() }},
+		{two, func(s *stats.StatisticalSampleSet) float64 { return math.NaN() }},
+	} {
+		if _, err := stats.Jackknife(testCase.set, testCase.statistic); err == nil {
+			t.Errorf("on test with index (%d) expected error, got none", testIndex)
+		}
+	}
+}
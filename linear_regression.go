@@ -0,0 +1,144 @@
+package stats
+
+import (
+	"math"
+)
+
+// A LinearRegressionResult is the ordinary least squares fit of the line y = Intercept + Slope*x to the pairs in a
+// PairedSampleSet.  The t statistics and p-values test whether each coefficient differs from zero (two-sided), with
+// DegreesOfFreedom (the number of pairs less two) degrees of freedom.  Residuals[i] is the observed minus the fitted
+// value of the ith pair, in the order in which the pairs were supplied.  DurbinWatson is the Durbin–Watson statistic
+// of the residuals in that order, which is near 2 when successive residuals are uncorrelated, and nearer 0 or 4 when
+// they are positively or negatively autocorrelated (as is common when x is time).
+type LinearRegressionResult struct {
+	Slope                  float64
+	Intercept              float64
+	SlopeStandardError     float64
+	InterceptStandardError float64
+	SlopeTStatistic        float64
+	InterceptTStatistic    float64
+	SlopePValue            float64
+	InterceptPValue        float64
+	DegreesOfFreedom       float64
+	RSquared               float64
+	AdjustedRSquared       float64
+	ResidualStandardError  float64
+	Residuals              []float64
+	DurbinWatson           float64
+
+	numberOfPairs                     float64
+	meanOfX                           float64
+	sumOfSquaredDeviationsOfXFromMean float64
+}
+
+// A LinearRegressionPrediction is the value of a fitted line at some x, with a confidence interval for the mean of
+// y at x and a (wider) prediction interval for a single new observation of y at x.
+type LinearRegressionPrediction struct {
+	X                  float64
+	Value              float64
+	ConfidenceInterval Interval
+	PredictionInterval Interval
+}
+
+// LinearRegression fits the line y = a + b*x to the pairs in set by ordinary least squares.  The standard errors,
+// p-values and intervals assume that the deviations of y from the line are independent and normally distributed with
+// the same variance.  There must be at least three pairs, and neither x nor y may be constant.
+func LinearRegression(set *PairedSampleSet) (*LinearRegressionResult, error) {
+	if err := errorIfPairedSampleSetIsTooSmall(set, 3); err != nil {
+		return nil, err
+	}
+
+	sxx, syy, sxy := set.sumsOfSquaredDeviations()
+	n := float64(set.Count())
+	meanOfX, meanOfY := set.xSet.Mean(), set.ySet.Mean()
+
+	slope := sxy / sxx
+	intercept := meanOfY - slope*meanOfX
+
+	residuals := make([]float64, set.Count())
+	sumOfSquaredResiduals, sumOfSquaredSuccessiveDifferences := float64(0), float64(0)
+	for i, x := range set.xValues {
+		residuals[i] = set.yValues[i] - (intercept + slope*x)
+		sumOfSquaredResiduals += residuals[i] * residuals[i]
+
+		if i > 0 {
+			difference := residuals[i] - residuals[i-1]
+			sumOfSquaredSuccessiveDifferences += difference * difference
+		}
+	}
+
+	degreesOfFreedom := n - 2
+	residualStandardError := math.Sqrt(sumOfSquaredResiduals / degreesOfFreedom)
+	rSquared := 1 - sumOfSquaredResiduals/syy
+
+	result := &LinearRegressionResult{
+		Slope:                  slope,
+		Intercept:              intercept,
+		SlopeStandardError:     residualStandardError / math.Sqrt(sxx),
+		InterceptStandardError: residualStandardError * math.Sqrt(1/n+meanOfX*meanOfX/sxx),
+		DegreesOfFreedom:       degreesOfFreedom,
+		RSquared:               rSquared,
+		AdjustedRSquared:       1 - (1-rSquared)*(n-1)/degreesOfFreedom,
+		ResidualStandardError:  residualStandardError,
+		Residuals:              residuals,
+		DurbinWatson:           sumOfSquaredSuccessiveDifferences / sumOfSquaredResiduals,
+
+		numberOfPairs:                     n,
+		meanOfX:                           meanOfX,
+		sumOfSquaredDeviationsOfXFromMean: sxx,
+	}
+
+	result.SlopeTStatistic = slope / result.SlopeStandardError
+	result.InterceptTStatistic = intercept / result.InterceptStandardError
+	result.SlopePValue = 2 * studentTCDF(-math.Abs(result.SlopeTStatistic), degreesOfFreedom)
+	result.InterceptPValue = 2 * studentTCDF(-math.Abs(result.InterceptTStatistic), degreesOfFreedom)
+
+	return result, nil
+}
+
+// SlopeConfidenceInterval returns the two-sided confidence interval for the slope, based on Student's t-distribution.
+// The confidence level must be greater than 0 and less than 1.
+func (result *LinearRegressionResult) SlopeConfidenceInterval(level float64) (*Interval, error) {
+	return result.coefficientConfidenceInterval(result.Slope, result.SlopeStandardError, level)
+}
+
+// InterceptConfidenceInterval returns the two-sided confidence interval for the intercept, based on Student's
+// t-distribution.  The confidence level must be greater than 0 and less than 1.
+func (result *LinearRegressionResult) InterceptConfidenceInterval(level float64) (*Interval, error) {
+	return result.coefficientConfidenceInterval(result.Intercept, result.InterceptStandardError, level)
+}
+
+func (result *LinearRegressionResult) coefficientConfidenceInterval(estimate float64, standardError float64, level float64) (*Interval, error) {
+	if err := errorIfConfidenceLevelIsNotValid(level); err != nil {
+		return nil, err
+	}
+
+	margin := studentTQuantile((1+level)/2, result.DegreesOfFreedom) * standardError
+
+	return &Interval{Lower: estimate - margin, Upper: estimate + margin, Level: level, Method: IntervalStudentT}, nil
+}
+
+// Predict returns the value of the fitted line at x, with two-sided confidence and prediction intervals at the given
+// confidence level, which must be greater than 0 and less than 1.  The intervals widen as x moves away from the mean
+// of the x values, and extrapolating beyond the range of the x values assumes that the relationship remains linear.
+func (result *LinearRegressionResult) Predict(x float64, confidenceLevel float64) (*LinearRegressionPrediction, error) {
+	if err := errorIfConfidenceLevelIsNotValid(confidenceLevel); err != nil {
+		return nil, err
+	}
+
+	value := result.Intercept + result.Slope*x
+	criticalValue := studentTQuantile((1+confidenceLevel)/2, result.DegreesOfFreedom)
+
+	deviationOfX := x - result.meanOfX
+	leverage := 1/result.numberOfPairs + deviationOfX*deviationOfX/result.sumOfSquaredDeviationsOfXFromMean
+
+	confidenceMargin := criticalValue * result.ResidualStandardError * math.Sqrt(leverage)
+	predictionMargin := criticalValue * result.ResidualStandardError * math.Sqrt(1+leverage)
+
+	return &LinearRegressionPrediction{
+		X:                  x,
+		Value:              value,
+		ConfidenceInterval: Interval{Lower: value - confidenceMargin, Upper: value + confidenceMargin, Level: confidenceLevel, Method: IntervalStudentT},
+		PredictionInterval: Interval{Lower: value - predictionMargin, Upper: value + predictionMargin, Level: confidenceLevel, Method: IntervalStudentT},
+	}, nil
+}
@@ -0,0 +1,108 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+var regressionX = []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+var regressionY = []float64{2.3, 2.9, 4.4, 4.2, 5.9, 6.1, 7.8, 7.6, 9.4, 9.9}
+
+func TestLinearRegression(t *testing.T) {
+	set, _ := stats.MakePairedSampleSetFrom(regressionX, regressionY)
+
+	fit, err := stats.LinearRegression(set)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	for testIndex, testCase := range []struct {
+		name     string
+		expected float64
+		got      float64
+	}{
+		{"Slope", 0.853939393939394, fit.Slope},
+		{"Intercept", 1.3533333333333326, fit.Intercept},
+		{"SlopeStandardError", 0.047752037826890206, fit.SlopeStandardError},
+		{"InterceptStandardError", 0.2962936026813589, fit.InterceptStandardError},
+		{"SlopeTStatistic", 17.882784333415866, fit.SlopeTStatistic},
+		{"InterceptTStatistic", 4.567541523293499, fit.InterceptTStatistic},
+		{"SlopePValue", 9.797120553611758e-08, fit.SlopePValue},
+		{"InterceptPValue", 0.0018316866932303055, fit.InterceptPValue},
+		{"DegreesOfFreedom", 8, fit.DegreesOfFreedom},
+		{"RSquared", 0.9755944263849883, fit.RSquared},
+		{"AdjustedRSquared", 0.9725437296831119, fit.AdjustedRSquared},
+		{"ResidualStandardError", 0.4337294227063828, fit.ResidualStandardError},
+		{"DurbinWatson", 3.7097315780224913, fit.DurbinWatson},
+	} {
+		if math.Abs(testCase.got-testCase.expected) > 1e-8*math.Abs(testCase.expected) {
+			t.Errorf("on test with index (%d) expected %s (%g), got (%g)", testIndex, testCase.name, testCase.expected, testCase.got)
+		}
+	}
+
+	expectedResiduals := []float64{0.0927273, -0.1612121, 0.4848485, -0.5690909, 0.2769697, -0.3769697, 0.4690909, -0.5848485, 0.3612121, 0.0072727}
+	for i, expected := range expectedResiduals {
+		if math.Abs(fit.Residuals[i]-expected) > 1e-7 {
+			t.Errorf("on residual (%d) expected (%f), got (%f)", i, expected, fit.Residuals[i])
+		}
+	}
+
+	slopeInterval, _ := fit.SlopeConfidenceInterval(0.95)
+	interceptInterval, _ := fit.InterceptConfidenceInterval(0.95)
+	if math.Abs(slopeInterval.Lower-0.7438229972461626) > 1e-9 || math.Abs(slopeInterval.Upper-0.9640557906326254) > 1e-9 {
+		t.Errorf("expected slope interval [0.743823, 0.964056], got %s", slopeInterval)
+	}
+	if math.Abs(interceptInterval.Lower-0.6700790603155986) > 1e-9 || math.Abs(interceptInterval.Upper-2.0365876063510666) > 1e-9 {
+		t.Errorf("expected intercept interval [0.670079, 2.036588], got %s", interceptInterval)
+	}
+}
+
+func TestLinearRegressionPredict(t *testing.T) {
+	set, _ := stats.MakePairedSampleSetFrom(regressionX, regressionY)
+	fit, _ := stats.LinearRegression(set)
+
+	for testIndex, testCase := range []struct {
+		x                  float64
+		expectedValue      float64
+		expectedConfidence stats.Interval
+		expectedPrediction stats.Interval
+	}{
+		{5.5, 6.05, stats.Interval{Lower: 5.733714730392349, Upper: 6.36628526960765}, stats.Interval{Lower: 5.001000433995025, Upper: 7.0989995660049745}},
+		{12, 11.600606060606061, stats.Interval{Lower: 10.818082034492226, Upper: 12.383130086719897}, stats.Interval{Lower: 10.33068182304177, Upper: 12.870530298170353}},
+	} {
+		got, err := fit.Predict(testCase.x, 0.95)
+		if err != nil {
+			t.Errorf("on test with index (%d) got unexpected error: %s", testIndex, err)
+			continue
+		}
+
+		if math.Abs(got.Value-testCase.expectedValue) > 1e-9 {
+			t.Errorf("on test with index (%d) expected Value (%f), got (%f)", testIndex, testCase.expectedValue, got.Value)
+		}
+
+		if math.Abs(got.ConfidenceInterval.Lower-testCase.expectedConfidence.Lower) > 1e-9 || math.Abs(got.ConfidenceInterval.Upper-testCase.expectedConfidence.Upper) > 1e-9 {
+			t.Errorf("on test with index (%d) expected ConfidenceInterval %s, got %s", testIndex, &testCase.expectedConfidence, &got.ConfidenceInterval)
+		}
+
+		if math.Abs(got.PredictionInterval.Lower-testCase.expectedPrediction.Lower) > 1e-9 || math.Abs(got.PredictionInterval.Upper-testCase.expectedPrediction.Upper) > 1e-9 {
+			t.Errorf("on test with index (%d) expected PredictionInterval %s, got %s", testIndex, &testCase.expectedPrediction, &got.PredictionInterval)
+		}
+	}
+
+	if _, err := fit.Predict(5, 1.5); err == nil {
+		t.Errorf("on confidence level (1.5) expected error, got none")
+	}
+}
+
+func TestLinearRegressionErrors(t *testing.T) {
+	twoPairs, _ := stats.MakePairedSampleSetFrom([]float64{1, 2}, []float64{1, 2})
+	constantX, _ := stats.MakePairedSampleSetFrom([]float64{3, 3, 3}, []float64{1, 2, 3})
+
+	for testIndex, set := range []*stats.PairedSampleSet{twoPairs, constantX} {
+		if _, err := stats.LinearRegression(set); err == nil {
+			t.Errorf("on test with index (%d) expected error, got none", testIndex)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// A Matrix is a small dense matrix of float64 values, stored in row-major order.  It provides only the operations
+// needed by the regressions in this package.  Methods that take a row or column index panic if it is out of range.
+type Matrix struct {
+	rows    int
+	columns int
+	values  []float64
+}
+
+// NewMatrix returns a matrix of zeros with the given number of rows and columns, each of which must be at least one.
+func NewMatrix(rows int, columns int) (*Matrix, error) {
+	if rows < 1 || columns < 1 {
+		return nil, fmt.Errorf("a matrix must have at least one row and one column")
+	}
+
+	return &Matrix{rows: rows, columns: columns, values: make([]float64, rows*columns)}, nil
+}
+
+// MakeMatrixFromRows returns a matrix whose ith row is a copy of rows[i].  There must be at least one row, and every
+// row must have the same number of values, which must be at least one.
+func MakeMatrixFromRows(rows [][]float64) (*Matrix, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("a matrix must have at least one row and one column")
+	}
+
+	matrix, err := NewMatrix(len(rows), len(rows[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, row := range rows {
+		if len(row) != matrix.columns {
+			return nil, fmt.Errorf("row %d has %d values, but row 0 has %d", i, len(row), matrix.columns)
+		}
+
+		copy(matrix.values[i*matrix.columns:], row)
+	}
+
+	return matrix, nil
+}
+
+// MakeMatrixFromColumns returns a matrix whose jth column is a copy of columns[j].  There must be at least one column,
+// and every column must have the same number of values, which must be at least one.
+func MakeMatrixFromColumns(columns [][]float64) (*Matrix, error) {
+	transpose, err := MakeMatrixFromRows(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	return transpose.Transpose(), nil
+}
+
+// Rows returns the number of rows in the matrix.
+func (matrix *Matrix) Rows() int {
+	return matrix.rows
+}
+
+// Columns returns the number of columns in the matrix.
+func (matrix *Matrix) Columns() int {
+	return matrix.columns
+}
+
+// At returns the value in row i and column j.
+func (matrix *Matrix) At(i int, j int) float64 {
+	matrix.panicIfIndexesAreOutOfRange(i, j)
+	return matrix.values[i*matrix.columns+j]
+}
+
+// Set sets the value in row i and column j.
+func (matrix *Matrix) Set(i int, j int, value float64) {
+	matrix.panicIfIndexesAreOutOfRange(i, j)
+	matrix.values[i*matrix.columns+j] = value
+}
+
+// Row returns a copy of row i.
+func (matrix *Matrix) Row(i int) []float64 {
+	matrix.panicIfIndexesAreOutOfRange(i, 0)
+
+	row := make([]float64, matrix.columns)
+	copy(row, matrix.values[i*matrix.columns:])
+
+	return row
+}
+
+// Column returns a copy of column j.
+func (matrix *Matrix) Column(j int) []float64 {
+	matrix.panicIfIndexesAreOutOfRange(0, j)
+
+	column := make([]float64, matrix.rows)
+	for i := range column {
+		column[i] = matrix.values[i*matrix.columns+j]
+	}
+
+	return column
+}
+
+// Transpose returns a new matrix that is the transpose of this one.
+func (matrix *Matrix) Transpose() *Matrix {
+	transpose := &Matrix{rows: matrix.columns, columns: matrix.rows, values: make([]float64, len(matrix.values))}
+
+	for i := 0; i < matrix.rows; i++ {
+		for j := 0; j < matrix.columns; j++ {
+			transpose.values[j*transpose.columns+i] = matrix.values[i*matrix.columns+j]
+		}
+	}
+
+	return transpose
+}
+
+// Multiply returns the matrix product of this matrix and other.  The number of columns of this matrix must equal the
+// number of rows of other.
+func (matrix *Matrix) Multiply(other *Matrix) (*Matrix, error) {
+	if matrix.columns != other.rows {
+		return nil, fmt.Errorf("cannot multiply a %dx%d matrix by a %dx%d matrix", matrix.rows, matrix.columns, other.rows, other.columns)
+	}
+
+	product := &Matrix{rows: matrix.rows, columns: other.columns, values: make([]float64, matrix.rows*other.columns)}
+
+	for i := 0; i < matrix.rows; i++ {
+		for k := 0; k < matrix.columns; k++ {
+			a := matrix.values[i*matrix.columns+k]
+			for j := 0; j < other.columns; j++ {
+				product.values[i*product.columns+j] += a * other.values[k*other.columns+j]
+			}
+		}
+	}
+
+	return product, nil
+}
+
+func (matrix *Matrix) String() string {
+	var builder strings.Builder
+
+	for i := 0; i < matrix.rows; i++ {
+		builder.WriteString("[")
+		for j := 0; j < matrix.columns; j++ {
+			if j > 0 {
+				builder.WriteString(" ")
+			}
+			fmt.Fprintf(&builder, "%g", matrix.values[i*matrix.columns+j])
+		}
+		builder.WriteString("]\n")
+	}
+
+	return builder.String()
+}
+
+func (matrix *Matrix) panicIfIndexesAreOutOfRange(i int, j int) {
+	if i < 0 || i >= matrix.rows || j < 0 || j >= matrix.columns {
+		panic(fmt.Sprintf("index (%d, %d) is out of range for a %dx%d matrix", i, j, matrix.rows, matrix.columns))
+	}
+}
+
+// A householderQRDecomposition is the QR decomposition of a matrix with at least as many rows as columns, computed
+// using Householder reflections.  Below the diagonal, the columns of qr hold the Householder vectors whose product is
+// Q; above the diagonal, qr holds R, whose diagonal is rDiagonal.
+type householderQRDecomposition struct {
+	qr        *Matrix
+	rDiagonal []float64
+}
+
+// A dependentColumnError is returned by householderQR when a column is zero or is a linear combination of the columns
+// before it, so that the caller may name the column.
+type dependentColumnError struct {
+	column int
+}
+
+func (err *dependentColumnError) Error() string {
+	return fmt.Sprintf("column %d is zero or is a linear combination of the columns before it", err.column)
+}
+
+// householderQR decomposes matrix, which is not modified.  An error is returned if a column of matrix is zero or is
+// (to within rounding error) a linear combination of the columns before it.
+func householderQR(matrix *Matrix) (*householderQRDecomposition, error) {
+	m, n := matrix.rows, matrix.columns
+	if m < n {
+		return nil, fmt.Errorf("there must be at least as many rows as columns")
+	}
+
+	qr := &Matrix{rows: m, columns: n, values: make([]float64, len(matrix.values))}
+	copy(qr.values, matrix.values)
+
+	rDiagonal := make([]float64, n)
+
+	for k := 0; k < n; k++ {
+		normOfOriginalColumn := float64(0)
+		for i := 0; i < m; i++ {
+			normOfOriginalColumn = math.Hypot(normOfOriginalColumn, matrix.values[i*n+k])
+		}
+
+		norm := float64(0)
+		for i := k; i < m; i++ {
+			norm = math.Hypot(norm, qr.values[i*n+k])
+		}
+
+		// norm is the length of the part of column k that is orthogonal to the columns before it
+		if norm <= 1e-10*normOfOriginalColumn || norm == 0 {
+			return nil, &dependentColumnError{column: k}
+		}
+
+		if qr.values[k*n+k] < 0 {
+			norm = -norm
+		}
+
+		for i := k; i < m; i++ {
+			qr.values[i*n+k] /= norm
+		}
+		qr.values[k*n+k]++
+
+		for j := k + 1; j < n; j++ {
+			s := float64(0)
+			for i := k; i < m; i++ {
+				s += qr.values[i*n+k] * qr.values[i*n+j]
+			}
+			s = -s / qr.values[k*n+k]
+			for i := k; i < m; i++ {
+				qr.values[i*n+j] += s * qr.values[i*n+k]
+			}
+		}
+
+		rDiagonal[k] = -norm
+	}
+
+	return &householderQRDecomposition{qr: qr, rDiagonal: rDiagonal}, nil
+}
+
+// solveLeastSquares returns the x that minimizes the Euclidean norm of Ax - b, where A is the decomposed matrix.
+func (decomposition *householderQRDecomposition) solveLeastSquares(b []float64) []float64 {
+	qr, m, n := decomposition.qr.values, decomposition.qr.rows, decomposition.qr.columns
+
+	// apply Q' to b
+	y := make([]float64, m)
+	copy(y, b)
+	for k := 0; k < n; k++ {
+		s := float64(0)
+		for i := k; i < m; i++ {
+			s += qr[i*n+k] * y[i]
+		}
+		s = -s / qr[k*n+k]
+		for i := k; i < m; i++ {
+			y[i] += s * qr[i*n+k]
+		}
+	}
+
+	// solve Rx = Q'b by back substitution
+	x := make([]float64, n)
+	for k := n - 1; k >= 0; k-- {
+		s := y[k]
+		for j := k + 1; j < n; j++ {
+			s -= qr[k*n+j] * x[j]
+		}
+		x[k] = s / decomposition.rDiagonal[k]
+	}
+
+	return x
+}
+
+// inverseOfGramMatrix returns the inverse of A'A, where A is the decomposed matrix, computed as the product of the
+// inverse of R and its transpose, without forming A'A.
+func (decomposition *householderQRDecomposition) inverseOfGramMatrix() *Matrix {
+	qr, n := decomposition.qr.values, decomposition.qr.columns
+
+	// the inverse of R is upper triangular; it is found one column at a time by back substitution
+	inverseOfR := make([]float64, n*n)
+	for j := 0; j < n; j++ {
+		inverseOfR[j*n+j] = 1 / decomposition.rDiagonal[j]
+		for k := j - 1; k >= 0; k-- {
+			s := float64(0)
+			for i := k + 1; i <= j; i++ {
+				s += qr[k*n+i] * inverseOfR[i*n+j]
+			}
+			inverseOfR[k*n+j] = -s / decomposition.rDiagonal[k]
+		}
+	}
+
+	inverse := &Matrix{rows: n, columns: n, values: make([]float64, n*n)}
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			s := float64(0)
+			for k := j; k < n; k++ {
+				s += inverseOfR[i*n+k] * inverseOfR[j*n+k]
+			}
+			inverse.values[i*n+j], inverse.values[j*n+i] = s, s
+		}
+	}
+
+	return inverse
+}
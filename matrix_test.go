@@ -0,0 +1,57 @@
+package stats_test
+
+import (
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+func TestMatrix(t *testing.T) {
+	a, err := stats.MakeMatrixFromRows([][]float64{{1, 2, 3}, {4, 5, 6}})
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	if a.Rows() != 2 || a.Columns() != 3 || a.At(1, 0) != 4 {
+		t.Errorf("expected a 2x3 matrix with (4) at (1, 0), got %dx%d with (%f)", a.Rows(), a.Columns(), a.At(1, 0))
+	}
+
+	b, _ := stats.MakeMatrixFromColumns([][]float64{{1, 0, 2}, {-1, 3, 1}})
+	product, err := a.Multiply(b)
+	if err != nil {
+		t.Fatalf("on Multiply got unexpected error: %s", err)
+	}
+
+	if product.String() != "[7 8]\n[16 17]\n" {
+		t.Errorf("expected product [7 8] [16 17], got %s", product)
+	}
+
+	transpose := a.Transpose()
+	if transpose.Rows() != 3 || transpose.At(2, 1) != 6 || transpose.Row(0)[1] != 4 || a.Column(2)[0] != 3 {
+		t.Errorf("got unexpected transpose %s", transpose)
+	}
+
+	row := a.Row(0)
+	row[0] = 100
+	a.Set(0, 1, -2)
+	if a.At(0, 0) != 1 || a.At(0, 1) != -2 {
+		t.Errorf("expected Row to return a copy and Set to set (0, 1), got %s", a)
+	}
+
+	if _, err := a.Multiply(a); err == nil {
+		t.Errorf("on multiplying a 2x3 matrix by itself expected error, got none")
+	}
+
+	for testIndex, rows := range [][][]float64{{}, {{}}, {{1, 2}, {3}}} {
+		if _, err := stats.MakeMatrixFromRows(rows); err == nil {
+			t.Errorf("on test with index (%d) expected error, got none", testIndex)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("on At(2, 0) of a 2x3 matrix expected panic, got none")
+		}
+	}()
+	a.At(2, 0)
+}
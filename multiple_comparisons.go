@@ -0,0 +1,233 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// A PValueAdjustmentMethod is a method for adjusting the p-values of a family of hypothesis tests so that rejecting
+// those with adjusted p-values at most alpha controls either the family-wise error rate (the probability of any false
+// rejection) or the false discovery rate (the expected proportion of rejections that are false).
+type PValueAdjustmentMethod int
+
+const (
+	// Controls the family-wise error rate under any dependence between the tests.
+	AdjustBonferroni PValueAdjustmentMethod = iota
+	// Controls the family-wise error rate under any dependence, and is uniformly more powerful than Bonferroni.
+	AdjustHolm
+	// Controls the family-wise error rate when the tests are independent or positively dependent.
+	AdjustHochberg
+	// Controls the family-wise error rate under the same conditions as Hochberg, and is more powerful.
+	AdjustHommel
+	// Controls the false discovery rate when the tests are independent or positively dependent.
+	AdjustBenjaminiHochberg
+	// Controls the false discovery rate under any dependence between the tests.
+	AdjustBenjaminiYekutieli
+)
+
+func (method PValueAdjustmentMethod) String() string {
+	switch method {
+	case AdjustBonferroni:
+		return "Bonferroni"
+	case AdjustHolm:
+		return "Holm"
+	case AdjustHochberg:
+		return "Hochberg"
+	case AdjustHommel:
+		return "Hommel"
+	case AdjustBenjaminiHochberg:
+		return "Benjamini-Hochberg"
+	case AdjustBenjaminiYekutieli:
+		return "Benjamini-Yekutieli"
+	}
+
+	return fmt.Sprintf("PValueAdjustmentMethod(%d)", int(method))
+}
+
+// A HypothesisTestResult is the result of a hypothesis test that reports a p-value.  It is implemented by the result
+// of each test in this package.
+type HypothesisTestResult interface {
+	ReportedPValue() float64
+}
+
+func (result *TTestResult) ReportedPValue() float64                      { return result.PValue }
+func (result *RankTestResult) ReportedPValue() float64                   { return result.PValue }
+func (result *KolmogorovSmirnovTestResult) ReportedPValue() float64      { return result.PValue }
+func (result *AndersonDarlingTestResult) ReportedPValue() float64        { return result.PValue }
+func (result *KSampleAndersonDarlingTestResult) ReportedPValue() float64 { return result.PValue }
+func (result *PermutationTestResult) ReportedPValue() float64            { return result.PValue }
+func (result *CorrelationTestResult) ReportedPValue() float64            { return result.PValue }
+
+// A PValueAdjustment is the result of AdjustPValues.  AdjustedPValues[i] and Rejected[i] correspond to the ith p-value
+// passed in, and Rejected[i] is true when AdjustedPValues[i] is at most Alpha.
+type PValueAdjustment struct {
+	AdjustedPValues []float64
+	Rejected        []bool
+	Method          PValueAdjustmentMethod
+	Alpha           float64
+}
+
+// AdjustPValues adjusts a family of p-values using method, matching R's p.adjust(), and rejects the hypotheses whose
+// adjusted p-values are at most alpha.  Each p-value must be in [0, 1], and alpha must be greater than 0 and less
+// than 1.
+func AdjustPValues(pValues []float64, method PValueAdjustmentMethod, alpha float64) (*PValueAdjustment, error) {
+	if method < AdjustBonferroni || method > AdjustBenjaminiYekutieli {
+		return nil, fmt.Errorf("invalid p-value adjustment method")
+	}
+
+	if !(alpha > 0 && alpha < 1) {
+		return nil, fmt.Errorf("alpha must be greater than 0 and less than 1")
+	}
+
+	if len(pValues) == 0 {
+		return nil, fmt.Errorf("there must be at least one p-value")
+	}
+
+	for _, p := range pValues {
+		if probabilityIsNotValid(p) {
+			return nil, fmt.Errorf("p-values must be in [0, 1]")
+		}
+	}
+
+	n := len(pValues)
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return pValues[order[i]] < pValues[order[j]] })
+
+	sortedPValues := make([]float64, n)
+	for i, index := range order {
+		sortedPValues[i] = pValues[index]
+	}
+
+	var adjustedSortedPValues []float64
+	switch method {
+	case AdjustBonferroni:
+		adjustedSortedPValues = bonferroniAdjustmentOfSortedPValues(sortedPValues)
+	case AdjustHolm:
+		adjustedSortedPValues = holmAdjustmentOfSortedPValues(sortedPValues)
+	case AdjustHochberg:
+		adjustedSortedPValues = stepUpAdjustmentOfSortedPValues(sortedPValues, func(rank float64) float64 { return float64(n) - rank + 1 })
+	case AdjustHommel:
+		adjustedSortedPValues = hommelAdjustmentOfSortedPValues(sortedPValues)
+	case AdjustBenjaminiHochberg:
+		adjustedSortedPValues = stepUpAdjustmentOfSortedPValues(sortedPValues, func(rank float64) float64 { return float64(n) / rank })
+	case AdjustBenjaminiYekutieli:
+		harmonicNumber := float64(0)
+		for i := 1; i <= n; i++ {
+			harmonicNumber += 1 / float64(i)
+		}
+		adjustedSortedPValues = stepUpAdjustmentOfSortedPValues(sortedPValues, func(rank float64) float64 { return harmonicNumber * float64(n) / rank })
+	}
+
+	adjustment := &PValueAdjustment{
+		AdjustedPValues: make([]float64, n),
+		Rejected:        make([]bool, n),
+		Method:          method,
+		Alpha:           alpha,
+	}
+
+	for i, index := range order {
+		adjustment.AdjustedPValues[index] = adjustedSortedPValues[i]
+		adjustment.Rejected[index] = adjustedSortedPValues[i] <= alpha
+	}
+
+	return adjustment, nil
+}
+
+// AdjustPValuesOfResults returns AdjustPValues for the p-values reported by a family of test results.
+func AdjustPValuesOfResults[T HypothesisTestResult](results []T, method PValueAdjustmentMethod, alpha float64) (*PValueAdjustment, error) {
+	pValues := make([]float64, len(results))
+	for i, result := range results {
+		pValues[i] = result.ReportedPValue()
+	}
+
+	return AdjustPValues(pValues, method, alpha)
+}
+
+func bonferroniAdjustmentOfSortedPValues(sortedPValues []float64) []float64 {
+	n := float64(len(sortedPValues))
+
+	adjusted := make([]float64, len(sortedPValues))
+	for i, p := range sortedPValues {
+		adjusted[i] = math.Min(1, n*p)
+	}
+
+	return adjusted
+}
+
+// holmAdjustmentOfSortedPValues multiplies the ith smallest p-value (counting from 1) by n - i + 1, then makes the
+// results non-decreasing by taking the running maximum from the smallest.
+func holmAdjustmentOfSortedPValues(sortedPValues []float64) []float64 {
+	n := len(sortedPValues)
+
+	adjusted := make([]float64, n)
+	runningMaximum := float64(0)
+	for i, p := range sortedPValues {
+		runningMaximum = math.Max(runningMaximum, float64(n-i)*p)
+		adjusted[i] = math.Min(1, runningMaximum)
+	}
+
+	return adjusted
+}
+
+// stepUpAdjustmentOfSortedPValues multiplies the p-value of each rank (counting from 1 at the smallest) by
+// multiplier(rank), then makes the results non-decreasing by taking the running minimum from the largest.
+func stepUpAdjustmentOfSortedPValues(sortedPValues []float64, multiplier func(rank float64) float64) []float64 {
+	adjusted := make([]float64, len(sortedPValues))
+	runningMinimum := float64(1)
+	for i := len(sortedPValues) - 1; i >= 0; i-- {
+		runningMinimum = math.Min(runningMinimum, multiplier(float64(i+1))*sortedPValues[i])
+		adjusted[i] = runningMinimum
+	}
+
+	return adjusted
+}
+
+// hommelAdjustmentOfSortedPValues follows the algorithm of R's p.adjust(), which for each size m of the
+// intersection hypotheses computes the Simes p-value of each intersection containing each hypothesis, and adjusts
+// each p-value to the largest of these.
+func hommelAdjustmentOfSortedPValues(sortedPValues []float64) []float64 {
+	n := len(sortedPValues)
+
+	simes := math.Inf(1)
+	for i, p := range sortedPValues {
+		simes = math.Min(simes, float64(n)*p/float64(i+1))
+	}
+
+	adjusted := make([]float64, n)
+	q := make([]float64, n)
+	for i := range adjusted {
+		adjusted[i], q[i] = simes, simes
+	}
+
+	for m := n - 1; m >= 2; m-- {
+		// the hypotheses with the m - 1 largest p-values, and the remaining n - m + 1 hypotheses
+		firstOfLargest := n - m + 1
+
+		simesOfLargest := math.Inf(1)
+		for i := firstOfLargest; i < n; i++ {
+			simesOfLargest = math.Min(simesOfLargest, float64(m)*sortedPValues[i]/float64(i-firstOfLargest+2))
+		}
+
+		for i := 0; i < firstOfLargest; i++ {
+			q[i] = math.Min(float64(m)*sortedPValues[i], simesOfLargest)
+		}
+		for i := firstOfLargest; i < n; i++ {
+			q[i] = q[firstOfLargest-1]
+		}
+
+		for i := range adjusted {
+			adjusted[i] = math.Max(adjusted[i], q[i])
+		}
+	}
+
+	for i, p := range sortedPValues {
+		adjusted[i] = math.Max(adjusted[i], p)
+	}
+
+	return adjusted
+}
@@ -0,0 +1,124 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+func TestAdjustPValuesAgainstR(t *testing.T) {
+	// R: p.adjust(p, method)
+	pValues := []float64{0.01, 0.02, 0.025, 0.04, 0.3, 0.005, 0.6, 0.049}
+
+	for testIndex, testCase := range []struct {
+		method            stats.PValueAdjustmentMethod
+		expectedAdjusted  []float64
+		expectedRejection []bool
+	}{
+		{stats.AdjustBonferroni, []float64{0.08, 0.16, 0.2, 0.32, 1, 0.04, 1, 0.392}, []bool{false, false, false, false, false, true, false, false}},
+		{stats.AdjustHolm, []float64{0.07, 0.12, 0.125, 0.16, 0.6, 0.04, 0.6, 0.16}, []bool{false, false, false, false, false, true, false, false}},
+		{stats.AdjustHochberg, []float64{0.07, 0.12, 0.125, 0.147, 0.6, 0.04, 0.6, 0.147}, []bool{false, false, false, false, false, true, false, false}},
+		{stats.AdjustHommel, []float64{0.06, 0.0816667, 0.098, 0.12, 0.6, 0.04, 0.6, 0.147}, []bool{false, false, false, false, false, true, false, false}},
+		{stats.AdjustBenjaminiHochberg, []float64{0.04, 0.05, 0.05, 0.064, 0.3428571, 0.04, 0.6, 0.0653333}, []bool{true, true, true, false, false, true, false, false}},
+		{stats.AdjustBenjaminiYekutieli, []float64{0.1087143, 0.1358929, 0.1358929, 0.1739429, 0.9318367, 0.1087143, 1, 0.1775667}, []bool{false, false, false, false, false, false, false, false}},
+	} {
+		adjustment, err := stats.AdjustPValues(pValues, testCase.method, 0.05)
+		if err != nil {
+			t.Errorf("on test with index (%d) got unexpected error: %s", testIndex, err)
+			continue
+		}
+
+		if adjustment.Method != testCase.method || adjustment.Alpha != 0.05 {
+			t.Errorf("on test with index (%d) expected Method (%s) and Alpha (0.05), got (%s) and (%f)", testIndex, testCase.method, adjustment.Method, adjustment.Alpha)
+		}
+
+		for i := range pValues {
+			if math.Abs(adjustment.AdjustedPValues[i]-testCase.expectedAdjusted[i]) > 1e-7 {
+				t.Errorf("on test with index (%d) (%s) expected adjusted p-value (%d) to be (%f), got (%f)", testIndex, testCase.method, i, testCase.expectedAdjusted[i], adjustment.AdjustedPValues[i])
+			}
+
+			if adjustment.Rejected[i] != testCase.expectedRejection[i] {
+				t.Errorf("on test with index (%d) (%s) expected rejection of (%d) to be (%t), got (%t)", testIndex, testCase.method, i, testCase.expectedRejection[i], adjustment.Rejected[i])
+			}
+		}
+	}
+}
+
+func TestAdjustPValuesOrdering(t *testing.T) {
+	pValues := []float64{0.001, 0.2, 0.012, 0.04, 0.04, 0.9, 0.0301, 0.011, 0.5, 0.003}
+
+	adjusted := map[stats.PValueAdjustmentMethod][]float64{}
+	for _, method := range []stats.PValueAdjustmentMethod{stats.AdjustBonferroni, stats.AdjustHolm, stats.AdjustHochberg, stats.AdjustHommel, stats.AdjustBenjaminiHochberg, stats.AdjustBenjaminiYekutieli} {
+		adjustment, err := stats.AdjustPValues(pValues, method, 0.05)
+		if err != nil {
+			t.Fatalf("on method (%s) got unexpected error: %s", method, err)
+		}
+		adjusted[method] = adjustment.AdjustedPValues
+	}
+
+	// each method is at least as powerful as the one it refines
+	for _, pair := range [][2]stats.PValueAdjustmentMethod{
+		{stats.AdjustHolm, stats.AdjustBonferroni},
+		{stats.AdjustHochberg, stats.AdjustHolm},
+		{stats.AdjustHommel, stats.AdjustHochberg},
+		{stats.AdjustBenjaminiHochberg, stats.AdjustHochberg},
+		{stats.AdjustBenjaminiHochberg, stats.AdjustBenjaminiYekutieli},
+	} {
+		for i, p := range pValues {
+			smaller, larger := adjusted[pair[0]][i], adjusted[pair[1]][i]
+			if smaller > larger+1e-15 || smaller < p {
+				t.Errorf("on p-value (%f) expected (%s) adjustment (%f) to be between it and (%s) adjustment (%f)", p, pair[0], smaller, pair[1], larger)
+			}
+		}
+	}
+
+	single, _ := stats.AdjustPValues([]float64{0.03}, stats.AdjustHommel, 0.05)
+	if single.AdjustedPValues[0] != 0.03 || !single.Rejected[0] {
+		t.Errorf("on a single p-value expected it to be unadjusted and rejected, got (%f) and (%t)", single.AdjustedPValues[0], single.Rejected[0])
+	}
+}
+
+func TestAdjustPValuesOfResults(t *testing.T) {
+	group1, _ := stats.MakeStatisticalSampleSetFrom(sleepGroup1)
+	group2, _ := stats.MakeStatisticalSampleSetFrom(sleepGroup2)
+
+	welch, _ := stats.WelchTTest(group1, group2, stats.AlternativeTwoSided, 0.95)
+	mannWhitney, _ := stats.MannWhitneyUTest(group1, group2, stats.AlternativeTwoSided, 0.95)
+	kolmogorovSmirnov, _ := stats.TwoSampleKolmogorovSmirnovTest(group1, group2, stats.AlternativeTwoSided)
+
+	adjustment, err := stats.AdjustPValuesOfResults([]stats.HypothesisTestResult{welch, mannWhitney, kolmogorovSmirnov}, stats.AdjustBonferroni, 0.05)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	for i, p := range []float64{welch.PValue, mannWhitney.PValue, kolmogorovSmirnov.PValue} {
+		if expected := math.Min(1, 3*p); adjustment.AdjustedPValues[i] != expected {
+			t.Errorf("on result (%d) expected adjusted p-value (%f), got (%f)", i, expected, adjustment.AdjustedPValues[i])
+		}
+	}
+
+	tTests := []*stats.TTestResult{welch, welch}
+	if _, err := stats.AdjustPValuesOfResults(tTests, stats.AdjustHolm, 0.05); err != nil {
+		t.Errorf("on slice of TTestResult got unexpected error: %s", err)
+	}
+}
+
+func TestAdjustPValuesErrors(t *testing.T) {
+	for testIndex, testCase := range []struct {
+		pValues []float64
+		method  stats.PValueAdjustmentMethod
+		alpha   float64
+	}{
+		{[]float64{}, stats.AdjustHolm, 0.05},
+		{[]float64{0.1, 1.2}, stats.AdjustHolm, 0.05},
+		{[]float64{0.1, math.NaN()}, stats.AdjustHolm, 0.05},
+		{[]float64{0.1}, stats.PValueAdjustmentMethod(17), 0.05},
+		{[]float64{0.1}, stats.AdjustHolm, 0},
+		{[]float64{0.1}, stats.AdjustHolm, 1},
+	} {
+		if _, err := stats.AdjustPValues(testCase.pValues, testCase.method, testCase.alpha); err == nil {
+			t.Errorf("on test with index (%d) expected error, got none", testIndex)
+		}
+	}
+}
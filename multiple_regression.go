@@ -0,0 +1,275 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// A RegressionDesign assembles named predictors, numeric or categorical, into the matrix of predictors for
+// MultipleLinearRegression.  Every predictor must have a value for each observation.  The zero value is an empty design.
+type RegressionDesign struct {
+	names   []string
+	columns [][]float64
+}
+
+// AddNumericPredictor adds a predictor whose ith value is values[i].
+func (design *RegressionDesign) AddNumericPredictor(name string, values []float64) error {
+	if err := design.errorIfNumberOfObservationsDiffers(name, len(values)); err != nil {
+		return err
+	}
+
+	column := make([]float64, len(values))
+	copy(column, values)
+
+	design.names = append(design.names, name)
+	design.columns = append(design.columns, column)
+
+	return nil
+}
+
+// AddCategoricalPredictor adds a predictor whose ith value is the category levels[i], using dummy (treatment) encoding:
+// the first level in sorted order is the reference level, and each other level becomes a predictor named
+// "name=level" that is 1 for the observations at that level and 0 otherwise.  The coefficient of each such predictor is
+// the difference between its level and the reference level.  There must be at least two distinct levels.
+func (design *RegressionDesign) AddCategoricalPredictor(name string, levels []string) error {
+	if err := design.errorIfNumberOfObservationsDiffers(name, len(levels)); err != nil {
+		return err
+	}
+
+	observationsAtLevel := make(map[string][]int)
+	for i, level := range levels {
+		observationsAtLevel[level] = append(observationsAtLevel[level], i)
+	}
+
+	if len(observationsAtLevel) < 2 {
+		return fmt.Errorf("categorical predictor (%s) must have at least two levels", name)
+	}
+
+	distinctLevels := make([]string, 0, len(observationsAtLevel))
+	for level := range observationsAtLevel {
+		distinctLevels = append(distinctLevels, level)
+	}
+	sort.Strings(distinctLevels)
+
+	for _, level := range distinctLevels[1:] {
+		column := make([]float64, len(levels))
+		for _, i := range observationsAtLevel[level] {
+			column[i] = 1
+		}
+
+		design.names = append(design.names, name+"="+level)
+		design.columns = append(design.columns, column)
+	}
+
+	return nil
+}
+
+func (design *RegressionDesign) errorIfNumberOfObservationsDiffers(name string, numberOfObservations int) error {
+	if numberOfObservations == 0 {
+		return fmt.Errorf("predictor (%s) has no values", name)
+	}
+
+	if len(design.columns) > 0 && len(design.columns[0]) != numberOfObservations {
+		return fmt.Errorf("predictor (%s) has %d values, but the design has %d observations", name, numberOfObservations, len(design.columns[0]))
+	}
+
+	return nil
+}
+
+// Names returns the name of each column of the matrix of predictors, in order.
+func (design *RegressionDesign) Names() []string {
+	names := make([]string, len(design.names))
+	copy(names, design.names)
+
+	return names
+}
+
+// Matrix returns the matrix of predictors, with a row for each observation and a column for each predictor (or, for
+// a categorical predictor, each level other than the reference level).  It does not include a column for the
+// intercept.  An error is returned if no predictors have been added.
+func (design *RegressionDesign) Matrix() (*Matrix, error) {
+	if len(design.columns) == 0 {
+		return nil, fmt.Errorf("the design has no predictors")
+	}
+
+	return MakeMatrixFromColumns(design.columns)
+}
+
+// A RegressionCoefficient is a row of the coefficient table of a MultipleRegressionResult.  The t statistic and
+// p-value test whether the coefficient differs from zero (two-sided).  VarianceInflationFactor is 1/(1 - R²), where
+// R² is that of regressing the predictor on the other predictors; values above 5 or 10 are commonly taken to mean
+// that the predictor is so correlated with the others that its coefficient is poorly determined.  It is NaN for the
+// intercept.
+type RegressionCoefficient struct {
+	Name                    string
+	Estimate                float64
+	StandardError           float64
+	TStatistic              float64
+	PValue                  float64
+	VarianceInflationFactor float64
+}
+
+// A MultipleRegressionResult is the ordinary least squares fit of y = b0 + b1*x1 + ... + bk*xk.  Coefficients[0] is
+// the intercept, named "(intercept)", and Coefficients[j] is the coefficient of the jth column of the matrix of
+// predictors.  CovarianceMatrix is the estimated covariance of the coefficients, in the same order.  The F statistic
+// tests whether any of b1..bk differs from zero, with FNumeratorDegreesOfFreedom (k) and DegreesOfFreedom (the
+// number of observations less k+1) degrees of freedom.  FittedValues[i] and Residuals[i] are for the ith observation.
+type MultipleRegressionResult struct {
+	Coefficients               []RegressionCoefficient
+	CovarianceMatrix           *Matrix
+	FittedValues               []float64
+	Residuals                  []float64
+	ResidualStandardError      float64
+	DegreesOfFreedom           float64
+	RSquared                   float64
+	AdjustedRSquared           float64
+	FStatistic                 float64
+	FNumeratorDegreesOfFreedom float64
+	FPValue                    float64
+}
+
+// MultipleLinearRegression fits y = b0 + b1*x1 + ... + bk*xk by ordinary least squares, where row i of predictors
+// holds the predictors x1..xk of the ith observation and response[i] is its y.  An intercept is always fitted, so
+// predictors should not include a column of ones.  names, if not nil, names each column of predictors; otherwise the
+// columns are named "x1".."xk".  The least squares problem is solved using the Householder QR decomposition of the
+// predictors, which is more accurate than solving the normal equations when predictors are nearly collinear.  There
+// must be more observations than coefficients, response must not be constant, and no predictor may be constant or
+// a linear combination of the other predictors.
+func MultipleLinearRegression(predictors *Matrix, names []string, response []float64) (*MultipleRegressionResult, error) {
+	n, k := predictors.Rows(), predictors.Columns()
+
+	if len(response) != n {
+		return nil, fmt.Errorf("there must be a response for each row of predictors")
+	}
+
+	if names == nil {
+		names = make([]string, k)
+		for j := range names {
+			names[j] = fmt.Sprintf("x%d", j+1)
+		}
+	} else if len(names) != k {
+		return nil, fmt.Errorf("there must be a name for each column of predictors")
+	}
+
+	if n <= k+1 {
+		return nil, fmt.Errorf("there must be more observations than coefficients")
+	}
+
+	design := &Matrix{rows: n, columns: k + 1, values: make([]float64, n*(k+1))}
+	for i := 0; i < n; i++ {
+		design.values[i*(k+1)] = 1
+		copy(design.values[i*(k+1)+1:(i+1)*(k+1)], predictors.values[i*k:(i+1)*k])
+	}
+
+	decomposition, err := householderQR(design)
+	if err != nil {
+		var dependentColumn *dependentColumnError
+		if errors.As(err, &dependentColumn) && dependentColumn.column > 0 {
+			return nil, fmt.Errorf("predictor (%s) is constant or is a linear combination of the predictors before it", names[dependentColumn.column-1])
+		}
+		return nil, err
+	}
+
+	estimates := decomposition.solveLeastSquares(response)
+
+	meanOfResponse := float64(0)
+	for _, y := range response {
+		meanOfResponse += y
+	}
+	meanOfResponse /= float64(n)
+
+	result := &MultipleRegressionResult{
+		FittedValues:               make([]float64, n),
+		Residuals:                  make([]float64, n),
+		DegreesOfFreedom:           float64(n - k - 1),
+		FNumeratorDegreesOfFreedom: float64(k),
+	}
+
+	sumOfSquaredResiduals, totalSumOfSquares := float64(0), float64(0)
+	for i := 0; i < n; i++ {
+		fitted := float64(0)
+		for j, estimate := range estimates {
+			fitted += design.values[i*(k+1)+j] * estimate
+		}
+
+		result.FittedValues[i] = fitted
+		result.Residuals[i] = response[i] - fitted
+
+		sumOfSquaredResiduals += result.Residuals[i] * result.Residuals[i]
+		totalSumOfSquares += (response[i] - meanOfResponse) * (response[i] - meanOfResponse)
+	}
+
+	if totalSumOfSquares == 0 {
+		return nil, fmt.Errorf("the response is constant")
+	}
+
+	residualVariance := sumOfSquaredResiduals / result.DegreesOfFreedom
+	result.ResidualStandardError = math.Sqrt(residualVariance)
+	result.RSquared = 1 - sumOfSquaredResiduals/totalSumOfSquares
+	result.AdjustedRSquared = 1 - (1-result.RSquared)*float64(n-1)/result.DegreesOfFreedom
+
+	result.FStatistic = ((totalSumOfSquares - sumOfSquaredResiduals) / result.FNumeratorDegreesOfFreedom) / residualVariance
+	result.FPValue = RegularizedIncompleteBeta(result.DegreesOfFreedom/2, result.FNumeratorDegreesOfFreedom/2, result.DegreesOfFreedom/(result.DegreesOfFreedom+result.FNumeratorDegreesOfFreedom*result.FStatistic))
+
+	inverseOfGramMatrix := decomposition.inverseOfGramMatrix()
+	result.CovarianceMatrix = &Matrix{rows: k + 1, columns: k + 1, values: make([]float64, len(inverseOfGramMatrix.values))}
+	for i, v := range inverseOfGramMatrix.values {
+		result.CovarianceMatrix.values[i] = residualVariance * v
+	}
+
+	result.Coefficients = make([]RegressionCoefficient, k+1)
+	for j, estimate := range estimates {
+		coefficient := RegressionCoefficient{
+			Name:                    "(intercept)",
+			Estimate:                estimate,
+			StandardError:           math.Sqrt(result.CovarianceMatrix.values[j*(k+1)+j]),
+			VarianceInflationFactor: math.NaN(),
+		}
+
+		coefficient.TStatistic = estimate / coefficient.StandardError
+		coefficient.PValue = 2 * studentTCDF(-math.Abs(coefficient.TStatistic), result.DegreesOfFreedom)
+
+		if j > 0 {
+			coefficient.Name = names[j-1]
+
+			// the variance of a coefficient is the residual variance divided by the sum of squared deviations of its
+			// predictor and by 1 - R² of the predictor on the others, so the inflation factor can be read from (X'X)^-1
+			sumOfSquaredDeviations := float64(0)
+			meanOfPredictor := float64(0)
+			for i := 0; i < n; i++ {
+				meanOfPredictor += design.values[i*(k+1)+j]
+			}
+			meanOfPredictor /= float64(n)
+			for i := 0; i < n; i++ {
+				deviation := design.values[i*(k+1)+j] - meanOfPredictor
+				sumOfSquaredDeviations += deviation * deviation
+			}
+
+			coefficient.VarianceInflationFactor = inverseOfGramMatrix.values[j*(k+1)+j] * sumOfSquaredDeviations
+		}
+
+		result.Coefficients[j] = coefficient
+	}
+
+	return result, nil
+}
+
+// CoefficientConfidenceIntervals returns the two-sided confidence interval for each coefficient, in the order of
+// Coefficients, based on Student's t-distribution.  The confidence level must be greater than 0 and less than 1.
+func (result *MultipleRegressionResult) CoefficientConfidenceIntervals(level float64) ([]Interval, error) {
+	if err := errorIfConfidenceLevelIsNotValid(level); err != nil {
+		return nil, err
+	}
+
+	criticalValue := studentTQuantile((1+level)/2, result.DegreesOfFreedom)
+
+	intervals := make([]Interval, len(result.Coefficients))
+	for j, coefficient := range result.Coefficients {
+		margin := criticalValue * coefficient.StandardError
+		intervals[j] = Interval{Lower: coefficient.Estimate - margin, Upper: coefficient.Estimate + margin, Level: level, Method: IntervalStudentT}
+	}
+
+	return intervals, nil
+}
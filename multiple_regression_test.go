@@ -0,0 +1,157 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+var latencyRequestSize = []float64{1.2, 2.5, 3.1, 4.8, 5.0, 6.3, 7.7, 8.1, 9.4, 10.0, 11.2, 12.5}
+var latencyConcurrency = []float64{4, 2, 8, 6, 3, 9, 5, 7, 2, 8, 6, 4}
+var latencyRegion = []string{"us", "eu", "us", "ap", "eu", "us", "ap", "eu", "us", "ap", "eu", "ap"}
+var latency = []float64{10.1, 11.5, 16.8, 17.2, 15.1, 22.9, 21.0, 23.4, 20.2, 27.9, 26.1, 25.3}
+
+func TestMultipleLinearRegression(t *testing.T) {
+	var design stats.RegressionDesign
+	design.AddNumericPredictor("size", latencyRequestSize)
+	design.AddNumericPredictor("concurrency", latencyConcurrency)
+	if err := design.AddCategoricalPredictor("region", latencyRegion); err != nil {
+		t.Fatalf("on AddCategoricalPredictor got unexpected error: %s", err)
+	}
+
+	predictors, err := design.Matrix()
+	if err != nil {
+		t.Fatalf("on Matrix got unexpected error: %s", err)
+	}
+
+	fit, err := stats.MultipleLinearRegression(predictors, design.Names(), latency)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	for testIndex, expected := range []stats.RegressionCoefficient{
+		{"(intercept)", 5.1606417191827925, 0.8706344937057239, 5.927449183890363, 0.0005831274727775986, math.NaN()},
+		{"size", 1.3556319633424727, 0.07038797869828782, 19.259424526924914, 2.5351670989406703e-07, 1.263958553138505},
+		{"concurrency", 1.0134919307079253, 0.09833238240643899, 10.30679727171504, 1.7523903109118276e-05, 1.0857422589060535},
+		{"region=eu", 0.22091043823697643, 0.557558807949467, 0.3962101128837303, 0.7037498413241856, 1.48541265073687},
+		{"region=us", -0.2663801374657274, 0.5905221308657079, -0.4510925561336931, 0.6655651698221087, 1.6662421368078768},
+	} {
+		got := fit.Coefficients[testIndex]
+
+		if got.Name != expected.Name {
+			t.Errorf("on test with index (%d) expected Name (%s), got (%s)", testIndex, expected.Name, got.Name)
+		}
+
+		if math.Abs(got.Estimate-expected.Estimate) > 1e-9 || math.Abs(got.StandardError-expected.StandardError) > 1e-9 || math.Abs(got.TStatistic-expected.TStatistic) > 1e-8 {
+			t.Errorf("on test with index (%d) expected Estimate, StandardError and TStatistic (%f, %f, %f), got (%f, %f, %f)", testIndex, expected.Estimate, expected.StandardError, expected.TStatistic, got.Estimate, got.StandardError, got.TStatistic)
+		}
+
+		if math.Abs(got.PValue-expected.PValue) > 1e-6*expected.PValue {
+			t.Errorf("on test with index (%d) expected PValue (%g), got (%g)", testIndex, expected.PValue, got.PValue)
+		}
+
+		if math.IsNaN(expected.VarianceInflationFactor) != math.IsNaN(got.VarianceInflationFactor) || math.Abs(got.VarianceInflationFactor-expected.VarianceInflationFactor) > 1e-9 {
+			t.Errorf("on test with index (%d) expected VarianceInflationFactor (%f), got (%f)", testIndex, expected.VarianceInflationFactor, got.VarianceInflationFactor)
+		}
+	}
+
+	for testIndex, testCase := range []struct {
+		name     string
+		expected float64
+		got      float64
+	}{
+		{"RSquared", 0.9891648102899794, fit.RSquared},
+		{"AdjustedRSquared", 0.9829732733128247, fit.AdjustedRSquared},
+		{"ResidualStandardError", 0.747053126875687, fit.ResidualStandardError},
+		{"DegreesOfFreedom", 7, fit.DegreesOfFreedom},
+		{"FStatistic", 159.7607853978372, fit.FStatistic},
+		{"FNumeratorDegreesOfFreedom", 4, fit.FNumeratorDegreesOfFreedom},
+		{"FPValue", 5.908331620751921e-07, fit.FPValue},
+		{"CovarianceMatrix(0, 1)", -0.038749652897024874, fit.CovarianceMatrix.At(0, 1)},
+		{"CovarianceMatrix(4, 3)", 0.1738579828554639, fit.CovarianceMatrix.At(4, 3)},
+		{"Residuals[0]", -0.474987660559734, fit.Residuals[0]},
+		{"FittedValues[11]", 26.160008983795404, fit.FittedValues[11]},
+	} {
+		if math.Abs(testCase.got-testCase.expected) > 1e-6*math.Abs(testCase.expected) {
+			t.Errorf("on test with index (%d) expected %s (%g), got (%g)", testIndex, testCase.name, testCase.expected, testCase.got)
+		}
+	}
+
+	intervals, _ := fit.CoefficientConfidenceIntervals(0.95)
+	if len(intervals) != 5 || math.Abs((intervals[1].Upper+intervals[1].Lower)/2-fit.Coefficients[1].Estimate) > 1e-12 || intervals[1].Method != stats.IntervalStudentT {
+		t.Errorf("expected Student's t intervals centered on the estimates, got %v", intervals)
+	}
+}
+
+func TestMultipleLinearRegressionOfOnePredictorMatchesLinearRegression(t *testing.T) {
+	predictors, _ := stats.MakeMatrixFromColumns([][]float64{regressionX})
+	fit, err := stats.MultipleLinearRegression(predictors, nil, regressionY)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	set, _ := stats.MakePairedSampleSetFrom(regressionX, regressionY)
+	simple, _ := stats.LinearRegression(set)
+
+	slope, intercept := fit.Coefficients[1], fit.Coefficients[0]
+	if slope.Name != "x1" || math.Abs(slope.VarianceInflationFactor-1) > 1e-12 {
+		t.Errorf("expected Name (x1) and VarianceInflationFactor (1), got (%s) and (%f)", slope.Name, slope.VarianceInflationFactor)
+	}
+
+	for testIndex, testCase := range [][2]float64{
+		{slope.Estimate, simple.Slope},
+		{slope.StandardError, simple.SlopeStandardError},
+		{slope.PValue, simple.SlopePValue},
+		{intercept.Estimate, simple.Intercept},
+		{intercept.StandardError, simple.InterceptStandardError},
+		{fit.RSquared, simple.RSquared},
+		{fit.ResidualStandardError, simple.ResidualStandardError},
+		{fit.FStatistic, simple.SlopeTStatistic * simple.SlopeTStatistic},
+		{fit.FPValue, simple.SlopePValue},
+	} {
+		if math.Abs(testCase[0]-testCase[1]) > 1e-9*math.Abs(testCase[1]) {
+			t.Errorf("on test with index (%d) expected (%g), got (%g)", testIndex, testCase[1], testCase[0])
+		}
+	}
+}
+
+func TestMultipleLinearRegressionErrors(t *testing.T) {
+	var design stats.RegressionDesign
+	if _, err := design.Matrix(); err == nil {
+		t.Errorf("on empty design expected error, got none")
+	}
+
+	design.AddNumericPredictor("size", latencyRequestSize)
+	if err := design.AddNumericPredictor("short", []float64{1, 2}); err == nil {
+		t.Errorf("on predictor with too few values expected error, got none")
+	}
+	if err := design.AddCategoricalPredictor("everywhere", make([]string, len(latency))); err == nil {
+		t.Errorf("on categorical predictor with one level expected error, got none")
+	}
+
+	doubledSize := make([]float64, len(latencyRequestSize))
+	for i, size := range latencyRequestSize {
+		doubledSize[i] = 2 * size
+	}
+	design.AddNumericPredictor("doubled size", doubledSize)
+
+	predictors, _ := design.Matrix()
+	if _, err := stats.MultipleLinearRegression(predictors, design.Names(), latency); err == nil || err.Error() != "predictor (doubled size) is constant or is a linear combination of the predictors before it" {
+		t.Errorf("on collinear predictors expected error naming (doubled size), got (%v)", err)
+	}
+
+	constant, _ := stats.MakeMatrixFromColumns([][]float64{{1, 2, 3, 4}, {5, 5, 5, 5}})
+	if _, err := stats.MultipleLinearRegression(constant, nil, []float64{1, 3, 2, 4}); err == nil {
+		t.Errorf("on constant predictor expected error, got none")
+	}
+
+	if _, err := stats.MultipleLinearRegression(constant, nil, []float64{1, 3, 2}); err == nil {
+		t.Errorf("on too few responses expected error, got none")
+	}
+
+	threeRows, _ := stats.MakeMatrixFromColumns([][]float64{{1, 2, 3}, {2, 1, 5}})
+	if _, err := stats.MultipleLinearRegression(threeRows, nil, []float64{1, 3, 2}); err == nil {
+		t.Errorf("on as many observations as coefficients expected error, got none")
+	}
+}
@@ -0,0 +1,116 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+)
+
+// A PairedSampleSet is a set of (x, y) pairs, such as the size and latency of each request, against which the
+// relationship between the two variables may be computed.  Unlike a StatisticalSampleSet, it retains the order in
+// which the pairs were supplied, and it cannot be appended to after it is created.
+type PairedSampleSet struct {
+	xValues                   []float64
+	yValues                   []float64
+	xSet                      *StatisticalSampleSet
+	ySet                      *StatisticalSampleSet
+	sumOfProductsOfDeviations float64
+}
+
+// MakePairedSampleSetFrom creates a set in which x[i] is paired with y[i].  There must be the same number of x and y
+// values, and at least one pair.
+func MakePairedSampleSetFrom(x []float64, y []float64) (*PairedSampleSet, error) {
+	if len(x) != len(y) {
+		return nil, fmt.Errorf("there must be the same number of x and y values")
+	}
+
+	xSet, err := MakeStatisticalSampleSetFrom(x)
+	if err != nil {
+		return nil, err
+	}
+
+	ySet, err := MakeStatisticalSampleSetFrom(y)
+	if err != nil {
+		return nil, err
+	}
+
+	set := &PairedSampleSet{
+		xValues: make([]float64, len(x)),
+		yValues: make([]float64, len(y)),
+		xSet:    xSet,
+		ySet:    ySet,
+	}
+
+	copy(set.xValues, x)
+	copy(set.yValues, y)
+
+	meanOfX, meanOfY := xSet.Mean(), ySet.Mean()
+	for i := range x {
+		set.sumOfProductsOfDeviations += (x[i] - meanOfX) * (y[i] - meanOfY)
+	}
+
+	return set, nil
+}
+
+// Count returns the number of pairs in the set.
+func (set *PairedSampleSet) Count() int {
+	return len(set.xValues)
+}
+
+// X returns the x values as a new StatisticalSampleSet, from which their marginal statistics may be computed.  Adding
+// values to the returned set does not change this one.
+func (set *PairedSampleSet) X() *StatisticalSampleSet {
+	xSet, _ := MakeStatisticalSampleSetFrom(set.xValues)
+	return xSet
+}
+
+// Y returns the y values as a new StatisticalSampleSet, from which their marginal statistics may be computed.  Adding
+// values to the returned set does not change this one.
+func (set *PairedSampleSet) Y() *StatisticalSampleSet {
+	ySet, _ := MakeStatisticalSampleSetFrom(set.yValues)
+	return ySet
+}
+
+// Pairs returns copies of the x and y values, in the order in which they were supplied.
+func (set *PairedSampleSet) Pairs() (x []float64, y []float64) {
+	x, y = make([]float64, len(set.xValues)), make([]float64, len(set.yValues))
+	copy(x, set.xValues)
+	copy(y, set.yValues)
+
+	return x, y
+}
+
+// SampleCovariance returns the covariance of x and y, treating the set as a sample of a larger population (that is,
+// dividing by one less than the number of pairs).  It is NaN when there is only one pair.
+func (set *PairedSampleSet) SampleCovariance() float64 {
+	return set.sumOfProductsOfDeviations / (float64(len(set.xValues)) - 1)
+}
+
+// PopulationCovariance returns the covariance of x and y, treating the set as the entire population.
+func (set *PairedSampleSet) PopulationCovariance() float64 {
+	return set.sumOfProductsOfDeviations / float64(len(set.xValues))
+}
+
+// sumsOfSquaredDeviations returns the sums of the squared deviations of x and y from their means, and the sum of the
+// products of their deviations, from which the correlation and the least squares line are computed.
+func (set *PairedSampleSet) sumsOfSquaredDeviations() (sxx float64, syy float64, sxy float64) {
+	n := float64(len(set.xValues))
+	return set.xSet.PopulationVariance() * n, set.ySet.PopulationVariance() * n, set.sumOfProductsOfDeviations
+}
+
+// errorIfPairedSampleSetIsTooSmall returns an error if set has fewer than minimumNumberOfPairs pairs, or if either of
+// its variables is constant.
+func errorIfPairedSampleSetIsTooSmall(set *PairedSampleSet, minimumNumberOfPairs int) error {
+	if set.Count() < minimumNumberOfPairs {
+		return fmt.Errorf("there must be at least %d pairs in the set", minimumNumberOfPairs)
+	}
+
+	sxx, syy, _ := set.sumsOfSquaredDeviations()
+	if sxx == 0 || math.IsNaN(sxx) {
+		return fmt.Errorf("the x values are constant")
+	}
+	if syy == 0 || math.IsNaN(syy) {
+		return fmt.Errorf("the y values are constant")
+	}
+
+	return nil
+}
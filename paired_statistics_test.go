@@ -0,0 +1,84 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+func TestMakePairedSampleSetFrom(t *testing.T) {
+	if _, err := stats.MakePairedSampleSetFrom([]float64{1, 2}, []float64{1}); err == nil {
+		t.Errorf("on unequal lengths expected error, got none")
+	}
+
+	if _, err := stats.MakePairedSampleSetFrom([]float64{}, []float64{}); err == nil {
+		t.Errorf("on empty sets expected error, got none")
+	}
+
+	x := []float64{3, 1, 2}
+	y := []float64{30, 10, 20}
+	set, err := stats.MakePairedSampleSetFrom(x, y)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	x[0], y[0] = 100, 100
+
+	pairedX, pairedY := set.Pairs()
+	if set.Count() != 3 || pairedX[0] != 3 || pairedY[0] != 30 || pairedX[1] != 1 || pairedY[2] != 20 {
+		t.Errorf("expected pairs to be copied in the order supplied, got %v and %v", pairedX, pairedY)
+	}
+
+	if set.X().Median() != 2 || set.Y().Mean() != 20 {
+		t.Errorf("expected X median (2) and Y mean (20), got (%f) and (%f)", set.X().Median(), set.Y().Mean())
+	}
+}
+
+func TestAddingToMarginalSetsDoesNotChangePairedSet(t *testing.T) {
+	set, _ := stats.MakePairedSampleSetFrom([]float64{1, 2, 3, 4}, []float64{1, 2, 3, 5})
+
+	before, err := stats.PearsonCorrelation(set, stats.AlternativeTwoSided, 0.95)
+	if err != nil {
+		t.Fatalf("on PearsonCorrelation got unexpected error: %s", err)
+	}
+
+	set.X().Add(100)
+	set.Y().AddMany([]float64{-100, 200})
+
+	after, err := stats.PearsonCorrelation(set, stats.AlternativeTwoSided, 0.95)
+	if err != nil {
+		t.Fatalf("on PearsonCorrelation after adding to X() and Y() got unexpected error: %s", err)
+	}
+
+	if after.Coefficient != before.Coefficient || after.PValue != before.PValue || math.Abs(before.Coefficient-0.9827076298239908) > 1e-12 {
+		t.Errorf("expected Coefficient (0.982708) and PValue (%f) to be unchanged, got (%f) and (%f)", before.PValue, after.Coefficient, after.PValue)
+	}
+
+	if set.X().Count() != 4 || set.Y().Count() != 4 || set.SampleCovariance() != 2.1666666666666665 {
+		t.Errorf("expected X and Y Counts (4) and SampleCovariance (2.166667), got (%d), (%d) and (%f)", set.X().Count(), set.Y().Count(), set.SampleCovariance())
+	}
+}
+
+func TestCovariance(t *testing.T) {
+	set, _ := stats.MakePairedSampleSetFrom(correlationX, correlationY)
+
+	if got := set.SampleCovariance(); math.Abs(got-3.787878787878788) > 1e-12 {
+		t.Errorf("expected SampleCovariance (3.787879), got (%f)", got)
+	}
+
+	if got := set.PopulationCovariance(); math.Abs(got-3.472222222222222) > 1e-12 {
+		t.Errorf("expected PopulationCovariance (3.472222), got (%f)", got)
+	}
+
+	// the covariance of a variable with itself is its variance
+	xWithItself, _ := stats.MakePairedSampleSetFrom(correlationX, correlationX)
+	if math.Abs(xWithItself.SampleCovariance()-xWithItself.X().SampleVariance()) > 1e-12 || math.Abs(xWithItself.PopulationCovariance()-xWithItself.X().PopulationVariance()) > 1e-12 {
+		t.Errorf("expected covariances (%f, %f) to equal the variances of x (%f, %f)", xWithItself.SampleCovariance(), xWithItself.PopulationCovariance(), xWithItself.X().SampleVariance(), xWithItself.X().PopulationVariance())
+	}
+
+	single, _ := stats.MakePairedSampleSetFrom([]float64{1}, []float64{2})
+	if !math.IsNaN(single.SampleCovariance()) || single.PopulationCovariance() != 0 {
+		t.Errorf("on a single pair expected SampleCovariance (NaN) and PopulationCovariance (0), got (%f) and (%f)", single.SampleCovariance(), single.PopulationCovariance())
+	}
+}
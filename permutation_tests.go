@@ -0,0 +1,287 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+const (
+	defaultNumberOfPermutationTestIterations  = 10000
+	defaultMaximumPermutationsForExactTest    = 100000
+	permutationsBetweenCancellationChecks     = 1024
+	relativeToleranceOfPermutationComparisons = 1e-12
+)
+
+// A TwoSampleStatistic computes a statistic comparing two sets (e.g., the difference between their 95th percentiles).
+type TwoSampleStatistic func(a *StatisticalSampleSet, b *StatisticalSampleSet) float64
+
+// PermutationTestOptions control PermutationTest.  The test is exact when the number of ways of dividing the values
+// between the sets is at most MaximumPermutationsForExactTest (100000 if zero; a negative value never uses the exact
+// test).  Otherwise, Iterations random permutations (10000 if zero) are drawn using a random number generator seeded
+// with Seed, on up to Parallelism goroutines.  Results depend only on Seed, not on Parallelism.
+type PermutationTestOptions struct {
+	Iterations                      int
+	Seed                            int64
+	Parallelism                     int
+	MaximumPermutationsForExactTest int
+}
+
+// A PermutationTestResult is the result of PermutationTest.  Statistic is the statistic computed on the original
+// sets.  When Exact is false, PValue is estimated from NumberOfPermutations random permutations, and
+// PValueStandardError is the Monte-Carlo standard error of that estimate; when Exact is true, NumberOfPermutations
+// is the number of ways of dividing the values between the sets and PValueStandardError is 0.
+type PermutationTestResult struct {
+	Statistic            float64
+	PValue               float64
+	PValueStandardError  float64
+	Alternative          HypothesisAlternative
+	Exact                bool
+	NumberOfPermutations int
+}
+
+// PermutationTest tests whether the sets are drawn from the same population, without any assumption about that
+// population, by comparing statistic(a, b) with its value when the pooled values are divided between the sets at
+// random.  AlternativeGreater is the hypothesis that statistic(a, b) is larger than it would be if the values were
+// exchangeable, and AlternativeLess that it is smaller.  The two-sided p-value is twice the smaller of the one-sided
+// p-values (capped at 1), which does not require the statistic to be symmetric.
+//
+// A Monte-Carlo p-value is (count + 1) / (iterations + 1), where count is the number of permutations for which the
+// statistic is at least as extreme as observed, so that it is never 0 and the test never rejects more often than its
+// nominal level.  Values of the statistic that differ by a relative amount of less than 1e-12 are treated as equal.
+//
+// The statistic must not modify the sets passed to it, and must be safe to call from concurrent goroutines when
+// options.Parallelism is greater than 1.  An error is returned if the statistic is NaN for any permutation, or if ctx
+// is cancelled before the test completes.
+func PermutationTest(ctx context.Context, a *StatisticalSampleSet, b *StatisticalSampleSet, statistic TwoSampleStatistic, alternative HypothesisAlternative, options PermutationTestOptions) (*PermutationTestResult, error) {
+	if err := errorIfAlternativeIsNotValid(alternative); err != nil {
+		return nil, err
+	}
+
+	if statistic == nil {
+		return nil, fmt.Errorf("statistic must not be nil")
+	}
+
+	if options.Iterations < 0 {
+		return nil, fmt.Errorf("number of iterations must not be negative")
+	}
+
+	if options.Iterations == 0 {
+		options.Iterations = defaultNumberOfPermutationTestIterations
+	}
+
+	if options.MaximumPermutationsForExactTest == 0 {
+		options.MaximumPermutationsForExactTest = defaultMaximumPermutationsForExactTest
+	}
+
+	observed := statistic(a, b)
+	if math.IsNaN(observed) {
+		return nil, fmt.Errorf("statistic is NaN for the sets")
+	}
+
+	sortedA, sortedB := a.snapshotOfSortedValues(), b.snapshotOfSortedValues()
+	pooled := mergeSortedValues(sortedA, sortedB)
+	sizeOfA := len(sortedA)
+
+	numberOfPermutations := math.Round(math.Exp(logBinomialCoefficient(float64(len(pooled)), float64(sizeOfA))))
+
+	var counts *permutationCounts
+	var err error
+
+	exact := numberOfPermutations <= float64(options.MaximumPermutationsForExactTest)
+	if exact {
+		counts, err = countExactPermutations(ctx, pooled, sizeOfA, statistic, observed)
+	} else {
+		counts, err = countRandomPermutations(ctx, pooled, sizeOfA, statistic, observed, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PermutationTestResult{
+		Statistic:            observed,
+		Alternative:          alternative,
+		Exact:                exact,
+		NumberOfPermutations: counts.total,
+	}
+
+	var pLess, pGreater float64
+	if exact {
+		pLess = float64(counts.atMostObserved) / float64(counts.total)
+		pGreater = float64(counts.atLeastObserved) / float64(counts.total)
+	} else {
+		pLess = float64(counts.atMostObserved+1) / float64(counts.total+1)
+		pGreater = float64(counts.atLeastObserved+1) / float64(counts.total+1)
+	}
+
+	switch alternative {
+	case AlternativeLess:
+		result.PValue = pLess
+	case AlternativeGreater:
+		result.PValue = pGreater
+	default:
+		result.PValue = math.Min(1, 2*math.Min(pLess, pGreater))
+	}
+
+	if !exact {
+		oneSidedPValue := result.PValue
+		if alternative == AlternativeTwoSided {
+			oneSidedPValue = math.Min(pLess, pGreater)
+		}
+
+		result.PValueStandardError = math.Sqrt(oneSidedPValue * (1 - oneSidedPValue) / float64(counts.total))
+		if alternative == AlternativeTwoSided {
+			result.PValueStandardError *= 2
+		}
+	}
+
+	return result, nil
+}
+
+// permutationCounts are the number of permutations for which the statistic was at most and at least the observed
+// value, out of a total number of permutations.
+type permutationCounts struct {
+	atMostObserved  int
+	atLeastObserved int
+	total           int
+}
+
+func (counts *permutationCounts) add(value float64, observed float64) {
+	tolerance := relativeToleranceOfPermutationComparisons * math.Max(1, math.Abs(observed))
+
+	if value <= observed+tolerance {
+		counts.atMostObserved++
+	}
+	if value >= observed-tolerance {
+		counts.atLeastObserved++
+	}
+	counts.total++
+}
+
+// countExactPermutations computes the statistic for every way of choosing sizeOfA of the pooled values for the first
+// set, in lexicographic order of the indices chosen.
+func countExactPermutations(ctx context.Context, pooled []float64, sizeOfA int, statistic TwoSampleStatistic, observed float64) (*permutationCounts, error) {
+	counts := &permutationCounts{}
+
+	chosenIndices := make([]int, sizeOfA)
+	for i := range chosenIndices {
+		chosenIndices[i] = i
+	}
+
+	isInA := make([]bool, len(pooled))
+
+	for {
+		if counts.total%permutationsBetweenCancellationChecks == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		for i := range isInA {
+			isInA[i] = false
+		}
+		for _, index := range chosenIndices {
+			isInA[index] = true
+		}
+
+		value, err := statisticOfDividedValues(pooled, isInA, sizeOfA, statistic)
+		if err != nil {
+			return nil, err
+		}
+		counts.add(value, observed)
+
+		// advance to the next combination: increment the rightmost index that can be, and reset those after it
+		i := sizeOfA - 1
+		for i >= 0 && chosenIndices[i] == len(pooled)-sizeOfA+i {
+			i--
+		}
+		if i < 0 {
+			return counts, nil
+		}
+
+		chosenIndices[i]++
+		for j := i + 1; j < sizeOfA; j++ {
+			chosenIndices[j] = chosenIndices[j-1] + 1
+		}
+	}
+}
+
+// countRandomPermutations computes the statistic for options.Iterations random divisions of the pooled values.
+func countRandomPermutations(ctx context.Context, pooled []float64, sizeOfA int, statistic TwoSampleStatistic, observed float64, options PermutationTestOptions) (*permutationCounts, error) {
+	values := make([]float64, options.Iterations)
+
+	err := forEachItemInRandomBlocks(ctx, options.Iterations, options.Parallelism, options.Seed, func(rng *rand.Rand, index int) error {
+		isInA := make([]bool, len(pooled))
+		for _, i := range rng.Perm(len(pooled))[:sizeOfA] {
+			isInA[i] = true
+		}
+
+		var err error
+		values[index], err = statisticOfDividedValues(pooled, isInA, sizeOfA, statistic)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := &permutationCounts{}
+	for _, value := range values {
+		counts.add(value, observed)
+	}
+
+	return counts, nil
+}
+
+// statisticOfDividedValues computes the statistic on the sets formed by dividing the sorted pooled values according
+// to isInA.  Both sets remain sorted.
+func statisticOfDividedValues(pooled []float64, isInA []bool, sizeOfA int, statistic TwoSampleStatistic) (float64, error) {
+	valuesOfA := make([]float64, 0, sizeOfA)
+	valuesOfB := make([]float64, 0, len(pooled)-sizeOfA)
+
+	for i, value := range pooled {
+		if isInA[i] {
+			valuesOfA = append(valuesOfA, value)
+		} else {
+			valuesOfB = append(valuesOfB, value)
+		}
+	}
+
+	a, err := MakeStatisticalSampleSetFrom(valuesOfA)
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := MakeStatisticalSampleSetFrom(valuesOfB)
+	if err != nil {
+		return 0, err
+	}
+
+	value := statistic(a, b)
+	if math.IsNaN(value) {
+		return 0, fmt.Errorf("statistic is NaN for a permutation")
+	}
+
+	return value, nil
+}
+
+// mergeSortedValues returns the values of two sorted slices in a single sorted slice.
+func mergeSortedValues(sortedA []float64, sortedB []float64) []float64 {
+	merged := make([]float64, 0, len(sortedA)+len(sortedB))
+
+	i, j := 0, 0
+	for i < len(sortedA) && j < len(sortedB) {
+		if sortedA[i] <= sortedB[j] {
+			merged = append(merged, sortedA[i])
+			i++
+		} else {
+			merged = append(merged, sortedB[j])
+			j++
+		}
+	}
+
+	merged = append(merged, sortedA[i:]...)
+
+	return append(merged, sortedB[j:]...)
+}
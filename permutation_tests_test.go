@@ -0,0 +1,163 @@
+package stats_test
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+func differenceOfMeans(a *stats.StatisticalSampleSet, b *stats.StatisticalSampleSet) float64 {
+	return a.Mean() - b.Mean()
+}
+
+func TestExactPermutationTest(t *testing.T) {
+	a, _ := stats.MakeStatisticalSampleSetFrom([]float64{1, 2, 3})
+	b, _ := stats.MakeStatisticalSampleSetFrom([]float64{4, 5, 6})
+
+	// of the 20 ways of choosing three of the six values for the first set, only {1, 2, 3} gives a difference in means
+	// as small as observed
+	for testIndex, testCase := range []struct {
+		a, b           *stats.StatisticalSampleSet
+		alternative    stats.HypothesisAlternative
+		expectedPValue float64
+	}{
+		{a, b, stats.AlternativeLess, 0.05},
+		{a, b, stats.AlternativeGreater, 1},
+		{a, b, stats.AlternativeTwoSided, 0.1},
+		{b, a, stats.AlternativeGreater, 0.05},
+	} {
+		result, err := stats.PermutationTest(context.Background(), testCase.a, testCase.b, differenceOfMeans, testCase.alternative, stats.PermutationTestOptions{})
+		if err != nil {
+			t.Errorf("on test with index (%d) got unexpected error: %s", testIndex, err)
+			continue
+		}
+
+		if !result.Exact || result.NumberOfPermutations != 20 || result.PValueStandardError != 0 {
+			t.Errorf("on test with index (%d) expected exact test of (20) permutations, got Exact (%t) of (%d) permutations with PValueStandardError (%f)", testIndex, result.Exact, result.NumberOfPermutations, result.PValueStandardError)
+		}
+
+		if math.Abs(result.PValue-testCase.expectedPValue) > 1e-12 {
+			t.Errorf("on test with index (%d) expected PValue (%f), got (%f)", testIndex, testCase.expectedPValue, result.PValue)
+		}
+
+		if result.Alternative != testCase.alternative {
+			t.Errorf("on test with index (%d) expected Alternative (%s), got (%s)", testIndex, testCase.alternative, result.Alternative)
+		}
+	}
+
+	// {1, 2, 3} and {1, 2, 4} give a difference in means at most that of {1, 2, 4}
+	c, _ := stats.MakeStatisticalSampleSetFrom([]float64{1, 2, 4})
+	result, _ := stats.PermutationTest(context.Background(), c, b, differenceOfMeans, stats.AlternativeLess, stats.PermutationTestOptions{})
+	if result.PValue != 0.1 || result.Statistic != -8.0/3 {
+		t.Errorf("on tied permutation values expected Statistic (%f) and PValue (0.1), got (%f) and (%f)", -8.0/3, result.Statistic, result.PValue)
+	}
+}
+
+func TestMonteCarloPermutationTest(t *testing.T) {
+	normal, _ := stats.NewNormalDistribution(0, 1)
+	shifted, _ := stats.NewNormalDistribution(0.5, 1)
+	a, _ := stats.MakeStatisticalSampleSetFromDistribution(normal, 40, rand.New(rand.NewSource(21)))
+	b, _ := stats.MakeStatisticalSampleSetFromDistribution(shifted, 40, rand.New(rand.NewSource(22)))
+
+	tTest, _ := stats.StudentTTest(a, b, stats.AlternativeTwoSided, 0.95)
+
+	run := func(parallelism int, seed int64) *stats.PermutationTestResult {
+		result, err := stats.PermutationTest(context.Background(), a, b, differenceOfMeans, stats.AlternativeTwoSided, stats.PermutationTestOptions{
+			Iterations:  20000,
+			Seed:        seed,
+			Parallelism: parallelism,
+		})
+		if err != nil {
+			t.Fatalf("got unexpected error: %s", err)
+		}
+		return result
+	}
+
+	sequential, parallel, otherSeed := run(1, 4), run(6, 4), run(6, 5)
+
+	if sequential.Exact || sequential.NumberOfPermutations != 20000 {
+		t.Errorf("expected Monte-Carlo test of (20000) permutations, got Exact (%t) of (%d) permutations", sequential.Exact, sequential.NumberOfPermutations)
+	}
+
+	if *sequential != *parallel {
+		t.Errorf("expected result with Parallelism (6) to match sequential result %+v, got %+v", *sequential, *parallel)
+	}
+
+	if sequential.PValue == otherSeed.PValue {
+		t.Errorf("expected a different seed to produce a different PValue")
+	}
+
+	// the permutation distribution of the difference in means is close to the t distribution for sets of this size
+	if math.Abs(sequential.PValue-tTest.PValue) > 0.005+4*sequential.PValueStandardError {
+		t.Errorf("expected PValue near t-test PValue (%f), got (%f) with standard error (%f)", tTest.PValue, sequential.PValue, sequential.PValueStandardError)
+	}
+
+	if expected := 2 * math.Sqrt(sequential.PValue/2*(1-sequential.PValue/2)/20000); math.Abs(sequential.PValueStandardError-expected) > 1e-6 {
+		t.Errorf("expected PValueStandardError (%f), got (%f)", expected, sequential.PValueStandardError)
+	}
+}
+
+func TestMonteCarloPermutationTestAgreesWithExactTest(t *testing.T) {
+	a, _ := stats.MakeStatisticalSampleSetFrom([]float64{3.1, 4.7, 2.2, 5.0, 3.9, 4.1, 2.8})
+	b, _ := stats.MakeStatisticalSampleSetFrom([]float64{4.4, 5.9, 6.1, 3.3, 5.2, 6.8})
+	median := func(a *stats.StatisticalSampleSet, b *stats.StatisticalSampleSet) float64 {
+		return a.Median() - b.Median()
+	}
+
+	exact, err := stats.PermutationTest(context.Background(), a, b, median, stats.AlternativeLess, stats.PermutationTestOptions{})
+	if err != nil {
+		t.Fatalf("on exact test got unexpected error: %s", err)
+	}
+
+	monteCarlo, err := stats.PermutationTest(context.Background(), a, b, median, stats.AlternativeLess, stats.PermutationTestOptions{
+		Iterations:                      10000,
+		Seed:                            9,
+		Parallelism:                     3,
+		MaximumPermutationsForExactTest: -1,
+	})
+	if err != nil {
+		t.Fatalf("on Monte-Carlo test got unexpected error: %s", err)
+	}
+
+	if !exact.Exact || exact.NumberOfPermutations != 1716 || monteCarlo.Exact {
+		t.Errorf("expected exact test of (1716) permutations and Monte-Carlo test, got Exact (%t) of (%d) permutations and Exact (%t)", exact.Exact, exact.NumberOfPermutations, monteCarlo.Exact)
+	}
+
+	if math.Abs(exact.PValue-monteCarlo.PValue) > 4*monteCarlo.PValueStandardError {
+		t.Errorf("expected Monte-Carlo PValue near exact PValue (%f), got (%f) with standard error (%f)", exact.PValue, monteCarlo.PValue, monteCarlo.PValueStandardError)
+	}
+}
+
+func TestPermutationTestErrors(t *testing.T) {
+	a, _ := stats.MakeStatisticalSampleSetFrom([]float64{1, 2, 3})
+	b, _ := stats.MakeStatisticalSampleSetFrom([]float64{4, 5, 6})
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for testIndex, testCase := range []struct {
+		ctx         context.Context
+		statistic   stats.TwoSampleStatistic
+		alternative stats.HypothesisAlternative
+		options     stats.PermutationTestOptions
+	}{
+		{context.Background(), nil, stats.AlternativeTwoSided, stats.PermutationTestOptions{}},
+		{context.Background(), differenceOfMeans, stats.HypothesisAlternative(7), stats.PermutationTestOptions{}},
+		{context.Background(), differenceOfMeans, stats.AlternativeTwoSided, stats.PermutationTestOptions{Iterations: -1}},
+		{context.Background(), func(a *stats.StatisticalSampleSet, b *stats.StatisticalSampleSet) float64 {
+			if a.Minimum() == 1 {
+				return 0
+			}
+			return math.NaN()
+		}, stats.AlternativeTwoSided, stats.PermutationTestOptions{}},
+		{cancelled, differenceOfMeans, stats.AlternativeTwoSided, stats.PermutationTestOptions{}},
+		{cancelled, differenceOfMeans, stats.AlternativeTwoSided, stats.PermutationTestOptions{MaximumPermutationsForExactTest: -1}},
+	} {
+		if _, err := stats.PermutationTest(testCase.ctx, a, b, testCase.statistic, testCase.alternative, testCase.options); err == nil {
+			t.Errorf("on test with index (%d) expected error, got none", testIndex)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+)
+
+// A QuantileMethod selects one of the nine sample quantile definitions described by Hyndman and Fan in
+// "Sample Quantiles in Statistical Packages" (1996).  The numbering matches the type argument of R's quantile().
+type QuantileMethod int
+
+const (
+	// Inverse of the empirical distribution function.
+	QuantileType1 QuantileMethod = iota + 1
+	// Inverse of the empirical distribution function, averaging at discontinuities.
+	QuantileType2
+	// Nearest even order statistic (SAS definition 2).
+	QuantileType3
+	// Linear interpolation of the empirical distribution function.
+	QuantileType4
+	// Piecewise linear function where the knots are the midpoints of the empirical distribution function steps.
+	QuantileType5
+	// Linear interpolation of the expectations of the order statistics for the uniform distribution (Minitab, SPSS).
+	QuantileType6
+	// Linear interpolation of the modes of the order statistics for the uniform distribution (R and numpy default).
+	QuantileType7
+	// Linear interpolation of the approximate medians of the order statistics; median-unbiased regardless of the distribution.
+	QuantileType8
+	// Linear interpolation of the approximate expected order statistics; approximately unbiased for normal distributions.
+	QuantileType9
+)
+
+const (
+	QuantileNearestRank    = QuantileType1
+	QuantileExcelExclusive = QuantileType6
+	QuantileExcelInclusive = QuantileType7
+	QuantileDefault        = QuantileType7
+)
+
+func (method QuantileMethod) String() string {
+	if method < QuantileType1 || method > QuantileType9 {
+		return fmt.Sprintf("QuantileMethod(%d)", int(method))
+	}
+
+	return fmt.Sprintf("QuantileType%d", int(method))
+}
+
+// QuantileWithErrors returns the value at quantile q (in the range 0..1) using the supplied method.  An error is
+// returned if q is outside of the range 0..1 or the method is not one of the nine defined methods.
+func (set *StatisticalSampleSet) QuantileWithErrors(q float64, method QuantileMethod) (float64, error) {
+	if err := errorIfQuantileMethodIsNotValid(method); err != nil {
+		return 0, err
+	}
+
+	if err := errorIfQuantileIsNotValid(q); err != nil {
+		return 0, err
+	}
+
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	return quantileOfSortedValues(set.sortedValues(), q, method), nil
+}
+
+// Quantile is the same as QuantileWithErrors, except that it panics on an error.
+func (set *StatisticalSampleSet) Quantile(q float64, method QuantileMethod) float64 {
+	f, err := set.QuantileWithErrors(q, method)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return f
+}
+
+// QuantilesWithErrors returns the value at each quantile in qs using the supplied method.  The set is sorted
+// (if necessary) only once for all of the quantiles.
+func (set *StatisticalSampleSet) QuantilesWithErrors(qs []float64, method QuantileMethod) ([]float64, error) {
+	if err := errorIfQuantileMethodIsNotValid(method); err != nil {
+		return nil, err
+	}
+
+	for _, q := range qs {
+		if err := errorIfQuantileIsNotValid(q); err != nil {
+			return nil, err
+		}
+	}
+
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	sortedValues := set.sortedValues()
+
+	values := make([]float64, len(qs))
+	for i, q := range qs {
+		values[i] = quantileOfSortedValues(sortedValues, q, method)
+	}
+
+	return values, nil
+}
+
+// Quantiles is the same as QuantilesWithErrors, except that it panics on an error.
+func (set *StatisticalSampleSet) Quantiles(qs []float64, method QuantileMethod) []float64 {
+	values, err := set.QuantilesWithErrors(qs, method)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return values
+}
+
+func errorIfQuantileMethodIsNotValid(method QuantileMethod) error {
+	if method < QuantileType1 || method > QuantileType9 {
+		return fmt.Errorf("quantile method must be one of QuantileType1 .. QuantileType9")
+	}
+
+	return nil
+}
+
+func errorIfQuantileIsNotValid(q float64) error {
+	if !(q >= 0 && q <= 1) {
+		return fmt.Errorf("quantile must be in the range 0..1")
+	}
+
+	return nil
+}
+
+// quantileInterpolationParameters are the (alpha, beta) plotting position parameters for the
+// continuous methods, types 4 through 9.
+var quantileInterpolationParameters = map[QuantileMethod][2]float64{
+	QuantileType4: {0, 1},
+	QuantileType5: {0.5, 0.5},
+	QuantileType6: {0, 0},
+	QuantileType7: {1, 1},
+	QuantileType8: {1.0 / 3.0, 1.0 / 3.0},
+	QuantileType9: {3.0 / 8.0, 3.0 / 8.0},
+}
+
+// quantileOfSortedValues follows the implementation of R's quantile.default().  sortedValues must be non-empty.
+func quantileOfSortedValues(sortedValues []float64, q float64, method QuantileMethod) float64 {
+	n := float64(len(sortedValues))
+	fuzz := 4 * 2.220446049250313e-16
+
+	var j, h float64
+
+	if method <= QuantileType3 {
+		nppm := n * q
+		if method == QuantileType3 {
+			nppm = n*q - 0.5
+		}
+
+		j = math.Floor(nppm + fuzz)
+
+		switch method {
+		case QuantileType1:
+			if nppm > j {
+				h = 1
+			}
+		case QuantileType2:
+			if nppm > j {
+				h = 1
+			} else {
+				h = 0.5
+			}
+		case QuantileType3:
+			if nppm != j || math.Mod(j, 2) != 0 {
+				h = 1
+			}
+		}
+	} else {
+		parameters := quantileInterpolationParameters[method]
+		alpha, beta := parameters[0], parameters[1]
+
+		nppm := alpha + q*(n+1-alpha-beta)
+		j = math.Floor(nppm + fuzz)
+
+		h = nppm - j
+		if math.Abs(h) < fuzz {
+			h = 0
+		}
+	}
+
+	lower := orderStatisticClampedToSet(sortedValues, int(j))
+	upper := orderStatisticClampedToSet(sortedValues, int(j)+1)
+
+	switch {
+	case h <= 0:
+		return lower
+	case h >= 1:
+		return upper
+	default:
+		return (1-h)*lower + h*upper
+	}
+}
+
+// orderStatisticClampedToSet returns the kth order statistic (counting from 1), where k less than 1 yields
+// the smallest value and k greater than the number of values yields the largest value.
+func orderStatisticClampedToSet(sortedValues []float64, k int) float64 {
+	if k < 1 {
+		return sortedValues[0]
+	}
+
+	if k > len(sortedValues) {
+		return sortedValues[len(sortedValues)-1]
+	}
+
+	return sortedValues[k-1]
+}
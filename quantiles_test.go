@@ -0,0 +1,146 @@
+package stats_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+type quantileTestCase struct {
+	floatSet                    []float64
+	method                      stats.QuantileMethod
+	quantiles                   []float64
+	expectedValueAtEachQuantile []float64
+}
+
+func (testCase *quantileTestCase) RunTest() error {
+	s, err := stats.MakeStatisticalSampleSetFrom(testCase.floatSet)
+	if err != nil {
+		return fmt.Errorf("on MakeStatisticalSampleSetFrom() got error: %s", err.Error())
+	}
+
+	for i, q := range testCase.quantiles {
+		v, err := s.QuantileWithErrors(q, testCase.method)
+		if err != nil {
+			return fmt.Errorf("for quantile (%f) with method (%s) got error: %s", q, testCase.method, err.Error())
+		}
+
+		if math.Abs(v-testCase.expectedValueAtEachQuantile[i]) > 1e-9 {
+			return fmt.Errorf("for quantile (%f) with method (%s) expected (%f), got (%f)", q, testCase.method, testCase.expectedValueAtEachQuantile[i], v)
+		}
+	}
+
+	values, err := s.QuantilesWithErrors(testCase.quantiles, testCase.method)
+	if err != nil {
+		return fmt.Errorf("on QuantilesWithErrors() with method (%s) got error: %s", testCase.method, err.Error())
+	}
+
+	for i, v := range values {
+		if math.Abs(v-testCase.expectedValueAtEachQuantile[i]) > 1e-9 {
+			return fmt.Errorf("on QuantilesWithErrors() for quantile (%f) with method (%s) expected (%f), got (%f)", testCase.quantiles[i], testCase.method, testCase.expectedValueAtEachQuantile[i], v)
+		}
+	}
+
+	return nil
+}
+
+func TestQuantile(t *testing.T) {
+	oneThroughTen := []float64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}
+	quantiles := []float64{0, 0.1, 0.5, 0.99, 1}
+
+	for testIndex, testCase := range []*quantileTestCase{
+		{
+			floatSet:                    oneThroughTen,
+			method:                      stats.QuantileType1,
+			quantiles:                   quantiles,
+			expectedValueAtEachQuantile: []float64{1, 1, 5, 10, 10},
+		},
+		{
+			floatSet:                    oneThroughTen,
+			method:                      stats.QuantileType2,
+			quantiles:                   quantiles,
+			expectedValueAtEachQuantile: []float64{1, 1.5, 5.5, 10, 10},
+		},
+		{
+			floatSet:                    oneThroughTen,
+			method:                      stats.QuantileType3,
+			quantiles:                   quantiles,
+			expectedValueAtEachQuantile: []float64{1, 1, 5, 10, 10},
+		},
+		{
+			floatSet:                    oneThroughTen,
+			method:                      stats.QuantileType4,
+			quantiles:                   quantiles,
+			expectedValueAtEachQuantile: []float64{1, 1, 5, 9.9, 10},
+		},
+		{
+			floatSet:                    oneThroughTen,
+			method:                      stats.QuantileType5,
+			quantiles:                   quantiles,
+			expectedValueAtEachQuantile: []float64{1, 1.5, 5.5, 10, 10},
+		},
+		{
+			floatSet:                    oneThroughTen,
+			method:                      stats.QuantileType6,
+			quantiles:                   quantiles,
+			expectedValueAtEachQuantile: []float64{1, 1.1, 5.5, 10, 10},
+		},
+		{
+			floatSet:                    oneThroughTen,
+			method:                      stats.QuantileType7,
+			quantiles:                   quantiles,
+			expectedValueAtEachQuantile: []float64{1, 1.9, 5.5, 9.91, 10},
+		},
+		{
+			floatSet:                    oneThroughTen,
+			method:                      stats.QuantileType8,
+			quantiles:                   quantiles,
+			expectedValueAtEachQuantile: []float64{1, 1.0/3.0 + 0.1*(11-2.0/3.0), 5.5, 10, 10},
+		},
+		{
+			floatSet:                    oneThroughTen,
+			method:                      stats.QuantileType9,
+			quantiles:                   quantiles,
+			expectedValueAtEachQuantile: []float64{1, 1.4, 5.5, 10, 10},
+		},
+		{
+			floatSet:                    []float64{3, 1, 4, 1, 5, 9, 2, 6},
+			method:                      stats.QuantileDefault,
+			quantiles:                   []float64{0.25, 0.75, 0.999},
+			expectedValueAtEachQuantile: []float64{1.75, 5.25, 8.979},
+		},
+		{
+			floatSet:                    []float64{42},
+			method:                      stats.QuantileType7,
+			quantiles:                   []float64{0, 0.5, 0.9999},
+			expectedValueAtEachQuantile: []float64{42, 42, 42},
+		},
+	} {
+		if err := testCase.RunTest(); err != nil {
+			t.Errorf("on test with index (%d): %s", testIndex, err.Error())
+		}
+	}
+}
+
+func TestQuantileErrors(t *testing.T) {
+	s, err := stats.MakeStatisticalSampleSetFrom([]float64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("on MakeStatisticalSampleSetFrom() got error: %s", err.Error())
+	}
+
+	for _, q := range []float64{-0.1, 1.1, math.NaN()} {
+		if _, err := s.QuantileWithErrors(q, stats.QuantileDefault); err == nil {
+			t.Errorf("on QuantileWithErrors() with quantile (%f), expected error, got none", q)
+		}
+	}
+
+	if _, err := s.QuantileWithErrors(0.5, stats.QuantileMethod(10)); err == nil {
+		t.Errorf("on QuantileWithErrors() with invalid method, expected error, got none")
+	}
+
+	if _, err := s.QuantilesWithErrors([]float64{0.5, 2}, stats.QuantileDefault); err == nil {
+		t.Errorf("on QuantilesWithErrors() with one invalid quantile, expected error, got none")
+	}
+}
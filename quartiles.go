@@ -0,0 +1,124 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+)
+
+// A QuartileMethod selects the convention used to compute the first and third quartiles of a set.  The conventions
+// are those compared by Langford in "Quartiles in Elementary Statistics" (2006).
+type QuartileMethod int
+
+const (
+	// The median of the values below the median and the median of the values above the median, excluding the
+	// median itself when there is an odd number of values (TI-83 calculators).  This is the method used by
+	// InterQuartileRange().
+	QuartileMooreMcCabe QuartileMethod = iota
+	// The median of the lower half and the median of the upper half of the values, including the median in both
+	// halves when there is an odd number of values.
+	QuartileTukeyHinges
+	// The values at positions (n+1)/4 and 3(n+1)/4 in the ordered set, where the first position is rounded up and
+	// the second position is rounded down when they lie halfway between two positions.
+	QuartileMendenhallSincich
+	// Linear interpolation at positions (n+3)/4 and (3n+1)/4.  This yields the same quartiles as
+	// QuartileInterpolatedType7.
+	QuartileFreundPerles
+	// Linear interpolation at positions (n+1)/4 and 3(n+1)/4 (QuantileType6).  This is also Excel's QUARTILE.EXC.
+	QuartileMinitab
+	// The 0.25 and 0.75 quantiles computed using QuantileType7.  This is also Excel's QUARTILE.INC.
+	QuartileInterpolatedType7
+)
+
+func (method QuartileMethod) String() string {
+	switch method {
+	case QuartileMooreMcCabe:
+		return "QuartileMooreMcCabe"
+	case QuartileTukeyHinges:
+		return "QuartileTukeyHinges"
+	case QuartileMendenhallSincich:
+		return "QuartileMendenhallSincich"
+	case QuartileFreundPerles:
+		return "QuartileFreundPerles"
+	case QuartileMinitab:
+		return "QuartileMinitab"
+	case QuartileInterpolatedType7:
+		return "QuartileInterpolatedType7"
+	}
+
+	return fmt.Sprintf("QuartileMethod(%d)", int(method))
+}
+
+// InterQuartileRangeUsing returns the first and third quartile, and their difference, using the supplied method.
+// It panics if the method is not one of the defined QuartileMethods.
+func (set *StatisticalSampleSet) InterQuartileRangeUsing(method QuartileMethod) (q1 float64, q3 float64, iqr float64) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	q1, q3 = quartilesOfSortedValues(set.sortedValues(), method)
+
+	return q1, q3, q3 - q1
+}
+
+// FiveNumberSummary returns the minimum, first quartile, median, third quartile and maximum of the set, with the
+// quartiles computed using the supplied method.  It panics if the method is not one of the defined QuartileMethods.
+func (set *StatisticalSampleSet) FiveNumberSummary(method QuartileMethod) (minimum float64, q1 float64, median float64, q3 float64, maximum float64) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	sortedValues := set.sortedValues()
+	q1, q3 = quartilesOfSortedValues(sortedValues, method)
+
+	return set.minimumValueInTheSet, q1, medianOfAFloatSet(sortedValues).computedMedian, q3, set.maximumValueInTheSet
+}
+
+func quartilesOfSortedValues(sortedValues []float64, method QuartileMethod) (q1 float64, q3 float64) {
+	switch method {
+	case QuartileMooreMcCabe:
+		return mooreMcCabeQuartilesOfSortedValues(sortedValues)
+
+	case QuartileTukeyHinges:
+		midPoint := len(sortedValues) / 2
+		if thereIsAnOddNumberOfSamplesInTheSet(sortedValues) {
+			return medianOfAFloatSet(sortedValues[0 : midPoint+1]).computedMedian, medianOfAFloatSet(sortedValues[midPoint:]).computedMedian
+		}
+		return medianOfAFloatSet(sortedValues[0:midPoint]).computedMedian, medianOfAFloatSet(sortedValues[midPoint:]).computedMedian
+
+	case QuartileMendenhallSincich:
+		n := float64(len(sortedValues))
+		lowerPosition := math.Floor((n+1)/4 + 0.5)
+		upperPosition := math.Ceil(3*(n+1)/4 - 0.5)
+		return orderStatisticClampedToSet(sortedValues, int(lowerPosition)), orderStatisticClampedToSet(sortedValues, int(upperPosition))
+
+	case QuartileFreundPerles, QuartileInterpolatedType7:
+		return quantileOfSortedValues(sortedValues, 0.25, QuantileType7), quantileOfSortedValues(sortedValues, 0.75, QuantileType7)
+
+	case QuartileMinitab:
+		return quantileOfSortedValues(sortedValues, 0.25, QuantileType6), quantileOfSortedValues(sortedValues, 0.75, QuantileType6)
+	}
+
+	panic(fmt.Sprintf("invalid quartile method (%d)", int(method)))
+}
+
+func mooreMcCabeQuartilesOfSortedValues(sortedValues []float64) (q1 float64, q3 float64) {
+	switch len(sortedValues) {
+	case 1:
+		return sortedValues[0], sortedValues[0]
+
+	case 2:
+		return sortedValues[0], sortedValues[1]
+	}
+
+	q2MedianInfo := medianOfAFloatSet(sortedValues)
+
+	var q1MedianInfo, q3MedianInfo *medianValueAndBracketInfo
+
+	if q2MedianInfo.medianIsBetweenTwoValues {
+		q1MedianInfo = medianOfAFloatSet(sortedValues[0:q2MedianInfo.indexOfMedianRightBracketInSet])
+		q3MedianInfo = medianOfAFloatSet(sortedValues[q2MedianInfo.indexOfMedianRightBracketInSet:])
+	} else {
+		q1MedianInfo = medianOfAFloatSet(sortedValues[0:q2MedianInfo.indexOfMedianInSet])
+		q3MedianInfo = medianOfAFloatSet(sortedValues[q2MedianInfo.indexOfMedianInSet+1:])
+	}
+
+	return q1MedianInfo.computedMedian, q3MedianInfo.computedMedian
+}
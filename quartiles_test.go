@@ -0,0 +1,76 @@
+package stats_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+type quartileTestCase struct {
+	floatSet          []float64
+	method            stats.QuartileMethod
+	expectedQuartile1 float64
+	expectedQuartile3 float64
+}
+
+func (testCase *quartileTestCase) RunTest() error {
+	s, err := stats.MakeStatisticalSampleSetFrom(testCase.floatSet)
+	if err != nil {
+		return fmt.Errorf("on MakeStatisticalSampleSetFrom() got error: %s", err.Error())
+	}
+
+	gotQ1, gotQ3, gotIQR := s.InterQuartileRangeUsing(testCase.method)
+
+	if math.Abs(gotQ1-testCase.expectedQuartile1) > 1e-9 {
+		return fmt.Errorf("with method (%s) expected Q1 (%f), got (%f)", testCase.method, testCase.expectedQuartile1, gotQ1)
+	}
+	if math.Abs(gotQ3-testCase.expectedQuartile3) > 1e-9 {
+		return fmt.Errorf("with method (%s) expected Q3 (%f), got (%f)", testCase.method, testCase.expectedQuartile3, gotQ3)
+	}
+	if gotIQR != gotQ3-gotQ1 {
+		return fmt.Errorf("with method (%s) expected IQR (%f), got (%f)", testCase.method, gotQ3-gotQ1, gotIQR)
+	}
+
+	minimum, q1, median, q3, maximum := s.FiveNumberSummary(testCase.method)
+	if minimum != s.Minimum() || q1 != gotQ1 || median != s.Median() || q3 != gotQ3 || maximum != s.Maximum() {
+		return fmt.Errorf("with method (%s) expected FiveNumberSummary (%f, %f, %f, %f, %f), got (%f, %f, %f, %f, %f)", testCase.method,
+			s.Minimum(), gotQ1, s.Median(), gotQ3, s.Maximum(), minimum, q1, median, q3, maximum)
+	}
+
+	return nil
+}
+
+func TestQuartileMethods(t *testing.T) {
+	oneThroughFive := []float64{1, 2, 3, 4, 5}
+	oneThroughSeven := []float64{1, 2, 3, 4, 5, 6, 7}
+	oneThroughEight := []float64{8, 7, 6, 5, 4, 3, 2, 1}
+
+	for testIndex, testCase := range []*quartileTestCase{
+		{floatSet: oneThroughSeven, method: stats.QuartileMooreMcCabe, expectedQuartile1: 2, expectedQuartile3: 6},
+		{floatSet: oneThroughSeven, method: stats.QuartileTukeyHinges, expectedQuartile1: 2.5, expectedQuartile3: 5.5},
+		{floatSet: oneThroughSeven, method: stats.QuartileMendenhallSincich, expectedQuartile1: 2, expectedQuartile3: 6},
+		{floatSet: oneThroughSeven, method: stats.QuartileFreundPerles, expectedQuartile1: 2.5, expectedQuartile3: 5.5},
+		{floatSet: oneThroughSeven, method: stats.QuartileMinitab, expectedQuartile1: 2, expectedQuartile3: 6},
+		{floatSet: oneThroughSeven, method: stats.QuartileInterpolatedType7, expectedQuartile1: 2.5, expectedQuartile3: 5.5},
+		{floatSet: oneThroughEight, method: stats.QuartileMooreMcCabe, expectedQuartile1: 2.5, expectedQuartile3: 6.5},
+		{floatSet: oneThroughEight, method: stats.QuartileTukeyHinges, expectedQuartile1: 2.5, expectedQuartile3: 6.5},
+		{floatSet: oneThroughEight, method: stats.QuartileMendenhallSincich, expectedQuartile1: 2, expectedQuartile3: 7},
+		{floatSet: oneThroughEight, method: stats.QuartileFreundPerles, expectedQuartile1: 2.75, expectedQuartile3: 6.25},
+		{floatSet: oneThroughEight, method: stats.QuartileMinitab, expectedQuartile1: 2.25, expectedQuartile3: 6.75},
+		{floatSet: oneThroughEight, method: stats.QuartileInterpolatedType7, expectedQuartile1: 2.75, expectedQuartile3: 6.25},
+		{floatSet: oneThroughFive, method: stats.QuartileMendenhallSincich, expectedQuartile1: 2, expectedQuartile3: 4},
+		{floatSet: oneThroughFive, method: stats.QuartileTukeyHinges, expectedQuartile1: 2, expectedQuartile3: 4},
+		{floatSet: oneThroughFive, method: stats.QuartileMooreMcCabe, expectedQuartile1: 1.5, expectedQuartile3: 4.5},
+		{floatSet: []float64{7}, method: stats.QuartileTukeyHinges, expectedQuartile1: 7, expectedQuartile3: 7},
+		{floatSet: []float64{7}, method: stats.QuartileMendenhallSincich, expectedQuartile1: 7, expectedQuartile3: 7},
+		{floatSet: []float64{7}, method: stats.QuartileMinitab, expectedQuartile1: 7, expectedQuartile3: 7},
+		{floatSet: []float64{3, 1}, method: stats.QuartileTukeyHinges, expectedQuartile1: 1, expectedQuartile3: 3},
+		{floatSet: []float64{3, 1}, method: stats.QuartileInterpolatedType7, expectedQuartile1: 1.5, expectedQuartile3: 2.5},
+	} {
+		if err := testCase.RunTest(); err != nil {
+			t.Errorf("on test with index (%d): %s", testIndex, err.Error())
+		}
+	}
+}
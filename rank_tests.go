@@ -0,0 +1,415 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Exact p-values are computed when each set has fewer than this many values and there are no ties.
+const maximumSamplesForExactRankTest = 50
+
+// A RankTestResult is the result of a Mann–Whitney U test or a Wilcoxon signed-rank test.  For a Mann–Whitney U test,
+// Statistic is the U statistic of the first set (the number of pairs in which the value from the first set exceeds
+// the value from the second, counting ties as one half), and the Hodges–Lehmann estimate is the median of the
+// differences between every value in the first set and every value in the second.  For a Wilcoxon signed-rank test,
+// Statistic is the sum of the ranks of the positive differences, and the Hodges–Lehmann estimate is the median of the
+// Walsh averages.  Exact is true when the p-value and confidence interval were computed from the exact null
+// distribution, and false when they were computed from the normal approximation with continuity correction.
+type RankTestResult struct {
+	Statistic               float64
+	PValue                  float64
+	Alternative             HypothesisAlternative
+	Exact                   bool
+	HodgesLehmannEstimate   float64
+	ConfidenceLevel         float64
+	ConfidenceIntervalLower float64
+	ConfidenceIntervalUpper float64
+}
+
+// MannWhitneyUTest (also known as the Wilcoxon rank-sum test) tests whether the values in the population from which a
+// is drawn tend to be larger or smaller than those in the population from which b is drawn.  The confidence interval
+// is for the shift in location between the populations.  Computing it requires memory proportional to the product
+// of the sizes of the sets.
+func MannWhitneyUTest(a *StatisticalSampleSet, b *StatisticalSampleSet, alternative HypothesisAlternative, confidenceLevel float64) (*RankTestResult, error) {
+	if err := errorIfHypothesisTestParametersAreNotValid(alternative, confidenceLevel); err != nil {
+		return nil, err
+	}
+
+	sortedA, sortedB := a.snapshotOfSortedValues(), b.snapshotOfSortedValues()
+	nx, ny := float64(len(sortedA)), float64(len(sortedB))
+
+	rankSumOfA, tieCorrection := rankSumOfFirstOfTwoSortedSets(sortedA, sortedB)
+	w := rankSumOfA - nx*(nx+1)/2
+
+	result := &RankTestResult{
+		Statistic:       w,
+		Alternative:     alternative,
+		ConfidenceLevel: confidenceLevel,
+		Exact:           len(sortedA) < maximumSamplesForExactRankTest && len(sortedB) < maximumSamplesForExactRankTest && tieCorrection == 0,
+	}
+
+	differences := make([]float64, 0, len(sortedA)*len(sortedB))
+	for _, x := range sortedA {
+		for _, y := range sortedB {
+			differences = append(differences, x-y)
+		}
+	}
+	sort.Float64s(differences)
+
+	result.HodgesLehmannEstimate = medianOfAFloatSet(differences).computedMedian
+
+	if result.Exact {
+		distribution := newExactRankStatisticDistribution(mannWhitneyUNullDistributionCounts(len(sortedA), len(sortedB)))
+		result.PValue = distribution.pValue(w, nx*ny/2, alternative)
+		result.ConfidenceIntervalLower, result.ConfidenceIntervalUpper = distribution.confidenceIntervalFromOrderedEstimates(differences, alternative, confidenceLevel)
+
+		return result, nil
+	}
+
+	sigma := math.Sqrt(nx * ny / 12 * ((nx + ny + 1) - tieCorrection/((nx+ny)*(nx+ny-1))))
+	if sigma == 0 {
+		return nil, fmt.Errorf("all values are tied")
+	}
+
+	result.PValue = rankStatisticNormalApproximationPValue(w-nx*ny/2, sigma, alternative)
+
+	sigmaWithoutTies := math.Sqrt(nx * ny * (nx + ny + 1) / 12)
+	result.ConfidenceIntervalLower, result.ConfidenceIntervalUpper = normalApproximationConfidenceIntervalFromOrderedEstimates(differences, nx*ny/2, sigmaWithoutTies, alternative, confidenceLevel)
+
+	return result, nil
+}
+
+// WilcoxonSignedRankTest tests whether the population from which set is drawn is symmetric about hypothesizedLocation.
+// Values equal to hypothesizedLocation are discarded when computing the statistic, the p-value, the Hodges–Lehmann
+// estimate and the confidence interval, as R's wilcox.test does.  The confidence interval is for the pseudo-median of
+// the population.  Computing it requires memory proportional to the square of the size of the set.
+func WilcoxonSignedRankTest(set *StatisticalSampleSet, hypothesizedLocation float64, alternative HypothesisAlternative, confidenceLevel float64) (*RankTestResult, error) {
+	if err := errorIfHypothesisTestParametersAreNotValid(alternative, confidenceLevel); err != nil {
+		return nil, err
+	}
+
+	sortedValues := set.snapshotOfSortedValues()
+
+	v, numberOfNonZeroDifferences, tieCorrection := signedRankSumOfSortedValues(sortedValues, hypothesizedLocation)
+	if numberOfNonZeroDifferences == 0 {
+		return nil, fmt.Errorf("every value is equal to the hypothesized location")
+	}
+
+	n := float64(numberOfNonZeroDifferences)
+	thereAreZeroDifferences := numberOfNonZeroDifferences != len(sortedValues)
+
+	result := &RankTestResult{
+		Statistic:       v,
+		Alternative:     alternative,
+		ConfidenceLevel: confidenceLevel,
+		Exact:           numberOfNonZeroDifferences < maximumSamplesForExactRankTest && tieCorrection == 0 && !thereAreZeroDifferences,
+	}
+
+	nonZeroValues := make([]float64, 0, numberOfNonZeroDifferences)
+	for _, value := range sortedValues {
+		if value != hypothesizedLocation {
+			nonZeroValues = append(nonZeroValues, value)
+		}
+	}
+
+	walshAverages := make([]float64, 0, len(nonZeroValues)*(len(nonZeroValues)+1)/2)
+	for i := range nonZeroValues {
+		for j := i; j < len(nonZeroValues); j++ {
+			walshAverages = append(walshAverages, (nonZeroValues[i]+nonZeroValues[j])/2)
+		}
+	}
+	sort.Float64s(walshAverages)
+
+	result.HodgesLehmannEstimate = medianOfAFloatSet(walshAverages).computedMedian
+
+	if result.Exact {
+		distribution := newExactRankStatisticDistribution(signedRankNullDistributionCounts(numberOfNonZeroDifferences))
+		result.PValue = distribution.pValue(v, n*(n+1)/4, alternative)
+		result.ConfidenceIntervalLower, result.ConfidenceIntervalUpper = distribution.confidenceIntervalFromOrderedEstimates(walshAverages, alternative, confidenceLevel)
+
+		return result, nil
+	}
+
+	sigma := math.Sqrt(n*(n+1)*(2*n+1)/24 - tieCorrection/48)
+	if sigma == 0 {
+		return nil, fmt.Errorf("all differences are tied")
+	}
+
+	result.PValue = rankStatisticNormalApproximationPValue(v-n*(n+1)/4, sigma, alternative)
+
+	sigmaWithoutTies := math.Sqrt(n * (n + 1) * (2*n + 1) / 24)
+	result.ConfidenceIntervalLower, result.ConfidenceIntervalUpper = normalApproximationConfidenceIntervalFromOrderedEstimates(walshAverages, n*(n+1)/4, sigmaWithoutTies, alternative, confidenceLevel)
+
+	return result, nil
+}
+
+// PairedWilcoxonSignedRankTest applies WilcoxonSignedRankTest to the differences first[i] - second[i], with a
+// hypothesized location of zero.  Because a StatisticalSampleSet does not retain the order in which its values were
+// added, the paired samples are supplied as slices, which must be of the same length.
+func PairedWilcoxonSignedRankTest(first []float64, second []float64, alternative HypothesisAlternative, confidenceLevel float64) (*RankTestResult, error) {
+	if len(first) != len(second) {
+		return nil, fmt.Errorf("paired samples must have the same number of values")
+	}
+
+	differences := make([]float64, len(first))
+	for i := range first {
+		differences[i] = first[i] - second[i]
+	}
+
+	set, err := MakeStatisticalSampleSetFrom(differences)
+	if err != nil {
+		return nil, err
+	}
+
+	return WilcoxonSignedRankTest(set, 0, alternative, confidenceLevel)
+}
+
+// rankSumOfFirstOfTwoSortedSets merges two sorted sets, assigning tied values the mean of the ranks they span, and
+// returns the sum of the ranks of the values from the first set along with the tie correction, the sum of
+// t^3 - t over each group of t tied values.
+func rankSumOfFirstOfTwoSortedSets(sortedA []float64, sortedB []float64) (rankSumOfA float64, tieCorrection float64) {
+	i, j := 0, 0
+	ranksAssigned := 0
+
+	for i < len(sortedA) || j < len(sortedB) {
+		var value float64
+		if j >= len(sortedB) || (i < len(sortedA) && sortedA[i] <= sortedB[j]) {
+			value = sortedA[i]
+		} else {
+			value = sortedB[j]
+		}
+
+		countInA, countInB := 0, 0
+		for i < len(sortedA) && sortedA[i] == value {
+			countInA++
+			i++
+		}
+		for j < len(sortedB) && sortedB[j] == value {
+			countInB++
+			j++
+		}
+
+		t := float64(countInA + countInB)
+		midRank := float64(ranksAssigned) + (t+1)/2
+
+		rankSumOfA += float64(countInA) * midRank
+		tieCorrection += t*t*t - t
+		ranksAssigned += countInA + countInB
+	}
+
+	return rankSumOfA, tieCorrection
+}
+
+// signedRankSumOfSortedValues ranks the absolute differences between each value and location, ignoring zero
+// differences, and returns the sum of the ranks of the positive differences, the number of non-zero differences
+// and the tie correction.  Because the values are sorted, the absolute differences of the values below location
+// are in descending order and those above it in ascending order, so the two can be merged.
+func signedRankSumOfSortedValues(sortedValues []float64, location float64) (rankSumOfPositiveDifferences float64, numberOfNonZeroDifferences int, tieCorrection float64) {
+	firstIndexAtOrAboveLocation := sort.SearchFloat64s(sortedValues, location)
+	firstIndexAboveLocation := firstIndexAtOrAboveLocation
+	for firstIndexAboveLocation < len(sortedValues) && sortedValues[firstIndexAboveLocation] == location {
+		firstIndexAboveLocation++
+	}
+
+	negativeDifferences := make([]float64, 0, firstIndexAtOrAboveLocation)
+	for i := firstIndexAtOrAboveLocation - 1; i >= 0; i-- {
+		negativeDifferences = append(negativeDifferences, location-sortedValues[i])
+	}
+
+	positiveDifferences := make([]float64, 0, len(sortedValues)-firstIndexAboveLocation)
+	for _, v := range sortedValues[firstIndexAboveLocation:] {
+		positiveDifferences = append(positiveDifferences, v-location)
+	}
+
+	rankSumOfPositiveDifferences, tieCorrection = rankSumOfFirstOfTwoSortedSets(positiveDifferences, negativeDifferences)
+
+	return rankSumOfPositiveDifferences, len(negativeDifferences) + len(positiveDifferences), tieCorrection
+}
+
+// rankStatisticNormalApproximationPValue computes a p-value from the difference between a statistic and its mean
+// under the null hypothesis, with a continuity correction of one half.
+func rankStatisticNormalApproximationPValue(differenceFromNullMean float64, sigma float64, alternative HypothesisAlternative) float64 {
+	switch alternative {
+	case AlternativeLess:
+		return standardNormalCDF((differenceFromNullMean + 0.5) / sigma)
+	case AlternativeGreater:
+		return 1 - standardNormalCDF((differenceFromNullMean-0.5)/sigma)
+	}
+
+	correction := 0.5
+	if differenceFromNullMean < 0 {
+		correction = -0.5
+	} else if differenceFromNullMean == 0 {
+		correction = 0
+	}
+
+	z := (differenceFromNullMean - correction) / sigma
+
+	return math.Min(1, 2*math.Min(standardNormalCDF(z), 1-standardNormalCDF(z)))
+}
+
+// normalApproximationConfidenceIntervalFromOrderedEstimates returns the confidence interval bounded by the order
+// statistics of the sorted pairwise estimates whose positions are given by the normal approximation to the null
+// distribution of the rank statistic.
+func normalApproximationConfidenceIntervalFromOrderedEstimates(sortedEstimates []float64, nullMean float64, sigma float64, alternative HypothesisAlternative, confidenceLevel float64) (lower float64, upper float64) {
+	alpha := 1 - confidenceLevel
+	if alternative == AlternativeTwoSided {
+		alpha = alpha / 2
+	}
+
+	criticalValue := int(math.Round(nullMean - standardNormalQuantile(1-alpha)*sigma))
+
+	return orderedEstimatesConfidenceInterval(sortedEstimates, criticalValue, alternative)
+}
+
+// orderedEstimatesConfidenceInterval returns the interval from the criticalValue-th smallest to the
+// criticalValue-th largest of the sorted pairwise estimates, with the bound opposite a one-sided alternative
+// being infinite.
+func orderedEstimatesConfidenceInterval(sortedEstimates []float64, criticalValue int, alternative HypothesisAlternative) (lower float64, upper float64) {
+	if criticalValue < 1 {
+		criticalValue = 1
+	}
+	if criticalValue > len(sortedEstimates) {
+		criticalValue = len(sortedEstimates)
+	}
+
+	lower = sortedEstimates[criticalValue-1]
+	upper = sortedEstimates[len(sortedEstimates)-criticalValue]
+
+	switch alternative {
+	case AlternativeLess:
+		lower = math.Inf(-1)
+	case AlternativeGreater:
+		upper = math.Inf(1)
+	}
+
+	return lower, upper
+}
+
+// An exactRankStatisticDistribution is the null distribution of an integer valued rank statistic, given as the
+// number of ways in which each value of the statistic can arise.
+type exactRankStatisticDistribution struct {
+	cumulativeProbabilities []float64
+}
+
+func newExactRankStatisticDistribution(counts []float64) *exactRankStatisticDistribution {
+	total := float64(0)
+	for _, c := range counts {
+		total += c
+	}
+
+	cumulativeProbabilities := make([]float64, len(counts))
+	cumulativeCount := float64(0)
+	for i, c := range counts {
+		cumulativeCount += c
+		cumulativeProbabilities[i] = cumulativeCount / total
+	}
+
+	return &exactRankStatisticDistribution{cumulativeProbabilities: cumulativeProbabilities}
+}
+
+// cdf returns the probability that the statistic is less than or equal to q.
+func (distribution *exactRankStatisticDistribution) cdf(q float64) float64 {
+	k := int(math.Floor(q + 1e-7))
+	if k < 0 {
+		return 0
+	}
+	if k >= len(distribution.cumulativeProbabilities) {
+		return 1
+	}
+
+	return distribution.cumulativeProbabilities[k]
+}
+
+// quantile returns the smallest value of the statistic at which the cumulative probability is at least p.
+func (distribution *exactRankStatisticDistribution) quantile(p float64) int {
+	for k, cumulativeProbability := range distribution.cumulativeProbabilities {
+		if cumulativeProbability >= p*(1-64*2.220446049250313e-16) {
+			return k
+		}
+	}
+
+	return len(distribution.cumulativeProbabilities) - 1
+}
+
+func (distribution *exactRankStatisticDistribution) pValue(statistic float64, nullMean float64, alternative HypothesisAlternative) float64 {
+	switch alternative {
+	case AlternativeLess:
+		return distribution.cdf(statistic)
+	case AlternativeGreater:
+		return 1 - distribution.cdf(statistic-1)
+	}
+
+	var p float64
+	if statistic > nullMean {
+		p = 1 - distribution.cdf(statistic-1)
+	} else {
+		p = distribution.cdf(statistic)
+	}
+
+	return math.Min(1, 2*p)
+}
+
+func (distribution *exactRankStatisticDistribution) confidenceIntervalFromOrderedEstimates(sortedEstimates []float64, alternative HypothesisAlternative, confidenceLevel float64) (lower float64, upper float64) {
+	alpha := 1 - confidenceLevel
+	if alternative == AlternativeTwoSided {
+		alpha = alpha / 2
+	}
+
+	return orderedEstimatesConfidenceInterval(sortedEstimates, distribution.quantile(alpha), alternative)
+}
+
+// mannWhitneyUNullDistributionCounts returns the number of ways in which U can take each value from 0 to m * n.
+// These are the coefficients of the Gaussian binomial coefficient [m + n choose m], computed using the recurrence
+// [t choose k] = [t - 1 choose k - 1] + q^k [t - 1 choose k], which involves only additions.
+func mannWhitneyUNullDistributionCounts(m int, n int) []float64 {
+	maximumDegree := m * n
+
+	polynomials := make([][]float64, m+1)
+	for k := range polynomials {
+		polynomials[k] = make([]float64, maximumDegree+1)
+	}
+	polynomials[0][0] = 1
+
+	for t := 1; t <= m+n; t++ {
+		highestK, lowestK := t, t-n
+		if highestK > m {
+			highestK = m
+		}
+		if lowestK < 1 {
+			lowestK = 1
+		}
+
+		// [t choose k] for k < t - n never contributes to [m + n choose m]
+		for k := highestK; k >= lowestK; k-- {
+			degree := k * (t - k)
+			for d := degree; d >= 0; d-- {
+				shifted := float64(0)
+				if d >= k {
+					shifted = polynomials[k][d-k]
+				}
+				polynomials[k][d] = polynomials[k-1][d] + shifted
+			}
+		}
+	}
+
+	return polynomials[m]
+}
+
+// signedRankNullDistributionCounts returns the number of subsets of {1, ..., n} whose elements sum to each value
+// from 0 to n(n + 1) / 2.
+func signedRankNullDistributionCounts(n int) []float64 {
+	maximumSum := n * (n + 1) / 2
+
+	counts := make([]float64, maximumSum+1)
+	counts[0] = 1
+
+	for i := 1; i <= n; i++ {
+		for sum := i * (i + 1) / 2; sum >= i; sum-- {
+			counts[sum] += counts[sum-i]
+		}
+	}
+
+	return counts
+}
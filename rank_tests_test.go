@@ -0,0 +1,174 @@
+package stats_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+// the example data from the R documentation for wilcox.test
+var (
+	depressionBefore = []float64{1.83, 0.50, 1.62, 2.48, 1.68, 1.88, 1.55, 3.06, 1.30}
+	depressionAfter  = []float64{0.878, 0.647, 0.598, 2.05, 1.06, 1.29, 1.06, 3.14, 1.29}
+	permeabilityX    = []float64{0.80, 0.83, 1.89, 1.04, 1.45, 1.38, 1.91, 1.64, 0.73, 1.46}
+	permeabilityY    = []float64{1.15, 0.88, 0.90, 0.74, 1.21}
+)
+
+type rankTestExpectation struct {
+	statistic               float64
+	pValue                  float64
+	exact                   bool
+	hodgesLehmannEstimate   float64
+	confidenceIntervalLower float64
+	confidenceIntervalUpper float64
+}
+
+func (expected *rankTestExpectation) compareTo(got *stats.RankTestResult) error {
+	if expected.exact != got.Exact {
+		return fmt.Errorf("expected Exact (%t), got (%t)", expected.exact, got.Exact)
+	}
+
+	for _, comparison := range []struct {
+		name      string
+		expected  float64
+		got       float64
+		tolerance float64
+	}{
+		{"Statistic", expected.statistic, got.Statistic, 1e-9},
+		{"PValue", expected.pValue, got.PValue, 1e-6},
+		{"HodgesLehmannEstimate", expected.hodgesLehmannEstimate, got.HodgesLehmannEstimate, 1e-9},
+		{"ConfidenceIntervalLower", expected.confidenceIntervalLower, got.ConfidenceIntervalLower, 1e-9},
+		{"ConfidenceIntervalUpper", expected.confidenceIntervalUpper, got.ConfidenceIntervalUpper, 1e-9},
+	} {
+		if math.IsInf(comparison.expected, 0) {
+			if comparison.got != comparison.expected {
+				return fmt.Errorf("expected %s (%f), got (%f)", comparison.name, comparison.expected, comparison.got)
+			}
+			continue
+		}
+
+		if math.Abs(comparison.expected-comparison.got) > comparison.tolerance {
+			return fmt.Errorf("expected %s (%f), got (%f)", comparison.name, comparison.expected, comparison.got)
+		}
+	}
+
+	return nil
+}
+
+func TestRankTests(t *testing.T) {
+	x, _ := stats.MakeStatisticalSampleSetFrom(permeabilityX)
+	y, _ := stats.MakeStatisticalSampleSetFrom(permeabilityY)
+	low, _ := stats.MakeStatisticalSampleSetFrom([]float64{1, 2, 3})
+	high, _ := stats.MakeStatisticalSampleSetFrom([]float64{4, 5, 6, 7})
+	group1, _ := stats.MakeStatisticalSampleSetFrom(sleepGroup1)
+	group2, _ := stats.MakeStatisticalSampleSetFrom(sleepGroup2)
+
+	shiftedDifferences := make([]float64, len(sleepGroup1))
+	for i := range sleepGroup1 {
+		shiftedDifferences[i] = sleepGroup1[i] - sleepGroup2[i] + 1
+	}
+	shiftedSleepDifferences, _ := stats.MakeStatisticalSampleSetFrom(shiftedDifferences)
+
+	for testIndex, testCase := range []struct {
+		run      func() (*stats.RankTestResult, error)
+		expected *rankTestExpectation
+	}{
+		{
+			// wilcox.test(x, y, alternative = "greater", conf.int = TRUE)
+			run: func() (*stats.RankTestResult, error) {
+				return stats.MannWhitneyUTest(x, y, stats.AlternativeGreater, 0.95)
+			},
+			expected: &rankTestExpectation{35, 0.1272061, true, 0.305, -0.08, math.Inf(1)},
+		},
+		{
+			// wilcox.test(x, y, conf.int = TRUE)
+			run: func() (*stats.RankTestResult, error) {
+				return stats.MannWhitneyUTest(x, y, stats.AlternativeTwoSided, 0.95)
+			},
+			expected: &rankTestExpectation{35, 0.2544123, true, 0.305, -0.15, 0.76},
+		},
+		{
+			// the smallest attainable two-sided p-value is 2 / choose(7, 3), so the interval spans every difference
+			run: func() (*stats.RankTestResult, error) {
+				return stats.MannWhitneyUTest(low, high, stats.AlternativeTwoSided, 0.95)
+			},
+			expected: &rankTestExpectation{0, 2.0 / 35, true, -3.5, -6, -1},
+		},
+		{
+			// wilcox.test(extra ~ group, data = sleep), which has ties
+			run: func() (*stats.RankTestResult, error) {
+				return stats.MannWhitneyUTest(group1, group2, stats.AlternativeTwoSided, 0.95)
+			},
+			expected: &rankTestExpectation{25.5, 0.06932758, false, -1.35, -3.6, 0.1},
+		},
+		{
+			// wilcox.test(x, y, paired = TRUE, alternative = "greater")
+			run: func() (*stats.RankTestResult, error) {
+				return stats.PairedWilcoxonSignedRankTest(depressionBefore, depressionAfter, stats.AlternativeGreater, 0.95)
+			},
+			expected: &rankTestExpectation{40, 0.01953125, true, 0.46, 0.175, math.Inf(1)},
+		},
+		{
+			// wilcox.test(x, y, paired = TRUE)
+			run: func() (*stats.RankTestResult, error) {
+				return stats.PairedWilcoxonSignedRankTest(depressionBefore, depressionAfter, stats.AlternativeTwoSided, 0.95)
+			},
+			expected: &rankTestExpectation{40, 0.0390625, true, 0.46, 0.01, 0.786},
+		},
+		{
+			// wilcox.test(extra ~ group, data = sleep, paired = TRUE, conf.int = TRUE), which has ties and a zero difference;
+			// R finds the estimate (-1.400031) and interval (-2.949921, -1.050018) by root finding, to within 1e-4 of
+			// the Walsh averages of the non-zero differences
+			run: func() (*stats.RankTestResult, error) {
+				return stats.PairedWilcoxonSignedRankTest(sleepGroup1, sleepGroup2, stats.AlternativeTwoSided, 0.95)
+			},
+			expected: &rankTestExpectation{0, 0.009090698, false, -1.4, -2.95, -1.05},
+		},
+		{
+			run: func() (*stats.RankTestResult, error) {
+				return stats.PairedWilcoxonSignedRankTest(sleepGroup1, sleepGroup2, stats.AlternativeLess, 0.95)
+			},
+			expected: &rankTestExpectation{0, 0.004545349, false, -1.4, math.Inf(-1), -1.15},
+		},
+		{
+			// the same differences shifted by 1 and tested against a location of 1, so that a value equals it
+			run: func() (*stats.RankTestResult, error) {
+				return stats.WilcoxonSignedRankTest(shiftedSleepDifferences, 1, stats.AlternativeTwoSided, 0.95)
+			},
+			expected: &rankTestExpectation{0, 0.009090698, false, -0.4, -1.95, -0.05},
+		},
+	} {
+		result, err := testCase.run()
+		if err != nil {
+			t.Errorf("on test with index (%d) got error: %s", testIndex, err.Error())
+			continue
+		}
+
+		if err := testCase.expected.compareTo(result); err != nil {
+			t.Errorf("on test with index (%d): %s", testIndex, err.Error())
+		}
+	}
+}
+
+func TestRankTestErrors(t *testing.T) {
+	constant, _ := stats.MakeStatisticalSampleSetFrom([]float64{2, 2, 2})
+	group1, _ := stats.MakeStatisticalSampleSetFrom(sleepGroup1)
+
+	if _, err := stats.MannWhitneyUTest(constant, constant, stats.AlternativeTwoSided, 0.95); err == nil {
+		t.Errorf("on MannWhitneyUTest() with every value tied, expected error, got none")
+	}
+
+	if _, err := stats.WilcoxonSignedRankTest(constant, 2, stats.AlternativeTwoSided, 0.95); err == nil {
+		t.Errorf("on WilcoxonSignedRankTest() with every value at the hypothesized location, expected error, got none")
+	}
+
+	if _, err := stats.PairedWilcoxonSignedRankTest([]float64{1, 2}, []float64{1}, stats.AlternativeTwoSided, 0.95); err == nil {
+		t.Errorf("on PairedWilcoxonSignedRankTest() with unequal lengths, expected error, got none")
+	}
+
+	if _, err := stats.MannWhitneyUTest(group1, group1, stats.AlternativeTwoSided, 0); err == nil {
+		t.Errorf("on MannWhitneyUTest() with confidence level (0), expected error, got none")
+	}
+}
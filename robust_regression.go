@@ -0,0 +1,437 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// A RobustRegressionResult is a fit of the line y = Intercept + Slope*x that is insensitive to outliers.  Residuals[i]
+// is the observed minus the fitted value of the ith pair, in the order in which the pairs were supplied, and Scale is
+// the robust estimate of the standard deviation of the residuals, their median absolute value divided by 0.6745.  For
+// M-estimation, Weights[i] is the final weight of the ith pair, between 0 (rejected as an outlier) and 1, and
+// Iterations is the number of reweighted least squares fits; for the other methods, Weights is nil and Iterations
+// is zero.  For repeated medians, RedrawnResamples is the number of bootstrap resamples that were discarded and drawn
+// again because their x values were all the same; it is zero for the other methods.
+type RobustRegressionResult struct {
+	Slope                       float64
+	Intercept                   float64
+	SlopeConfidenceInterval     Interval
+	InterceptConfidenceInterval Interval
+	Residuals                   []float64
+	Scale                       float64
+	Weights                     []float64
+	Iterations                  int
+	RedrawnResamples            int
+}
+
+// TheilSenRegression fits a line whose slope is the median of the slopes between every two pairs with different x
+// values, and whose intercept is the median of y - Slope*x.  It tolerates up to about 29% of the pairs being
+// outliers.  The slope interval is Sen's (1968) distribution-free interval, a pair of order statistics of the slopes
+// chosen from the normal approximation to the null distribution of Kendall's statistic, with the variance corrected
+// for ties.  The intercept interval is the union of the order statistic intervals for the median of y - b*x, where b
+// is the slope and each end of its interval, so it is conservative.  Computing the fit requires memory proportional to
+// the square of the number of pairs.  There must be at least six pairs, and neither x nor y may be constant.
+func TheilSenRegression(set *PairedSampleSet, confidenceLevel float64) (*RobustRegressionResult, error) {
+	if err := errorIfConfidenceLevelIsNotValid(confidenceLevel); err != nil {
+		return nil, err
+	}
+
+	if err := errorIfPairedSampleSetIsTooSmall(set, 6); err != nil {
+		return nil, err
+	}
+
+	x, y := set.xValues, set.yValues
+	n := float64(len(x))
+
+	slopes := make([]float64, 0, len(x)*(len(x)-1)/2)
+	for i := range x {
+		for j := i + 1; j < len(x); j++ {
+			if x[i] != x[j] {
+				slopes = append(slopes, (y[j]-y[i])/(x[j]-x[i]))
+			}
+		}
+	}
+	sort.Float64s(slopes)
+
+	slope := medianOfAFloatSet(slopes).computedMedian
+
+	xTies := tieStatisticsOfSortedValues(set.xSet.snapshotOfSortedValues())
+	yTies := tieStatisticsOfSortedValues(set.ySet.snapshotOfSortedValues())
+	sigma := math.Sqrt((n*(n-1)*(2*n+5) - xTies.sumForVariance - yTies.sumForVariance) / 18)
+	criticalValue := standardNormalQuantile((1+confidenceLevel)/2) * sigma
+
+	numberOfSlopes := float64(len(slopes))
+	lowerIndex := int(math.Max(math.Round((numberOfSlopes-criticalValue)/2)-1, 0))
+	upperIndex := int(math.Min(math.Round((numberOfSlopes+criticalValue)/2), numberOfSlopes-1))
+
+	result := &RobustRegressionResult{
+		Slope:                   slope,
+		SlopeConfidenceInterval: Interval{Lower: slopes[lowerIndex], Upper: slopes[upperIndex], Level: confidenceLevel, Method: IntervalOrderStatistic},
+	}
+
+	result.InterceptConfidenceInterval = Interval{Lower: math.Inf(1), Upper: math.Inf(-1), Level: confidenceLevel, Method: IntervalOrderStatistic}
+	for i, b := range []float64{slope, slopes[lowerIndex], slopes[upperIndex]} {
+		intercepts := make([]float64, len(x))
+		for j := range x {
+			intercepts[j] = y[j] - b*x[j]
+		}
+
+		interceptSet, err := MakeStatisticalSampleSetFrom(intercepts)
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			result.Intercept = interceptSet.Median()
+		}
+
+		interval, err := interceptSet.MedianConfidenceInterval(confidenceLevel)
+		if err != nil {
+			return nil, err
+		}
+
+		result.InterceptConfidenceInterval.Lower = math.Min(result.InterceptConfidenceInterval.Lower, interval.Lower)
+		result.InterceptConfidenceInterval.Upper = math.Max(result.InterceptConfidenceInterval.Upper, interval.Upper)
+	}
+
+	result.setResidualsAndScale(set)
+
+	return result, nil
+}
+
+// RepeatedMedianRegression fits a line using Siegel's (1982) repeated medians: for each pair, the median of the slopes
+// (and of the intercepts) of the lines through it and every other pair with a different x value, and then the median
+// of those medians over the pairs.  It tolerates up to half of the pairs being outliers.  There is no simple
+// distribution-free interval for the repeated median, so the intervals are bootstrap percentile intervals, computed by
+// resampling the pairs as controlled by options (the StandardError and NumberOfInnerResamples options are ignored).
+// A resample whose x values are all the same has no line, so it is discarded and drawn again; with few pairs or few
+// distinct x values this is common, and the intervals are then conditional on the x values not being constant.  Each
+// resample costs time proportional to n² log n for n pairs.  An error is returned if ctx is cancelled.  There must be
+// at least three pairs, and neither x nor y may be constant.
+func RepeatedMedianRegression(ctx context.Context, set *PairedSampleSet, options BootstrapOptions) (*RobustRegressionResult, error) {
+	if options.NumberOfResamples < 0 {
+		return nil, fmt.Errorf("number of resamples must not be negative")
+	}
+
+	if options.NumberOfResamples == 0 {
+		options.NumberOfResamples = defaultNumberOfBootstrapResamples
+	}
+
+	if options.ConfidenceLevel == 0 {
+		options.ConfidenceLevel = defaultBootstrapConfidenceLevel
+	}
+
+	if err := errorIfConfidenceLevelIsNotValid(options.ConfidenceLevel); err != nil {
+		return nil, err
+	}
+
+	if err := errorIfPairedSampleSetIsTooSmall(set, 3); err != nil {
+		return nil, err
+	}
+
+	slope, intercept := repeatedMedianLine(set.xValues, set.yValues)
+
+	slopeReplicates := make([]float64, options.NumberOfResamples)
+	interceptReplicates := make([]float64, options.NumberOfResamples)
+	redrawsOfResample := make([]int, options.NumberOfResamples)
+
+	err := forEachItemInRandomBlocks(ctx, options.NumberOfResamples, options.Parallelism, options.Seed, func(rng *rand.Rand, index int) error {
+		n := len(set.xValues)
+		x, y := make([]float64, n), make([]float64, n)
+
+		// the x values of the set are not constant, so this ends after a few redraws at most
+		for xIsConstant := true; xIsConstant; {
+			for i := range x {
+				drawn := rng.Intn(n)
+				x[i], y[i] = set.xValues[drawn], set.yValues[drawn]
+				xIsConstant = i == 0 || xIsConstant && x[i] == x[0]
+			}
+
+			if xIsConstant {
+				redrawsOfResample[index]++
+			}
+		}
+
+		slopeReplicates[index], interceptReplicates[index] = repeatedMedianLine(x, y)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RobustRegressionResult{
+		Slope:                       slope,
+		Intercept:                   intercept,
+		SlopeConfidenceInterval:     percentileIntervalOfReplicates(slopeReplicates, options.ConfidenceLevel),
+		InterceptConfidenceInterval: percentileIntervalOfReplicates(interceptReplicates, options.ConfidenceLevel),
+	}
+
+	for _, redraws := range redrawsOfResample {
+		result.RedrawnResamples += redraws
+	}
+
+	result.setResidualsAndScale(set)
+
+	return result, nil
+}
+
+// repeatedMedianLine returns Siegel's repeated median slope and intercept of the pairs (x[i], y[i]).  The x values
+// must not all be the same.
+func repeatedMedianLine(x []float64, y []float64) (slope float64, intercept float64) {
+	medianSlopes := make([]float64, 0, len(x))
+	medianIntercepts := make([]float64, 0, len(x))
+
+	slopesThroughPair := make([]float64, 0, len(x))
+	interceptsThroughPair := make([]float64, 0, len(x))
+
+	for i := range x {
+		slopesThroughPair, interceptsThroughPair = slopesThroughPair[:0], interceptsThroughPair[:0]
+
+		for j := range x {
+			if x[j] != x[i] {
+				slopesThroughPair = append(slopesThroughPair, (y[j]-y[i])/(x[j]-x[i]))
+				interceptsThroughPair = append(interceptsThroughPair, (x[j]*y[i]-x[i]*y[j])/(x[j]-x[i]))
+			}
+		}
+
+		if len(slopesThroughPair) == 0 {
+			continue
+		}
+
+		sort.Float64s(slopesThroughPair)
+		sort.Float64s(interceptsThroughPair)
+
+		medianSlopes = append(medianSlopes, medianOfAFloatSet(slopesThroughPair).computedMedian)
+		medianIntercepts = append(medianIntercepts, medianOfAFloatSet(interceptsThroughPair).computedMedian)
+	}
+
+	sort.Float64s(medianSlopes)
+	sort.Float64s(medianIntercepts)
+
+	return medianOfAFloatSet(medianSlopes).computedMedian, medianOfAFloatSet(medianIntercepts).computedMedian
+}
+
+func percentileIntervalOfReplicates(replicates []float64, level float64) Interval {
+	sortedReplicates := make([]float64, len(replicates))
+	copy(sortedReplicates, replicates)
+	sort.Float64s(sortedReplicates)
+
+	alpha := 1 - level
+
+	return Interval{
+		Lower:  quantileOfSortedValues(sortedReplicates, alpha/2, QuantileDefault),
+		Upper:  quantileOfSortedValues(sortedReplicates, 1-alpha/2, QuantileDefault),
+		Level:  level,
+		Method: IntervalBootstrapPercentile,
+	}
+}
+
+// An MEstimator selects the function used by MEstimationRegression to weight each pair by the size of its residual.
+type MEstimator int
+
+const (
+	// Huber's estimator, with tuning constant 1.345, gives a weight of 1 to residuals within 1.345 times the scale
+	// and a weight inversely proportional to the size of larger residuals.
+	MEstimatorHuber MEstimator = iota
+	// Tukey's bisquare (biweight) estimator, with tuning constant 4.685, gives smoothly decreasing weights to larger
+	// residuals and a weight of 0 to residuals beyond 4.685 times the scale, so gross outliers are ignored entirely.
+	MEstimatorTukeyBisquare
+)
+
+func (estimator MEstimator) String() string {
+	switch estimator {
+	case MEstimatorHuber:
+		return "Huber"
+	case MEstimatorTukeyBisquare:
+		return "Tukey bisquare"
+	}
+
+	return fmt.Sprintf("MEstimator(%d)", int(estimator))
+}
+
+// The tuning constants give 95% efficiency relative to least squares when the residuals are normally distributed.
+const (
+	huberTuningConstant         = 1.345
+	tukeyBisquareTuningConstant = 4.685
+)
+
+// psi returns the influence function of the estimator, and its derivative, at the scaled residual u.
+func (estimator MEstimator) psi(u float64) (psi float64, derivative float64) {
+	switch estimator {
+	case MEstimatorHuber:
+		if math.Abs(u) <= huberTuningConstant {
+			return u, 1
+		}
+		return math.Copysign(huberTuningConstant, u), 0
+
+	default:
+		if math.Abs(u) > tukeyBisquareTuningConstant {
+			return 0, 0
+		}
+		v := (u / tukeyBisquareTuningConstant) * (u / tukeyBisquareTuningConstant)
+		return u * (1 - v) * (1 - v), (1 - v) * (1 - 5*v)
+	}
+}
+
+// weight returns psi(u)/u, the weight of a pair whose scaled residual is u.
+func (estimator MEstimator) weight(u float64) float64 {
+	if u == 0 {
+		return 1
+	}
+
+	psi, _ := estimator.psi(u)
+
+	return psi / u
+}
+
+const (
+	maximumIterationsOfMEstimation  = 200
+	convergenceToleranceOfMEstimate = 1e-10
+)
+
+// MEstimationRegression fits a line by M-estimation, using iteratively reweighted least squares: starting from the
+// least squares line, each pair is weighted according to estimator by the size of its residual relative to the
+// scale, and the line is refitted by weighted least squares until the residuals stop changing.  The scale is
+// re-estimated in each iteration as the median absolute residual divided by 0.6745.  The bisquare estimator can
+// converge to a poor local solution from a poor start, so it starts from the Huber fit.
+//
+// The intervals are based on Student's t-distribution with n-2 degrees of freedom, using Huber's (1981) asymptotic
+// covariance of the coefficients, which equals that of least squares when no pair is downweighted.  There must be at
+// least three pairs, and neither x nor y may be constant.  An error is returned if more than half of the pairs lie on
+// a single line (so that the scale is zero), or if the fit does not converge.
+func MEstimationRegression(set *PairedSampleSet, estimator MEstimator, confidenceLevel float64) (*RobustRegressionResult, error) {
+	if estimator < MEstimatorHuber || estimator > MEstimatorTukeyBisquare {
+		return nil, fmt.Errorf("estimator must be one of MEstimatorHuber or MEstimatorTukeyBisquare")
+	}
+
+	if err := errorIfConfidenceLevelIsNotValid(confidenceLevel); err != nil {
+		return nil, err
+	}
+
+	if err := errorIfPairedSampleSetIsTooSmall(set, 3); err != nil {
+		return nil, err
+	}
+
+	sxx, _, sxy := set.sumsOfSquaredDeviations()
+	result := &RobustRegressionResult{Slope: sxy / sxx}
+	result.Intercept = set.ySet.Mean() - result.Slope*set.xSet.Mean()
+	result.setResidualsAndScale(set)
+
+	estimators := []MEstimator{estimator}
+	if estimator == MEstimatorTukeyBisquare {
+		estimators = []MEstimator{MEstimatorHuber, MEstimatorTukeyBisquare}
+	}
+
+	for _, estimatorOfStage := range estimators {
+		if err := result.iterativelyReweight(set, estimatorOfStage); err != nil {
+			return nil, err
+		}
+	}
+
+	// Huber's covariance is (kappa² (sum of psi² / (n - 2)) / (mean of psi')²) s² (X'X)^-1, where kappa corrects
+	// for the estimation of the coefficients from the same data
+	n := float64(set.Count())
+	sumOfSquaredPsi, sumOfDerivatives, sumOfSquaredDerivatives := float64(0), float64(0), float64(0)
+	for _, residual := range result.Residuals {
+		psi, derivative := estimator.psi(residual / result.Scale)
+		sumOfSquaredPsi += psi * psi
+		sumOfDerivatives += derivative
+		sumOfSquaredDerivatives += derivative * derivative
+	}
+
+	meanOfDerivatives := sumOfDerivatives / n
+	if meanOfDerivatives == 0 {
+		return nil, fmt.Errorf("every pair was rejected as an outlier")
+	}
+
+	varianceOfDerivatives := sumOfSquaredDerivatives/n - meanOfDerivatives*meanOfDerivatives
+	kappa := 1 + 2*varianceOfDerivatives/(n*meanOfDerivatives*meanOfDerivatives)
+	varianceFactor := kappa * kappa * (sumOfSquaredPsi / (n - 2)) / (meanOfDerivatives * meanOfDerivatives) * result.Scale * result.Scale
+
+	meanOfX := set.xSet.Mean()
+	slopeStandardError := math.Sqrt(varianceFactor / sxx)
+	interceptStandardError := math.Sqrt(varianceFactor * (1/n + meanOfX*meanOfX/sxx))
+
+	criticalValue := studentTQuantile((1+confidenceLevel)/2, n-2)
+	result.SlopeConfidenceInterval = Interval{Lower: result.Slope - criticalValue*slopeStandardError, Upper: result.Slope + criticalValue*slopeStandardError, Level: confidenceLevel, Method: IntervalStudentT}
+	result.InterceptConfidenceInterval = Interval{Lower: result.Intercept - criticalValue*interceptStandardError, Upper: result.Intercept + criticalValue*interceptStandardError, Level: confidenceLevel, Method: IntervalStudentT}
+
+	return result, nil
+}
+
+// iterativelyReweight refines the line of result by iteratively reweighted least squares using estimator, starting
+// from its current residuals and scale.
+func (result *RobustRegressionResult) iterativelyReweight(set *PairedSampleSet, estimator MEstimator) error {
+	weights := make([]float64, set.Count())
+
+	for iteration := 1; iteration <= maximumIterationsOfMEstimation; iteration++ {
+		if result.Scale == 0 {
+			return fmt.Errorf("more than half of the pairs lie on a single line, so the scale of the residuals is zero")
+		}
+
+		for i, residual := range result.Residuals {
+			weights[i] = estimator.weight(residual / result.Scale)
+		}
+
+		sumOfWeights, sumOfWeightedX, sumOfWeightedY := float64(0), float64(0), float64(0)
+		for i, w := range weights {
+			sumOfWeights += w
+			sumOfWeightedX += w * set.xValues[i]
+			sumOfWeightedY += w * set.yValues[i]
+		}
+		weightedMeanOfX, weightedMeanOfY := sumOfWeightedX/sumOfWeights, sumOfWeightedY/sumOfWeights
+
+		weightedSxx, weightedSxy := float64(0), float64(0)
+		for i, w := range weights {
+			deviationOfX := set.xValues[i] - weightedMeanOfX
+			weightedSxx += w * deviationOfX * deviationOfX
+			weightedSxy += w * deviationOfX * (set.yValues[i] - weightedMeanOfY)
+		}
+
+		if !(weightedSxx > 0) {
+			return fmt.Errorf("the pairs that were not rejected as outliers have the same x value")
+		}
+
+		previousResiduals := result.Residuals
+
+		result.Slope = weightedSxy / weightedSxx
+		result.Intercept = weightedMeanOfY - result.Slope*weightedMeanOfX
+		result.setResidualsAndScale(set)
+		result.Weights = weights
+		result.Iterations++
+
+		sumOfSquaredChanges, sumOfSquaredPreviousResiduals := float64(0), float64(0)
+		for i, residual := range result.Residuals {
+			change := residual - previousResiduals[i]
+			sumOfSquaredChanges += change * change
+			sumOfSquaredPreviousResiduals += previousResiduals[i] * previousResiduals[i]
+		}
+
+		if sumOfSquaredChanges <= convergenceToleranceOfMEstimate*convergenceToleranceOfMEstimate*sumOfSquaredPreviousResiduals {
+			for i, residual := range result.Residuals {
+				weights[i] = estimator.weight(residual / result.Scale)
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("M-estimation did not converge in %d iterations", maximumIterationsOfMEstimation)
+}
+
+// setResidualsAndScale computes the residuals of the pairs in set from the line of result, and their robust scale.
+func (result *RobustRegressionResult) setResidualsAndScale(set *PairedSampleSet) {
+	result.Residuals = make([]float64, set.Count())
+	absoluteResiduals := make([]float64, set.Count())
+
+	for i, x := range set.xValues {
+		result.Residuals[i] = set.yValues[i] - (result.Intercept + result.Slope*x)
+		absoluteResiduals[i] = math.Abs(result.Residuals[i])
+	}
+
+	sort.Float64s(absoluteResiduals)
+	result.Scale = medianOfAFloatSet(absoluteResiduals).computedMedian / 0.6745
+}
@@ -0,0 +1,179 @@
+package stats_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+// a line y = 2 + 0.5x with small deviations and one gross outlier at x = 8
+var robustX = []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+var robustY = []float64{2.6, 3.0, 3.4, 4.1, 4.4, 5.1, 5.4, 19.0, 6.6, 7.0, 7.4, 8.1}
+
+func TestRobustRegressions(t *testing.T) {
+	set, _ := stats.MakePairedSampleSetFrom(robustX, robustY)
+
+	theilSen, err := stats.TheilSenRegression(set, 0.95)
+	if err != nil {
+		t.Fatalf("on TheilSenRegression got unexpected error: %s", err)
+	}
+
+	huber, err := stats.MEstimationRegression(set, stats.MEstimatorHuber, 0.95)
+	if err != nil {
+		t.Fatalf("on Huber MEstimationRegression got unexpected error: %s", err)
+	}
+
+	bisquare, err := stats.MEstimationRegression(set, stats.MEstimatorTukeyBisquare, 0.95)
+	if err != nil {
+		t.Fatalf("on bisquare MEstimationRegression got unexpected error: %s", err)
+	}
+
+	for testIndex, testCase := range []struct {
+		name                      string
+		got                       *stats.RobustRegressionResult
+		expectedSlope             float64
+		expectedIntercept         float64
+		expectedScale             float64
+		expectedSlopeInterval     stats.Interval
+		expectedInterceptInterval stats.Interval
+	}{
+		{"TheilSenRegression", theilSen, 0.5, 2.05, 0.07412898443291334, stats.Interval{Lower: 0.4750000000000001, Upper: 0.5285714285714285, Method: stats.IntervalOrderStatistic}, stats.Interval{Lower: 1.7142857142857153, Upper: 2.3249999999999993, Method: stats.IntervalOrderStatistic}},
+		{"Huber", huber, 0.5016535590780211, 2.0134995853349196, 0.12211492508528894, stats.Interval{Lower: 0.4791417104970721, Upper: 0.5241654076589701, Method: stats.IntervalStudentT}, stats.Interval{Lower: 1.8478168658966498, Upper: 2.1791823047731893, Method: stats.IntervalStudentT}},
+		{"TukeyBisquare", bisquare, 0.4997334399077967, 2.0115015290633087, 0.1353555995623799, stats.Interval{Lower: 0.47873607842985433, Upper: 0.520730801385739, Method: stats.IntervalStudentT}, stats.Interval{Lower: 1.8569651328991164, Upper: 2.166037925227501, Method: stats.IntervalStudentT}},
+	} {
+		got := testCase.got
+
+		if math.Abs(got.Slope-testCase.expectedSlope) > 1e-8 || math.Abs(got.Intercept-testCase.expectedIntercept) > 1e-8 || math.Abs(got.Scale-testCase.expectedScale) > 1e-8 {
+			t.Errorf("on test with index (%d) (%s) expected Slope, Intercept and Scale (%f, %f, %f), got (%f, %f, %f)", testIndex, testCase.name, testCase.expectedSlope, testCase.expectedIntercept, testCase.expectedScale, got.Slope, got.Intercept, got.Scale)
+		}
+
+		for _, intervals := range [][2]stats.Interval{{testCase.expectedSlopeInterval, got.SlopeConfidenceInterval}, {testCase.expectedInterceptInterval, got.InterceptConfidenceInterval}} {
+			expected, gotInterval := intervals[0], intervals[1]
+			if math.Abs(gotInterval.Lower-expected.Lower) > 1e-6 || math.Abs(gotInterval.Upper-expected.Upper) > 1e-6 || gotInterval.Method != expected.Method || gotInterval.Level != 0.95 {
+				t.Errorf("on test with index (%d) (%s) expected %s interval %s, got %s interval %s", testIndex, testCase.name, expected.Method, &expected, gotInterval.Method, &gotInterval)
+			}
+		}
+
+		if math.Abs(got.Residuals[7]-(19-got.Intercept-8*got.Slope)) > 1e-12 {
+			t.Errorf("on test with index (%d) (%s) expected residual of the outlier (%f), got (%f)", testIndex, testCase.name, 19-got.Intercept-8*got.Slope, got.Residuals[7])
+		}
+	}
+
+	if theilSen.Weights != nil || theilSen.Iterations != 0 {
+		t.Errorf("expected no Weights or Iterations for TheilSenRegression, got %v and (%d)", theilSen.Weights, theilSen.Iterations)
+	}
+
+	if huber.Iterations == 0 || math.Abs(huber.Weights[7]-0.012660227502629165) > 1e-8 || huber.Weights[0] != 1 {
+		t.Errorf("expected Huber to downweight only the outlier, got Weights %v after (%d) iterations", huber.Weights, huber.Iterations)
+	}
+
+	if bisquare.Weights[7] != 0 {
+		t.Errorf("expected bisquare to reject the outlier, got weight (%f)", bisquare.Weights[7])
+	}
+}
+
+func TestMEstimationWithoutOutliersMatchesLeastSquares(t *testing.T) {
+	// every residual of the least squares line is within 1.345 times the scale, so Huber gives every pair a weight of 1
+	set, _ := stats.MakePairedSampleSetFrom(regressionX, regressionY)
+
+	huber, err := stats.MEstimationRegression(set, stats.MEstimatorHuber, 0.95)
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	leastSquares, _ := stats.LinearRegression(set)
+	slopeInterval, _ := leastSquares.SlopeConfidenceInterval(0.95)
+	interceptInterval, _ := leastSquares.InterceptConfidenceInterval(0.95)
+
+	if math.Abs(huber.Slope-leastSquares.Slope) > 1e-12 || math.Abs(huber.Intercept-leastSquares.Intercept) > 1e-12 {
+		t.Errorf("expected Slope and Intercept (%f, %f), got (%f, %f)", leastSquares.Slope, leastSquares.Intercept, huber.Slope, huber.Intercept)
+	}
+
+	if math.Abs(huber.SlopeConfidenceInterval.Lower-slopeInterval.Lower) > 1e-12 || math.Abs(huber.InterceptConfidenceInterval.Upper-interceptInterval.Upper) > 1e-12 {
+		t.Errorf("expected intervals %s and %s, got %s and %s", slopeInterval, interceptInterval, &huber.SlopeConfidenceInterval, &huber.InterceptConfidenceInterval)
+	}
+}
+
+func TestRepeatedMedianRegression(t *testing.T) {
+	set, _ := stats.MakePairedSampleSetFrom(robustX, robustY)
+
+	got, err := stats.RepeatedMedianRegression(context.Background(), set, stats.BootstrapOptions{NumberOfResamples: 500, Seed: 24})
+	if err != nil {
+		t.Fatalf("got unexpected error: %s", err)
+	}
+
+	if math.Abs(got.Slope-0.5) > 1e-12 || math.Abs(got.Intercept-2.05) > 1e-12 {
+		t.Errorf("expected Slope (0.5) and Intercept (2.05), got (%f) and (%f)", got.Slope, got.Intercept)
+	}
+
+	slopeInterval, interceptInterval := got.SlopeConfidenceInterval, got.InterceptConfidenceInterval
+	if !(slopeInterval.Lower < 0.5 && slopeInterval.Upper > 0.5 && slopeInterval.Upper < 0.6) || slopeInterval.Method != stats.IntervalBootstrapPercentile {
+		t.Errorf("expected a bootstrap percentile slope interval around 0.5, not reaching the outlier, got %s", &slopeInterval)
+	}
+	if !(interceptInterval.Lower < 2.05 && interceptInterval.Upper > 2.05) {
+		t.Errorf("expected an intercept interval around 2.05, got %s", &interceptInterval)
+	}
+
+	parallel, _ := stats.RepeatedMedianRegression(context.Background(), set, stats.BootstrapOptions{NumberOfResamples: 500, Seed: 24, Parallelism: 4})
+	if parallel.SlopeConfidenceInterval != slopeInterval || parallel.InterceptConfidenceInterval != interceptInterval {
+		t.Errorf("expected intervals not to depend on Parallelism, got %s and %s", &parallel.SlopeConfidenceInterval, &parallel.InterceptConfidenceInterval)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := stats.RepeatedMedianRegression(ctx, set, stats.BootstrapOptions{}); err == nil {
+		t.Errorf("on cancelled context expected error, got none")
+	}
+}
+
+func TestRepeatedMedianRegressionRedrawsResamplesWithConstantX(t *testing.T) {
+	threePairs, _ := stats.MakePairedSampleSetFrom([]float64{1, 2, 3}, []float64{1, 3, 2})
+	twoDistinctX, _ := stats.MakePairedSampleSetFrom([]float64{0, 0, 0, 0, 0, 1, 1, 1, 1, 1}, []float64{1, 2, 3, 4, 5, 3, 4, 5, 6, 7})
+
+	for testIndex, testCase := range []struct {
+		set                               *stats.PairedSampleSet
+		expectedMinimumOfRedrawnResamples int
+	}{
+		// with three pairs, one resample in nine has constant x
+		{threePairs, 1},
+		{twoDistinctX, 0},
+	} {
+		got, err := stats.RepeatedMedianRegression(context.Background(), testCase.set, stats.BootstrapOptions{})
+		if err != nil {
+			t.Errorf("on test with index (%d) got unexpected error: %s", testIndex, err)
+			continue
+		}
+
+		if got.RedrawnResamples < testCase.expectedMinimumOfRedrawnResamples {
+			t.Errorf("on test with index (%d) expected at least (%d) RedrawnResamples, got (%d)", testIndex, testCase.expectedMinimumOfRedrawnResamples, got.RedrawnResamples)
+		}
+
+		slopeInterval := got.SlopeConfidenceInterval
+		if !(slopeInterval.Lower <= got.Slope && got.Slope <= slopeInterval.Upper) || math.IsNaN(slopeInterval.Lower) || math.IsNaN(slopeInterval.Upper) {
+			t.Errorf("on test with index (%d) expected a slope interval around (%f), got %s", testIndex, got.Slope, &slopeInterval)
+		}
+	}
+}
+
+func TestRobustRegressionErrors(t *testing.T) {
+	fivePairs, _ := stats.MakePairedSampleSetFrom([]float64{1, 2, 3, 4, 5}, []float64{2, 1, 4, 3, 5})
+	onALine, _ := stats.MakePairedSampleSetFrom([]float64{1, 2, 3, 4, 5}, []float64{1, 2, 3, 4, 50})
+
+	if _, err := stats.TheilSenRegression(fivePairs, 0.95); err == nil {
+		t.Errorf("on TheilSenRegression of five pairs expected error, got none")
+	}
+
+	if _, err := stats.MEstimationRegression(fivePairs, stats.MEstimator(5), 0.95); err == nil {
+		t.Errorf("on unknown MEstimator expected error, got none")
+	}
+
+	if _, err := stats.MEstimationRegression(onALine, stats.MEstimatorHuber, 0.95); err == nil {
+		t.Errorf("on pairs mostly on a line expected error, got none")
+	}
+
+	if _, err := stats.RepeatedMedianRegression(context.Background(), fivePairs, stats.BootstrapOptions{ConfidenceLevel: 2}); err == nil {
+		t.Errorf("on confidence level (2) expected error, got none")
+	}
+}
@@ -0,0 +1,502 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	continuedFractionMaximumIterations = 300
+	continuedFractionEpsilon           = 3e-16
+	continuedFractionTiny              = 1e-300
+)
+
+// EvaluateContinuedFraction evaluates b0 + a1 / (b1 + a2 / (b2 + a3 / (b3 + ...))), where terms(n) returns the
+// partial numerator a_n and partial denominator b_n for n >= 1, using the modified Lentz method.  Evaluation stops
+// when a term changes the value by a relative amount less than tolerance, which should not be less than about 1e-16.
+// If that does not happen within maximumIterations terms, the value reached is returned along with an error.
+func EvaluateContinuedFraction(b0 float64, terms func(n int) (a float64, b float64), tolerance float64, maximumIterations int) (float64, error) {
+	f := b0
+	if math.Abs(f) < continuedFractionTiny {
+		f = continuedFractionTiny
+	}
+
+	c, d := f, float64(0)
+
+	for n := 1; n <= maximumIterations; n++ {
+		a, b := terms(n)
+
+		d = b + a*d
+		if math.Abs(d) < continuedFractionTiny {
+			d = continuedFractionTiny
+		}
+
+		c = b + a/c
+		if math.Abs(c) < continuedFractionTiny {
+			c = continuedFractionTiny
+		}
+
+		d = 1 / d
+		delta := c * d
+		f *= delta
+
+		if math.Abs(delta-1) < tolerance {
+			return f, nil
+		}
+	}
+
+	return f, fmt.Errorf("continued fraction did not converge within %d iterations", maximumIterations)
+}
+
+// LogGamma returns the natural logarithm of the absolute value of the gamma function.  It is math.Lgamma without
+// the sign, and is accurate to within a few units in the last place.
+func LogGamma(x float64) float64 {
+	lgamma, _ := math.Lgamma(x)
+	return lgamma
+}
+
+// LogBeta returns the natural logarithm of the beta function B(a, b) = Γ(a)Γ(b) / Γ(a + b) for a, b > 0.  Because
+// it is computed from LogGamma, its absolute error is about 1e-15 times the largest of the logarithms involved.
+func LogBeta(a float64, b float64) float64 {
+	if !(a > 0 && b > 0) {
+		return math.NaN()
+	}
+
+	return LogGamma(a) + LogGamma(b) - LogGamma(a+b)
+}
+
+// Digamma returns ψ(x), the logarithmic derivative of the gamma function.  Arguments less than 10 are shifted
+// upwards using the recurrence ψ(x + 1) = ψ(x) + 1 / x (and negative arguments first reflected using
+// ψ(1 - x) - ψ(x) = π cot(πx)), after which the asymptotic series is accurate to better than 1e-16.  The absolute
+// error for positive arguments is about 1e-15; the relative error degrades only near the zero at x ≈ 1.4616 and,
+// for negative arguments, near the poles.  Digamma returns NaN at zero and the negative integers.
+func Digamma(x float64) float64 {
+	switch {
+	case math.IsNaN(x) || math.IsInf(x, -1):
+		return math.NaN()
+	case math.IsInf(x, 1):
+		return x
+	case x <= 0 && x == math.Floor(x):
+		return math.NaN()
+	}
+
+	result := float64(0)
+
+	if x < 0 {
+		result = -math.Pi / math.Tan(math.Pi*x)
+		x = 1 - x
+	}
+
+	for x < 10 {
+		result -= 1 / x
+		x++
+	}
+
+	// the terms are B_2k / (2k x^2k) for k = 1, ..., 7
+	f := 1 / (x * x)
+	series := f * (1.0/12 - f*(1.0/120-f*(1.0/252-f*(1.0/240-f*(1.0/132-f*(691.0/32760-f/12))))))
+
+	return result + math.Log(x) - 0.5/x - series
+}
+
+// ErfInverse returns the inverse of the error function, so that math.Erf(ErfInverse(x)) = x for x in [-1, 1].  It
+// is math.Erfinv, which uses the rational approximations of Wichura (1988, algorithm AS 241) and is accurate to
+// about 1e-16 relative.
+func ErfInverse(x float64) float64 {
+	return math.Erfinv(x)
+}
+
+// ErfcInverse returns the inverse of the complementary error function, so that math.Erfc(ErfcInverse(x)) = x for x
+// in [0, 2].  Because math.Erfcinv(x) is computed as math.Erfinv(1 - x), it loses relative precision as x approaches
+// 0, so its result (or, below 1e-12, an asymptotic approximation) is refined with Newton's method.  The result is
+// accurate to within a few units in the last place for x down to 1e-300.
+func ErfcInverse(x float64) float64 {
+	if !(x > 0 && x < 2) {
+		return math.Erfcinv(x)
+	}
+
+	if x > 1 {
+		return -ErfcInverse(2 - x)
+	}
+
+	var y float64
+	if x > 1e-12 {
+		y = math.Erfcinv(x)
+	} else {
+		// erfc(y) is approximately exp(-y^2) / (y sqrt(π)) for large y
+		y = math.Sqrt(-math.Log(x))
+		for i := 0; i < 2; i++ {
+			y = math.Sqrt(-math.Log(x * y * math.SqrtPi))
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		step := (math.Erfc(y) - x) / (2 / math.SqrtPi * math.Exp(-y*y))
+		y += step
+
+		if math.Abs(step) <= 1e-16*y {
+			break
+		}
+	}
+
+	return y
+}
+
+// RegularizedIncompleteBeta computes I_x(a, b) for a, b > 0 using the continued fraction of Numerical Recipes §6.4,
+// evaluated on whichever of x and 1 - x converges more quickly.  The relative error in the smaller of I_x(a, b) and
+// 1 - I_x(a, b) is about 5e-15 (a + b), and the absolute error is no larger, the growth
+// with a + b coming from the logarithm of the beta function.  It returns NaN if a or b is not positive.
+func RegularizedIncompleteBeta(a float64, b float64, x float64) float64 {
+	if math.IsNaN(x) || !(a > 0 && b > 0) {
+		return math.NaN()
+	}
+
+	if x <= 0 {
+		return 0
+	}
+
+	if x >= 1 {
+		return 1
+	}
+
+	logOfFrontFactor := a*math.Log(x) + b*math.Log1p(-x) - LogBeta(a, b)
+
+	if x < (a+1)/(a+b+2) {
+		return math.Exp(logOfFrontFactor) * incompleteBetaContinuedFraction(a, b, x) / a
+	}
+
+	return 1 - math.Exp(logOfFrontFactor)*incompleteBetaContinuedFraction(b, a, 1-x)/b
+}
+
+// incompleteBetaContinuedFraction evaluates 1 / (1 + d1 / (1 + d2 / (1 + ...))), where the odd terms are
+// d_2m+1 = -(a + m)(a + b + m)x / ((a + 2m)(a + 2m + 1)) and the even terms d_2m = m(b - m)x / ((a + 2m - 1)(a + 2m)).
+func incompleteBetaContinuedFraction(a float64, b float64, x float64) float64 {
+	value, _ := EvaluateContinuedFraction(0, func(n int) (float64, float64) {
+		if n == 1 {
+			return 1, 1
+		}
+
+		m := float64((n - 1) / 2)
+		if n%2 == 0 {
+			return -(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1)), 1
+		}
+
+		return m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m)), 1
+	}, continuedFractionEpsilon, 2*continuedFractionMaximumIterations)
+
+	return value
+}
+
+// InverseRegularizedIncompleteBeta returns x such that I_x(a, b) = p, using the initial approximation and Halley
+// iteration of Numerical Recipes §6.14.  The result is accurate to about 1e-14 relative, except where I_x(a, b) is
+// so flat that x is poorly determined by p.  It returns NaN if a or b is not positive or p is not in [0, 1].
+func InverseRegularizedIncompleteBeta(a float64, b float64, p float64) float64 {
+	if !(a > 0 && b > 0) || probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	if p == 0 {
+		return 0
+	}
+
+	if p == 1 {
+		return 1
+	}
+
+	var x float64
+
+	if a >= 1 && b >= 1 {
+		pp := p
+		if p >= 0.5 {
+			pp = 1 - p
+		}
+
+		t := math.Sqrt(-2 * math.Log(pp))
+		x = (2.30753+t*0.27061)/(1+t*(0.99229+t*0.04481)) - t
+		if p < 0.5 {
+			x = -x
+		}
+
+		al := (x*x - 3) / 6
+		h := 2 / (1/(2*a-1) + 1/(2*b-1))
+		w := (x * math.Sqrt(al+h) / h) - (1/(2*b-1)-1/(2*a-1))*(al+5.0/6.0-2/(3*h))
+		x = a / (a + b*math.Exp(2*w))
+	} else {
+		lna := math.Log(a / (a + b))
+		lnb := math.Log(b / (a + b))
+		t := math.Exp(a*lna) / a
+		u := math.Exp(b*lnb) / b
+		w := t + u
+
+		if p < t/w {
+			x = math.Pow(a*w*p, 1/a)
+		} else {
+			x = 1 - math.Pow(b*w*(1-p), 1/b)
+		}
+	}
+
+	negativeLogBeta := -LogBeta(a, b)
+
+	for j := 0; j < 30; j++ {
+		if x == 0 || x == 1 {
+			return x
+		}
+
+		err := RegularizedIncompleteBeta(a, b, x) - p
+		t := math.Exp((a-1)*math.Log(x) + (b-1)*math.Log1p(-x) + negativeLogBeta)
+		u := err / t
+
+		t = u / (1 - 0.5*math.Min(1, u*((a-1)/x-(b-1)/(1-x))))
+		x -= t
+
+		if x <= 0 {
+			x = 0.5 * (x + t)
+		}
+		if x >= 1 {
+			x = 0.5 * (x + t + 1)
+		}
+
+		if math.Abs(t) < 1e-14*x && j > 0 {
+			break
+		}
+	}
+
+	return x
+}
+
+// RegularizedLowerIncompleteGamma computes P(a, x) for a > 0 using the series of Numerical Recipes §6.2 when
+// x < a + 1, and otherwise one minus the continued fraction for Q(a, x).  The absolute error is about 1e-15 for a up
+// to 20, and beyond that the relative error is about 1e-16 (|x - a| + sqrt(a)), because the number of terms needed
+// grows with the square root of a.  It returns NaN if a is not positive, or if the sums do not converge, which can
+// happen only for a beyond 1e12 with x near a.
+func RegularizedLowerIncompleteGamma(a float64, x float64) float64 {
+	if math.IsNaN(x) || !(a > 0) {
+		return math.NaN()
+	}
+
+	if x <= 0 {
+		return 0
+	}
+
+	if math.IsInf(x, 1) {
+		return 1
+	}
+
+	if x < a+1 {
+		return incompleteGammaSeries(a, x)
+	}
+
+	return 1 - incompleteGammaContinuedFraction(a, x)
+}
+
+// RegularizedUpperIncompleteGamma computes Q(a, x) = 1 - P(a, x) for a > 0 without the loss of precision in
+// subtracting from 1 when Q(a, x) is small, so that its relative error in the upper tail is about 1e-14 for a up
+// to 20, and about 1e-16 (|x - a| + sqrt(a)) beyond that.  It returns NaN where RegularizedLowerIncompleteGamma does.
+func RegularizedUpperIncompleteGamma(a float64, x float64) float64 {
+	if math.IsNaN(x) || !(a > 0) {
+		return math.NaN()
+	}
+
+	if x <= 0 {
+		return 1
+	}
+
+	if math.IsInf(x, 1) {
+		return 0
+	}
+
+	if x < a+1 {
+		return 1 - incompleteGammaSeries(a, x)
+	}
+
+	return incompleteGammaContinuedFraction(a, x)
+}
+
+// incompleteGammaLogOfFrontFactor returns the logarithm of exp(-x) x^a / Γ(a).  For large a, the terms of
+// -x + a log(x) - log Γ(a) nearly cancel, so it is computed instead as a (log(1 + t) - t) + log(a / 2π) / 2 minus the
+// Stirling series for log Γ(a), where t = (x - a) / a, which leaves an absolute error of about 1e-16 |x - a|.
+func incompleteGammaLogOfFrontFactor(a float64, x float64) float64 {
+	if a < 20 {
+		return -x + a*math.Log(x) - LogGamma(a)
+	}
+
+	t := (x - a) / a
+	inverseOfASquared := 1 / (a * a)
+	stirlingCorrection := (1.0/12 - inverseOfASquared*(1.0/360-inverseOfASquared*(1.0/1260-inverseOfASquared*(1.0/1680-inverseOfASquared/1188)))) / a
+
+	return a*(math.Log1p(t)-t) + 0.5*math.Log(a/(2*math.Pi)) - stirlingCorrection
+}
+
+// incompleteGammaMaximumIterations returns the number of terms of the series or the continued fraction to evaluate
+// before giving up.  Near x = a the terms of both fall off only like exp(-n²/2a), so the number needed grows with the
+// square root of a.  The growth is capped at a = 1e12, beyond which the sums may not converge when x is near a.
+func incompleteGammaMaximumIterations(a float64) int {
+	return continuedFractionMaximumIterations*10 + int(20*math.Sqrt(math.Min(a, 1e12)))
+}
+
+// incompleteGammaSeries evaluates P(a, x) using the series exp(-x) x^a / Γ(a) sum_{n>=0} x^n / (a (a + 1) ... (a + n)),
+// returning NaN if the series does not converge.
+func incompleteGammaSeries(a float64, x float64) float64 {
+	term := 1 / a
+	sum := term
+
+	for n := 1; n <= incompleteGammaMaximumIterations(a); n++ {
+		term *= x / (a + float64(n))
+		sum += term
+
+		if math.Abs(term) < math.Abs(sum)*continuedFractionEpsilon {
+			return sum * math.Exp(incompleteGammaLogOfFrontFactor(a, x))
+		}
+	}
+
+	return math.NaN()
+}
+
+// incompleteGammaContinuedFraction evaluates Q(a, x) using the continued fraction
+// 1 / (x + 1 - a - 1(1 - a) / (x + 3 - a - 2(2 - a) / (x + 5 - a - ...))), returning NaN if it does not converge.
+func incompleteGammaContinuedFraction(a float64, x float64) float64 {
+	value, err := EvaluateContinuedFraction(0, func(n int) (float64, float64) {
+		if n == 1 {
+			return 1, x + 1 - a
+		}
+
+		i := float64(n - 1)
+		return -i * (i - a), x + 2*i + 1 - a
+	}, continuedFractionEpsilon, incompleteGammaMaximumIterations(a))
+	if err != nil {
+		return math.NaN()
+	}
+
+	return math.Exp(incompleteGammaLogOfFrontFactor(a, x)) * value
+}
+
+// InverseRegularizedLowerIncompleteGamma returns x such that P(a, x) = p, using the initial approximation and
+// Halley iteration of Numerical Recipes §6.2.1.  The result is accurate to about 1e-14 relative.  It returns NaN
+// if a is not positive or p is not in [0, 1], and +Inf if p is 1.
+func InverseRegularizedLowerIncompleteGamma(a float64, p float64) float64 {
+	if !(a > 0) || probabilityIsNotValid(p) {
+		return math.NaN()
+	}
+
+	if p == 0 {
+		return 0
+	}
+
+	if p == 1 {
+		return math.Inf(1)
+	}
+
+	lgammaA := LogGamma(a)
+	a1 := a - 1
+
+	var x, logOfA1, factor float64
+
+	if a > 1 {
+		logOfA1 = math.Log(a1)
+		factor = math.Exp(a1*(logOfA1-1) - lgammaA)
+
+		pp := p
+		if p >= 0.5 {
+			pp = 1 - p
+		}
+
+		t := math.Sqrt(-2 * math.Log(pp))
+		x = (2.30753+t*0.27061)/(1+t*(0.99229+t*0.04481)) - t
+		if p < 0.5 {
+			x = -x
+		}
+
+		x = math.Max(1e-3, a*math.Pow(1-1/(9*a)-x/(3*math.Sqrt(a)), 3))
+	} else {
+		t := 1 - a*(0.253+a*0.12)
+		if p < t {
+			x = math.Pow(p/t, 1/a)
+		} else {
+			x = 1 - math.Log(1-(p-t)/(1-t))
+		}
+	}
+
+	for j := 0; j < 30; j++ {
+		if x <= 0 {
+			return 0
+		}
+
+		err := RegularizedLowerIncompleteGamma(a, x) - p
+
+		var t float64
+		if a > 1 {
+			t = factor * math.Exp(-(x-a1)+a1*(math.Log(x)-logOfA1))
+		} else {
+			t = math.Exp(-x + a1*math.Log(x) - lgammaA)
+		}
+
+		u := err / t
+		t = u / (1 - 0.5*math.Min(1, u*((a-1)/x-1)))
+		x -= t
+
+		if x <= 0 {
+			x = 0.5 * (x + t)
+		}
+
+		if math.Abs(t) < 1e-14*x {
+			break
+		}
+	}
+
+	return x
+}
+
+func standardNormalCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+func standardNormalQuantile(p float64) float64 {
+	return -math.Sqrt2 * ErfcInverse(2*p)
+}
+
+func studentTCDF(t float64, degreesOfFreedom float64) float64 {
+	if math.IsInf(t, 0) {
+		if t > 0 {
+			return 1
+		}
+		return 0
+	}
+
+	tailProbability := 0.5 * RegularizedIncompleteBeta(degreesOfFreedom/2, 0.5, degreesOfFreedom/(degreesOfFreedom+t*t))
+
+	if t > 0 {
+		return 1 - tailProbability
+	}
+
+	return tailProbability
+}
+
+func studentTQuantile(p float64, degreesOfFreedom float64) float64 {
+	switch {
+	case math.IsNaN(p):
+		return math.NaN()
+	case p <= 0:
+		return math.Inf(-1)
+	case p >= 1:
+		return math.Inf(1)
+	case p == 0.5:
+		return 0
+	}
+
+	twoSidedTailProbability := 2 * math.Min(p, 1-p)
+
+	var magnitude float64
+	if twoSidedTailProbability < 0.5 {
+		x := InverseRegularizedIncompleteBeta(degreesOfFreedom/2, 0.5, twoSidedTailProbability)
+		magnitude = math.Sqrt(degreesOfFreedom * (1 - x) / x)
+	} else {
+		y := InverseRegularizedIncompleteBeta(0.5, degreesOfFreedom/2, 1-twoSidedTailProbability)
+		magnitude = math.Sqrt(degreesOfFreedom * y / (1 - y))
+	}
+
+	if p < 0.5 {
+		return -magnitude
+	}
+
+	return magnitude
+}
@@ -0,0 +1,211 @@
+package stats_test
+
+import (
+	"math"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+// Euler's constant, -ψ(1)
+const eulerMascheroni = 0.57721566490153286060651209008240243
+
+func relativeError(got float64, expected float64) float64 {
+	if expected == 0 {
+		return math.Abs(got)
+	}
+	return math.Abs(got/expected - 1)
+}
+
+func TestRegularizedIncompleteBeta(t *testing.T) {
+	// for integer a and b, I_x(a, b) is a binomial tail probability, which was computed exactly using rationals
+	for testIndex, testCase := range []struct {
+		a, b, x  float64
+		expected float64
+	}{
+		{1, 1, 0.3, 0.3},
+		{2, 5, 0.3, 0.579825},
+		{5, 2, 0.9, 0.885735},
+		{10, 10, 0.45, 0.32896408757839224},
+		{30, 70, 0.2, 0.009692085749007038},
+		{200, 300, 0.41, 0.6776281647721794},
+		{1, 50, 0.01, 0.39499393286246337},
+		{3, 400, 0.02, 0.9872924826327013},
+		{500, 500, 0.48, 0.10291752730699591},
+		{8, 3, 0.05, 1.605078125e-09},
+	} {
+		got := stats.RegularizedIncompleteBeta(testCase.a, testCase.b, testCase.x)
+		if err := relativeError(got, testCase.expected); err > 5e-15*(testCase.a+testCase.b) {
+			t.Errorf("on test with index (%d), expected I_%g(%g, %g) = (%.17g), got (%.17g)", testIndex, testCase.x, testCase.a, testCase.b, testCase.expected, got)
+		}
+
+		if p := stats.InverseRegularizedIncompleteBeta(testCase.a, testCase.b, got); relativeError(p, testCase.x) > 1e-12 {
+			t.Errorf("on test with index (%d), expected inverse of I_x(%g, %g) = (%.17g) to be (%g), got (%.17g)", testIndex, testCase.a, testCase.b, got, testCase.x, p)
+		}
+	}
+
+	// the symmetry relation I_x(a, b) = 1 - I_1-x(b, a) for non-integer parameters
+	for _, x := range []float64{0.01, 0.2, 0.5, 0.77, 0.999} {
+		if got, symmetric := stats.RegularizedIncompleteBeta(0.3, 7.5, x), 1-stats.RegularizedIncompleteBeta(7.5, 0.3, 1-x); math.Abs(got-symmetric) > 1e-14 {
+			t.Errorf("expected I_%g(0.3, 7.5) (%.17g) to equal 1 - I_%g(7.5, 0.3) (%.17g)", x, got, 1-x, symmetric)
+		}
+	}
+
+	if !math.IsNaN(stats.RegularizedIncompleteBeta(0, 1, 0.5)) || !math.IsNaN(stats.InverseRegularizedIncompleteBeta(1, 1, 2)) {
+		t.Errorf("expected NaN for invalid parameters")
+	}
+}
+
+func TestRegularizedIncompleteGamma(t *testing.T) {
+	// for integer a, Q(a, x) = exp(-x) sum_{k<a} x^k / k!, which was computed to 50 digits
+	for testIndex, testCase := range []struct {
+		a, x          float64
+		expectedLower float64
+		expectedUpper float64
+	}{
+		{1, 0.5, 0.3934693402873666, 0.6065306597126334},
+		{3, 2, 0.32332358381693654, 0.6766764161830635},
+		{10, 5, 0.03182805730620481, 0.9681719426937951},
+		{10, 15, 0.9301463393005902, 0.06985366069940976},
+		{50, 40, 0.07033506665939496, 0.9296649333406051},
+		{100, 130, 0.9972495916326934, 0.002750408367306526},
+		{5, 0.1, 7.667801686189309e-08, 0.9999999233219832},
+		{300, 260, 0.00816671622181907, 0.9918332837781809},
+		{1000, 1030, 0.828911903882394, 0.17108809611760603},
+		{20, 60, 0.9999999993648082, 6.351918340378976e-10},
+	} {
+		lower := stats.RegularizedLowerIncompleteGamma(testCase.a, testCase.x)
+		upper := stats.RegularizedUpperIncompleteGamma(testCase.a, testCase.x)
+
+		if math.Min(lower, upper) == lower && relativeError(lower, testCase.expectedLower) > 1e-15*math.Max(10, testCase.a) ||
+			math.Min(lower, upper) == upper && relativeError(upper, testCase.expectedUpper) > 1e-15*math.Max(10, testCase.a) ||
+			math.Abs(lower-testCase.expectedLower) > 1e-16*math.Max(10, testCase.a) || math.Abs(upper-testCase.expectedUpper) > 1e-16*math.Max(10, testCase.a) {
+			t.Errorf("on test with index (%d), expected P(%g, %g) (%.17g) and Q (%.17g), got (%.17g) and (%.17g)", testIndex, testCase.a, testCase.x, testCase.expectedLower, testCase.expectedUpper, lower, upper)
+		}
+
+		if x := stats.InverseRegularizedLowerIncompleteGamma(testCase.a, lower); lower < 1-1e-9 && relativeError(x, testCase.x) > 1e-12 {
+			t.Errorf("on test with index (%d), expected inverse of P(%g, x) = (%.17g) to be (%g), got (%.17g)", testIndex, testCase.a, lower, testCase.x, x)
+		}
+	}
+
+	// P(1/2, x) = erf(sqrt(x))
+	for _, x := range []float64{0.001, 0.3, 1, 4, 12} {
+		if got, expected := stats.RegularizedLowerIncompleteGamma(0.5, x), math.Erf(math.Sqrt(x)); math.Abs(got-expected) > 1e-15 {
+			t.Errorf("expected P(0.5, %g) (%.17g), got (%.17g)", x, expected, got)
+		}
+	}
+}
+
+func TestRegularizedIncompleteGammaOfLargeA(t *testing.T) {
+	// computed to 60 digits from the series exp(-x) x^a / Γ(a + 1) sum_{n>=0} x^n / ((a + 1) ... (a + n)), with log Γ
+	// from the Stirling series
+	for testIndex, testCase := range []struct {
+		a, x          float64
+		expectedLower float64
+		expectedUpper float64
+	}{
+		{1e4, 1e4, 0.5013298083399552, 0.4986701916600448},
+		{1e4, 10200, 0.9767126778664011, 0.023287322133598805},
+		{1e4, 11000, 1, 1.6928531496469328e-22},
+		{1e6, 1e6, 0.5001329807608725, 0.4998670192391274},
+		{1e6, 997000, 0.0013381041673135997, 0.9986618958326864},
+		{1e6, 1003000, 0.9986382593537824, 0.0013617406462175915},
+		{1e6, 990000, 5.446644693010809e-24, 1},
+		{1e6, 1010000, 1, 1.060699747758691e-23},
+		{1e8, 1e8, 0.5000132980760141, 0.49998670192398587},
+		{1e8, 99950000, 2.854642139958626e-07, 0.999999714535786},
+	} {
+		lower := stats.RegularizedLowerIncompleteGamma(testCase.a, testCase.x)
+		upper := stats.RegularizedUpperIncompleteGamma(testCase.a, testCase.x)
+		tolerance := 1e-16 * (math.Abs(testCase.x-testCase.a) + math.Sqrt(testCase.a))
+
+		if relativeError(math.Min(lower, upper), math.Min(testCase.expectedLower, testCase.expectedUpper)) > tolerance ||
+			math.Abs(lower-testCase.expectedLower) > tolerance || math.Abs(upper-testCase.expectedUpper) > tolerance {
+			t.Errorf("on test with index (%d), expected P(%g, %g) (%.17g) and Q (%.17g), got (%.17g) and (%.17g)", testIndex, testCase.a, testCase.x, testCase.expectedLower, testCase.expectedUpper, lower, upper)
+		}
+	}
+
+	chiSquared, _ := stats.NewChiSquaredDistribution(2e6)
+	if got := chiSquared.CDF(2e6); math.Abs(got-0.5001329807608725) > 1e-12 {
+		t.Errorf("expected ChiSquared(2e6) CDF(2e6) (0.5001329807608725), got (%.17g)", got)
+	}
+
+	// P(X <= 999999) = Q(1000000, 1e6) < 0.5 < P(X <= 1000000) = Q(1000001, 1e6)
+	poisson, _ := stats.NewPoissonDistribution(1e6)
+	if got := poisson.Quantile(0.5); got != 1e6 {
+		t.Errorf("expected Poisson(1e6) Quantile(0.5) (1000000), got (%f)", got)
+	}
+}
+
+func TestDigamma(t *testing.T) {
+	for testIndex, testCase := range []struct {
+		x        float64
+		expected float64
+	}{
+		{1, -eulerMascheroni},
+		{0.5, -eulerMascheroni - 2*math.Ln2},
+		{2, 1 - eulerMascheroni},
+		{10, 1 + 1.0/2 + 1.0/3 + 1.0/4 + 1.0/5 + 1.0/6 + 1.0/7 + 1.0/8 + 1.0/9 - eulerMascheroni},
+		{3.5, -eulerMascheroni - 2*math.Ln2 + 2 + 2.0/3 + 2.0/5},
+		// ψ(1 - x) - ψ(x) = π cot(πx), so ψ(-0.5) = ψ(1.5) - π cot(-π/2) ... and cot(-π/2) = 0
+		{-0.5, -eulerMascheroni - 2*math.Ln2 + 2},
+		{1e6, math.Log(1e6) - 0.5e-6 - 1.0/12e12},
+	} {
+		if got := stats.Digamma(testCase.x); math.Abs(got-testCase.expected) > 2e-15*math.Max(1, math.Abs(testCase.expected)) {
+			t.Errorf("on test with index (%d), expected ψ(%g) (%.17g), got (%.17g)", testIndex, testCase.x, testCase.expected, got)
+		}
+	}
+
+	// the derivative of LogGamma
+	for _, x := range []float64{0.25, 3.7, 42} {
+		h := 1e-5
+		if got, numerical := stats.Digamma(x), (stats.LogGamma(x+h)-stats.LogGamma(x-h))/(2*h); math.Abs(got-numerical) > 1e-8 {
+			t.Errorf("expected ψ(%g) near (%.12f), got (%.12f)", x, numerical, got)
+		}
+	}
+
+	if !math.IsNaN(stats.Digamma(0)) || !math.IsNaN(stats.Digamma(-3)) {
+		t.Errorf("expected NaN at the poles of ψ")
+	}
+}
+
+func TestErfInverse(t *testing.T) {
+	for _, x := range []float64{-0.999, -0.5, 0, 1e-10, 0.3, 0.9999} {
+		if got := math.Erf(stats.ErfInverse(x)); math.Abs(got-x) > 1e-15 {
+			t.Errorf("expected erf(ErfInverse(%g)) (%g), got (%.17g)", x, x, got)
+		}
+	}
+
+	for _, x := range []float64{1e-300, 1e-100, 1e-13, 1e-10, 0.5, 1, 1.7, 2 - 1e-12} {
+		// a relative error of e in y changes erfc(y) by a relative 2 y^2 e
+		y := stats.ErfcInverse(x)
+		if got := math.Erfc(y); relativeError(got, x) > 1e-15*math.Max(1, y*y) {
+			t.Errorf("expected erfc(ErfcInverse(%g)) (%g), got (%.17g)", x, x, got)
+		}
+	}
+}
+
+func TestEvaluateContinuedFraction(t *testing.T) {
+	// sqrt(2) = 1 + 1 / (2 + 1 / (2 + ...))
+	got, err := stats.EvaluateContinuedFraction(1, func(int) (float64, float64) { return 1, 2 }, 1e-15, 100)
+	if err != nil || math.Abs(got-math.Sqrt2) > 1e-15 {
+		t.Errorf("expected (%.17g), got (%.17g) with error (%v)", math.Sqrt2, got, err)
+	}
+
+	// tan(x) = x / (1 - x^2 / (3 - x^2 / (5 - ...)))
+	x := 0.7
+	got, err = stats.EvaluateContinuedFraction(0, func(n int) (float64, float64) {
+		if n == 1 {
+			return x, 1
+		}
+		return -x * x, float64(2*n - 1)
+	}, 1e-16, 100)
+	if err != nil || relativeError(got, math.Tan(x)) > 1e-15 {
+		t.Errorf("expected (%.17g), got (%.17g) with error (%v)", math.Tan(x), got, err)
+	}
+
+	// the harmonic series as a continued fraction does not converge
+	if _, err := stats.EvaluateContinuedFraction(0, func(n int) (float64, float64) { return 1, 0 }, 1e-15, 50); err == nil {
+		t.Errorf("on a non-converging continued fraction, expected error, got none")
+	}
+}
@@ -5,14 +5,35 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"sync"
 )
 
+// A StatisticalSampleSet is a set of samples against which statistics may be computed.  Values may be appended
+// to the set after it is created using Add() and AddMany().  The count, sum, minimum and maximum are updated
+// on each addition, while ordered statistics (e.g., the median) only cause the set to be re-sorted when they are
+// requested after an addition.
 type StatisticalSampleSet struct {
-	valuesSortedInAscendingOrder []float64
-	sumOfAllValuesInTheSet       float64
-	distributionTracker          *valueDistributionTracker
-	modeTracker                  *modalTracker
-	varianceTracker              *varianceTracker
+	mutex                           sync.Mutex
+	valuesSortedInAscendingOrder    []float64
+	valuesHaveBeenAddedSinceSorting bool
+	sumOfAllValuesInTheSet          float64
+	minimumValueInTheSet            float64
+	maximumValueInTheSet            float64
+	distributionTracker             *valueDistributionTracker
+	modeTracker                     *modalTracker
+	varianceTracker                 *varianceTracker
+}
+
+// A SampleSetSummary provides summary statistics for a set of values.  It is implemented by StatisticalSampleSet,
+// which computes them exactly, and by the summaries of sketches, which compute them approximately, so that callers
+// may switch between exact and approximate modes.
+type SampleSetSummary interface {
+	Count() int
+	Minimum() float64
+	Maximum() float64
+	Mean() float64
+	Median() float64
+	ValueNearestPercentile(percentile int) float64
 }
 
 var ErrorFloat64Overflow = errors.New("float64 overflow")
@@ -46,6 +67,8 @@ func MakeStatisticalSampleSetFrom(samples []float64) (*StatisticalSampleSet, err
 	set := &StatisticalSampleSet{
 		valuesSortedInAscendingOrder: copyOfSamples,
 		sumOfAllValuesInTheSet:       sum,
+		minimumValueInTheSet:         copyOfSamples[0],
+		maximumValueInTheSet:         copyOfSamples[len(copyOfSamples)-1],
 		distributionTracker:          distributionTracker,
 		modeTracker:                  modeTracker,
 	}
@@ -55,72 +78,190 @@ func MakeStatisticalSampleSetFrom(samples []float64) (*StatisticalSampleSet, err
 	return set, nil
 }
 
+// Add appends a single value to the set.  If adding the value would cause the sum of the set to overflow or underflow,
+// an error is returned and the set is unchanged.
+func (set *StatisticalSampleSet) Add(value float64) error {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	if err := set.errorIfSumWouldOverflowAfterAdding([]float64{value}); err != nil {
+		return err
+	}
+
+	set.addValue(value)
+
+	return nil
+}
+
+// AddMany appends each of the provided values to the set.  If adding the values would cause the sum of the set to overflow
+// or underflow, an error is returned and none of the values are added.
+func (set *StatisticalSampleSet) AddMany(values []float64) error {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	if err := set.errorIfSumWouldOverflowAfterAdding(values); err != nil {
+		return err
+	}
+
+	for _, v := range values {
+		set.addValue(v)
+	}
+
+	return nil
+}
+
+func (set *StatisticalSampleSet) errorIfSumWouldOverflowAfterAdding(values []float64) error {
+	sum := set.sumOfAllValuesInTheSet
+	for _, v := range values {
+		sum = sum + v
+	}
+
+	if sum == math.Inf(1) {
+		return ErrorFloat64Overflow
+	}
+
+	if sum == math.Inf(-1) {
+		return ErrorFloat64Underflow
+	}
+
+	return nil
+}
+
+func (set *StatisticalSampleSet) addValue(value float64) {
+	meanBeforeAddingValue := set.mean()
+
+	set.valuesSortedInAscendingOrder = append(set.valuesSortedInAscendingOrder, value)
+	set.valuesHaveBeenAddedSinceSorting = true
+	set.sumOfAllValuesInTheSet = set.sumOfAllValuesInTheSet + value
+
+	if value < set.minimumValueInTheSet {
+		set.minimumValueInTheSet = value
+	}
+	if value > set.maximumValueInTheSet {
+		set.maximumValueInTheSet = value
+	}
+
+	set.distributionTracker.AddValue(value)
+	set.modeTracker.Invalidate()
+	set.varianceTracker.AddDataPoint(value, meanBeforeAddingValue, set.mean())
+}
+
+// sortedValues returns the values in the set in ascending order, sorting them first if values have been
+// added since they were last sorted.  The values are sorted in a copy so that slices previously returned by
+// this method are never modified.  The caller must hold the set mutex.
+func (set *StatisticalSampleSet) sortedValues() []float64 {
+	if set.valuesHaveBeenAddedSinceSorting {
+		copyOfValues := make([]float64, len(set.valuesSortedInAscendingOrder))
+		copy(copyOfValues, set.valuesSortedInAscendingOrder)
+		sort.Float64s(copyOfValues)
+
+		set.valuesSortedInAscendingOrder = copyOfValues
+		set.valuesHaveBeenAddedSinceSorting = false
+	}
+
+	return set.valuesSortedInAscendingOrder
+}
+
+// snapshotOfSortedValues returns the values in the set in ascending order.  The returned slice is never modified
+// by the set, so it may be read after the set mutex is released, but it must not be modified by the caller.
+func (set *StatisticalSampleSet) snapshotOfSortedValues() []float64 {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	return set.sortedValues()
+}
+
+func (set *StatisticalSampleSet) Count() int {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	return len(set.valuesSortedInAscendingOrder)
+}
+
 func (set *StatisticalSampleSet) Minimum() float64 {
-	return set.valuesSortedInAscendingOrder[0]
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	return set.minimumValueInTheSet
 }
 
 func (set *StatisticalSampleSet) Maximum() float64 {
-	return set.valuesSortedInAscendingOrder[len(set.valuesSortedInAscendingOrder)-1]
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	return set.maximumValueInTheSet
 }
 
 func (set *StatisticalSampleSet) Mean() float64 {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	return set.mean()
+}
+
+func (set *StatisticalSampleSet) mean() float64 {
 	return set.sumOfAllValuesInTheSet / float64(len(set.valuesSortedInAscendingOrder))
 }
 
 func (set *StatisticalSampleSet) Median() float64 {
-	return medianOfAFloatSet(set.valuesSortedInAscendingOrder).computedMedian
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	return medianOfAFloatSet(set.sortedValues()).computedMedian
 }
 
 func (set *StatisticalSampleSet) Mode() (modeFrequencyCount uint, valuesSeenThatManyTimes []float64) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
 	return set.modeTracker.Modes()
 }
 
 func (set *StatisticalSampleSet) Range() float64 {
-	lastElementIndex := len(set.valuesSortedInAscendingOrder) - 1
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
 
-	return set.valuesSortedInAscendingOrder[lastElementIndex] - set.valuesSortedInAscendingOrder[0]
+	return set.maximumValueInTheSet - set.minimumValueInTheSet
 }
 
 func (set *StatisticalSampleSet) SampleVariance() float64 {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	return set.sampleVariance()
+}
+
+func (set *StatisticalSampleSet) sampleVariance() float64 {
 	return set.varianceTracker.Variance() / (float64(len(set.valuesSortedInAscendingOrder)) - 1)
 }
 
 func (set *StatisticalSampleSet) PopulationVariance() float64 {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	return set.populationVariance()
+}
+
+func (set *StatisticalSampleSet) populationVariance() float64 {
 	return set.varianceTracker.Variance() / float64(len(set.valuesSortedInAscendingOrder))
 }
 
 func (set *StatisticalSampleSet) SampleStdev() float64 {
-	return math.Sqrt(set.SampleVariance())
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	return math.Sqrt(set.sampleVariance())
 }
 
 func (set *StatisticalSampleSet) PopulationStdev() float64 {
-	return math.Sqrt(set.PopulationVariance())
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	return math.Sqrt(set.populationVariance())
 }
 
 func (set *StatisticalSampleSet) InterQuartileRange() (q1 float64, q3 float64, iqr float64) {
-	switch len(set.valuesSortedInAscendingOrder) {
-	case 1:
-		return set.valuesSortedInAscendingOrder[0], set.valuesSortedInAscendingOrder[0], 0.0
-
-	case 2:
-		return set.valuesSortedInAscendingOrder[0], set.valuesSortedInAscendingOrder[1], set.valuesSortedInAscendingOrder[1] - set.valuesSortedInAscendingOrder[0]
-	}
-
-	q2MedianInfo := medianOfAFloatSet(set.valuesSortedInAscendingOrder)
-
-	var q1MedianInfo, q3MedianInfo *medianValueAndBracketInfo
-
-	if q2MedianInfo.medianIsBetweenTwoValues {
-		q1MedianInfo = medianOfAFloatSet(set.valuesSortedInAscendingOrder[0:q2MedianInfo.indexOfMedianRightBracketInSet])
-		q3MedianInfo = medianOfAFloatSet(set.valuesSortedInAscendingOrder[q2MedianInfo.indexOfMedianRightBracketInSet:])
-	} else {
-		q1MedianInfo = medianOfAFloatSet(set.valuesSortedInAscendingOrder[0:q2MedianInfo.indexOfMedianInSet])
-		q3MedianInfo = medianOfAFloatSet(set.valuesSortedInAscendingOrder[q2MedianInfo.indexOfMedianInSet+1:])
-	}
-
-	iqr = q3MedianInfo.computedMedian - q1MedianInfo.computedMedian
-
-	return q1MedianInfo.computedMedian, q3MedianInfo.computedMedian, iqr
+	return set.InterQuartileRangeUsing(QuartileMooreMcCabe)
 }
 
 func (set *StatisticalSampleSet) ValueNearestPercentileWithErrors(percentile int) (float64, error) {
@@ -128,9 +269,14 @@ func (set *StatisticalSampleSet) ValueNearestPercentileWithErrors(percentile int
 		return 0, fmt.Errorf("percentile must be in the range 0..100")
 	}
 
-	floorIndexNearestPercentile := (len(set.valuesSortedInAscendingOrder) - 1) * percentile / 100
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	sortedValues := set.sortedValues()
+
+	floorIndexNearestPercentile := (len(sortedValues) - 1) * percentile / 100
 
-	return set.valuesSortedInAscendingOrder[floorIndexNearestPercentile], nil
+	return sortedValues[floorIndexNearestPercentile], nil
 }
 
 func (set *StatisticalSampleSet) ValueNearestPercentile(percentile int) float64 {
@@ -522,3 +522,143 @@ func TestPercentile(t *testing.T) {
 		}
 	}
 }
+
+type addToSampleSetTestCase struct {
+	initialFloatSet []float64
+	addedFloatSets  [][]float64
+}
+
+func (testCase *addToSampleSetTestCase) RunTest() error {
+	s, err := stats.MakeStatisticalSampleSetFrom(testCase.initialFloatSet)
+	if err != nil {
+		return fmt.Errorf("on MakeStatisticalSampleSetFrom() got error: %s", err.Error())
+	}
+
+	allValues := append([]float64{}, testCase.initialFloatSet...)
+
+	for _, addedFloatSet := range testCase.addedFloatSets {
+		// force the lazily computed statistics to be computed before each addition
+		s.Median()
+		s.Mode()
+		s.SampleVariance()
+
+		if len(addedFloatSet) == 1 {
+			err = s.Add(addedFloatSet[0])
+		} else {
+			err = s.AddMany(addedFloatSet)
+		}
+		if err != nil {
+			return fmt.Errorf("on Add() or AddMany() got error: %s", err.Error())
+		}
+
+		allValues = append(allValues, addedFloatSet...)
+	}
+
+	expected, err := stats.MakeStatisticalSampleSetFrom(allValues)
+	if err != nil {
+		return fmt.Errorf("on MakeStatisticalSampleSetFrom() for all values got error: %s", err.Error())
+	}
+
+	if s.Count() != expected.Count() {
+		return fmt.Errorf("expected Count (%d), got (%d)", expected.Count(), s.Count())
+	}
+
+	for _, comparison := range []struct {
+		name     string
+		expected float64
+		got      float64
+	}{
+		{"Minimum", expected.Minimum(), s.Minimum()},
+		{"Maximum", expected.Maximum(), s.Maximum()},
+		{"Range", expected.Range(), s.Range()},
+		{"Mean", expected.Mean(), s.Mean()},
+		{"Median", expected.Median(), s.Median()},
+		{"ValueNearestPercentile(90)", expected.ValueNearestPercentile(90), s.ValueNearestPercentile(90)},
+	} {
+		if comparison.expected != comparison.got {
+			return fmt.Errorf("expected %s (%f), got (%f)", comparison.name, comparison.expected, comparison.got)
+		}
+	}
+
+	if math.Abs(expected.SampleVariance()-s.SampleVariance()) > 1e-9 {
+		return fmt.Errorf("expected SampleVariance (%f), got (%f)", expected.SampleVariance(), s.SampleVariance())
+	}
+	if math.Abs(expected.PopulationVariance()-s.PopulationVariance()) > 1e-9 {
+		return fmt.Errorf("expected PopulationVariance (%f), got (%f)", expected.PopulationVariance(), s.PopulationVariance())
+	}
+
+	expectedQ1, expectedQ3, expectedIQR := expected.InterQuartileRange()
+	gotQ1, gotQ3, gotIQR := s.InterQuartileRange()
+	if expectedQ1 != gotQ1 || expectedQ3 != gotQ3 || expectedIQR != gotIQR {
+		return fmt.Errorf("expected InterQuartileRange (%f, %f, %f), got (%f, %f, %f)", expectedQ1, expectedQ3, expectedIQR, gotQ1, gotQ3, gotIQR)
+	}
+
+	expectedModeCount, expectedModeValues := expected.Mode()
+	gotModeCount, gotModeValues := s.Mode()
+	if expectedModeCount != gotModeCount {
+		return fmt.Errorf("expected mode frequency count (%d), got (%d)", expectedModeCount, gotModeCount)
+	}
+
+	sort.Float64s(expectedModeValues)
+	sort.Float64s(gotModeValues)
+	if !reflect.DeepEqual(expectedModeValues, gotModeValues) {
+		return fmt.Errorf("expected mode values (%v), got (%v)", expectedModeValues, gotModeValues)
+	}
+
+	return nil
+}
+
+func TestAddToStatisticalSampleSet(t *testing.T) {
+	for testIndex, testCase := range []*addToSampleSetTestCase{
+		{
+			initialFloatSet: []float64{1.0},
+			addedFloatSets:  [][]float64{{2.0}},
+		},
+		{
+			initialFloatSet: []float64{5.0},
+			addedFloatSets:  [][]float64{{-1.0}, {10.0}, {5.0}},
+		},
+		{
+			initialFloatSet: []float64{46, 37, 40, 33, 42},
+			addedFloatSets:  [][]float64{{36, 40, 47}, {34}, {45}},
+		},
+		{
+			initialFloatSet: []float64{0, 1, -1, 5, 3},
+			addedFloatSets:  [][]float64{{1, 15}, {3, 5, 1}, {3, 3, 3}},
+		},
+		{
+			initialFloatSet: []float64{1.90, 3.00, 2.53},
+			addedFloatSets:  [][]float64{{3.71, 2.12, 1.76, 2.71}, {1.39, 4.00, 3.33}},
+		},
+	} {
+		if err := testCase.RunTest(); err != nil {
+			t.Errorf("on test with index (%d): %s", testIndex, err.Error())
+		}
+	}
+}
+
+func TestAddToStatisticalSampleSetErrors(t *testing.T) {
+	h := math.MaxFloat64
+
+	s, err := stats.MakeStatisticalSampleSetFrom([]float64{h})
+	if err != nil {
+		t.Fatalf("on MakeStatisticalSampleSetFrom() got error: %s", err.Error())
+	}
+
+	if err := s.Add(h); err != stats.ErrorFloat64Overflow {
+		t.Errorf("on Add() of float64max to set containing float64max, expected ErrorFloat64Overflow, got (%v)", err)
+	}
+
+	if err := s.AddMany([]float64{1, h}); err != stats.ErrorFloat64Overflow {
+		t.Errorf("on AddMany() including float64max to set containing float64max, expected ErrorFloat64Overflow, got (%v)", err)
+	}
+
+	if s.Count() != 1 {
+		t.Errorf("expected set to be unchanged after failed additions, but Count is (%d)", s.Count())
+	}
+
+	s, _ = stats.MakeStatisticalSampleSetFrom([]float64{-h})
+	if err := s.Add(-h); err != stats.ErrorFloat64Underflow {
+		t.Errorf("on Add() of -1 * float64max to set containing -1 * float64max, expected ErrorFloat64Underflow, got (%v)", err)
+	}
+}
@@ -36,6 +36,19 @@ func (generator *valueDistributionTracker) Map() map[float64]uint {
 	return generator.conditionallyGeneratedMap
 }
 
+// AddValue records an additional value.  If the map has already been generated, the count for the value is
+// incremented, otherwise the value is included when the map is eventually generated.
+func (generator *valueDistributionTracker) AddValue(v float64) {
+	generator.mutex.Lock()
+	defer generator.mutex.Unlock()
+
+	if generator.mapHasBeenGenerated {
+		generator.conditionallyGeneratedMap[v] = generator.conditionallyGeneratedMap[v] + uint(1)
+	} else {
+		generator.sourceValueSet = append(generator.sourceValueSet, v)
+	}
+}
+
 // a modal map is the inverse of a value distribution map.  That is, it is keyed by the number
 // of occurances of a value and points to a list of values that occurred that number of times.
 type modalTracker struct {
@@ -80,11 +93,21 @@ func (tracker *modalTracker) Modes() (numberOfTimesValuesWereSeen uint, valuesSe
 
 	if !tracker.mapHasBeenGenerated {
 		tracker.conditionallyGeneratedMap, tracker.highestFrequencyCount = generateModalMapFromADistributionMap(tracker.valueDistributionTracker.Map())
+		tracker.mapHasBeenGenerated = true
 	}
 
 	return tracker.highestFrequencyCount, tracker.conditionallyGeneratedMap[tracker.highestFrequencyCount]
 }
 
+// Invalidate discards the modal map, if it has been generated, so that it is regenerated from the
+// value distribution map the next time it is requested.
+func (tracker *modalTracker) Invalidate() {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	tracker.mapHasBeenGenerated = false
+}
+
 type varianceTracker struct {
 	mutex                           sync.Mutex
 	haveSummedDataPointVariances    bool
@@ -105,7 +128,7 @@ func (tracker *varianceTracker) Variance() float64 {
 	defer tracker.mutex.Unlock()
 
 	if !tracker.haveSummedDataPointVariances {
-		sampleSetMean := tracker.sampleSetContainerForDataPoints.Mean()
+		sampleSetMean := tracker.sampleSetContainerForDataPoints.mean()
 		for _, dataPoint := range tracker.setOfDataPoints {
 			diff := dataPoint - sampleSetMean
 			tracker.summedDataPointVariances += (diff * diff)
@@ -115,3 +138,83 @@ func (tracker *varianceTracker) Variance() float64 {
 
 	return tracker.summedDataPointVariances
 }
+
+// AddDataPoint records an additional data point.  If the summed variances have already been computed, they are
+// updated using Welford's method, which requires the mean of the containing set before and after the data point
+// was added.  Otherwise, the data point is included when the summed variances are eventually computed.
+func (tracker *varianceTracker) AddDataPoint(dataPoint float64, meanBeforeAddingDataPoint float64, meanAfterAddingDataPoint float64) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	if tracker.haveSummedDataPointVariances {
+		tracker.summedDataPointVariances += (dataPoint - meanBeforeAddingDataPoint) * (dataPoint - meanAfterAddingDataPoint)
+	} else {
+		tracker.setOfDataPoints = append(tracker.setOfDataPoints, dataPoint)
+	}
+}
+
+// a weighted value distribution map is keyed by value and points to the sum of the weights of every
+// occurance of that value.
+type weightedValueDistributionTracker struct {
+	mutex                     sync.Mutex
+	mapHasBeenGenerated       bool
+	sourceValueSet            []float64
+	sourceWeightSet           []float64
+	conditionallyGeneratedMap map[float64]float64
+}
+
+func newWeightedValueDistributionTracker(valueSet []float64, weightSet []float64) *weightedValueDistributionTracker {
+	return &weightedValueDistributionTracker{
+		sourceValueSet:            valueSet,
+		sourceWeightSet:           weightSet,
+		conditionallyGeneratedMap: make(map[float64]float64),
+	}
+}
+
+func (generator *weightedValueDistributionTracker) Map() map[float64]float64 {
+	generator.mutex.Lock()
+	defer generator.mutex.Unlock()
+
+	if !generator.mapHasBeenGenerated {
+		for i, v := range generator.sourceValueSet {
+			generator.conditionallyGeneratedMap[v] += generator.sourceWeightSet[i]
+		}
+
+		generator.mapHasBeenGenerated = true
+	}
+
+	return generator.conditionallyGeneratedMap
+}
+
+type weightedVarianceTracker struct {
+	mutex                                sync.Mutex
+	haveSummedWeightedDataPointVariances bool
+	setOfDataPoints                      []float64
+	weightsOfDataPoints                  []float64
+	weightedMeanOfDataPoints             float64
+	summedWeightedDataPointVariances     float64
+}
+
+func newWeightedVarianceTracker(forTheSetOfValues []float64, withWeights []float64, weightedMean float64) *weightedVarianceTracker {
+	return &weightedVarianceTracker{
+		setOfDataPoints:          forTheSetOfValues,
+		weightsOfDataPoints:      withWeights,
+		weightedMeanOfDataPoints: weightedMean,
+	}
+}
+
+// Variance returns the sum of the weighted squared deviations of each data point from the weighted mean.
+func (tracker *weightedVarianceTracker) Variance() float64 {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	if !tracker.haveSummedWeightedDataPointVariances {
+		for i, dataPoint := range tracker.setOfDataPoints {
+			diff := dataPoint - tracker.weightedMeanOfDataPoints
+			tracker.summedWeightedDataPointVariances += tracker.weightsOfDataPoints[i] * diff * diff
+		}
+		tracker.haveSummedWeightedDataPointVariances = true
+	}
+
+	return tracker.summedWeightedDataPointVariances
+}
@@ -0,0 +1,177 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+)
+
+// A HypothesisAlternative is the alternative hypothesis of a test.  For a two sample test, AlternativeLess is the
+// hypothesis that the first sample is drawn from a population with a smaller location than the second.
+type HypothesisAlternative int
+
+const (
+	AlternativeTwoSided HypothesisAlternative = iota
+	AlternativeLess
+	AlternativeGreater
+)
+
+func (alternative HypothesisAlternative) String() string {
+	switch alternative {
+	case AlternativeTwoSided:
+		return "two-sided"
+	case AlternativeLess:
+		return "less"
+	case AlternativeGreater:
+		return "greater"
+	}
+
+	return fmt.Sprintf("HypothesisAlternative(%d)", int(alternative))
+}
+
+// A TTestResult is the result of one of the Student's t-tests.  MeanDifference is the mean minus the hypothesized
+// mean for a one sample test, the mean of the differences for a paired test, and the mean of the first set minus the
+// mean of the second set for a two sample test.  The confidence interval is for MeanDifference; for one-sided
+// alternatives, one of its bounds is infinite.
+type TTestResult struct {
+	TStatistic              float64
+	DegreesOfFreedom        float64
+	PValue                  float64
+	Alternative             HypothesisAlternative
+	MeanDifference          float64
+	StandardError           float64
+	ConfidenceLevel         float64
+	ConfidenceIntervalLower float64
+	ConfidenceIntervalUpper float64
+}
+
+// OneSampleTTest tests whether the mean of the population from which set is drawn differs from hypothesizedMean.
+// The confidence level must be greater than 0 and less than 1 (e.g., 0.95).
+func OneSampleTTest(set *StatisticalSampleSet, hypothesizedMean float64, alternative HypothesisAlternative, confidenceLevel float64) (*TTestResult, error) {
+	if err := errorIfHypothesisTestParametersAreNotValid(alternative, confidenceLevel); err != nil {
+		return nil, err
+	}
+
+	n := float64(set.Count())
+	if n < 2 {
+		return nil, fmt.Errorf("there must be at least two samples in the set")
+	}
+
+	standardError := math.Sqrt(set.SampleVariance() / n)
+
+	return computeTTestResult(set.Mean()-hypothesizedMean, standardError, n-1, alternative, confidenceLevel)
+}
+
+// PairedTTest tests whether the mean of the differences first[i] - second[i] differs from zero.  Because a
+// StatisticalSampleSet does not retain the order in which its values were added, the paired samples are supplied
+// as slices, which must be of the same length.
+func PairedTTest(first []float64, second []float64, alternative HypothesisAlternative, confidenceLevel float64) (*TTestResult, error) {
+	if len(first) != len(second) {
+		return nil, fmt.Errorf("paired samples must have the same number of values")
+	}
+
+	differences := make([]float64, len(first))
+	for i := range first {
+		differences[i] = first[i] - second[i]
+	}
+
+	set, err := MakeStatisticalSampleSetFrom(differences)
+	if err != nil {
+		return nil, err
+	}
+
+	return OneSampleTTest(set, 0, alternative, confidenceLevel)
+}
+
+// StudentTTest tests whether the means of the populations from which a and b are drawn differ, assuming that the
+// populations have the same variance.
+func StudentTTest(a *StatisticalSampleSet, b *StatisticalSampleSet, alternative HypothesisAlternative, confidenceLevel float64) (*TTestResult, error) {
+	if err := errorIfHypothesisTestParametersAreNotValid(alternative, confidenceLevel); err != nil {
+		return nil, err
+	}
+
+	n1, n2 := float64(a.Count()), float64(b.Count())
+	if n1 < 2 || n2 < 2 {
+		return nil, fmt.Errorf("there must be at least two samples in each set")
+	}
+
+	degreesOfFreedom := n1 + n2 - 2
+	pooledVariance := ((n1-1)*a.SampleVariance() + (n2-1)*b.SampleVariance()) / degreesOfFreedom
+	standardError := math.Sqrt(pooledVariance * (1/n1 + 1/n2))
+
+	return computeTTestResult(a.Mean()-b.Mean(), standardError, degreesOfFreedom, alternative, confidenceLevel)
+}
+
+// WelchTTest tests whether the means of the populations from which a and b are drawn differ, without assuming that
+// the populations have the same variance.  The degrees of freedom are computed using the Welch–Satterthwaite equation.
+func WelchTTest(a *StatisticalSampleSet, b *StatisticalSampleSet, alternative HypothesisAlternative, confidenceLevel float64) (*TTestResult, error) {
+	if err := errorIfHypothesisTestParametersAreNotValid(alternative, confidenceLevel); err != nil {
+		return nil, err
+	}
+
+	n1, n2 := float64(a.Count()), float64(b.Count())
+	if n1 < 2 || n2 < 2 {
+		return nil, fmt.Errorf("there must be at least two samples in each set")
+	}
+
+	squaredStandardError1 := a.SampleVariance() / n1
+	squaredStandardError2 := b.SampleVariance() / n2
+	squaredStandardError := squaredStandardError1 + squaredStandardError2
+
+	degreesOfFreedom := squaredStandardError * squaredStandardError /
+		(squaredStandardError1*squaredStandardError1/(n1-1) + squaredStandardError2*squaredStandardError2/(n2-1))
+
+	return computeTTestResult(a.Mean()-b.Mean(), math.Sqrt(squaredStandardError), degreesOfFreedom, alternative, confidenceLevel)
+}
+
+func errorIfAlternativeIsNotValid(alternative HypothesisAlternative) error {
+	if alternative < AlternativeTwoSided || alternative > AlternativeGreater {
+		return fmt.Errorf("alternative must be one of AlternativeTwoSided, AlternativeLess or AlternativeGreater")
+	}
+
+	return nil
+}
+
+func errorIfHypothesisTestParametersAreNotValid(alternative HypothesisAlternative, confidenceLevel float64) error {
+	if err := errorIfAlternativeIsNotValid(alternative); err != nil {
+		return err
+	}
+
+	return errorIfConfidenceLevelIsNotValid(confidenceLevel)
+}
+
+func computeTTestResult(meanDifference float64, standardError float64, degreesOfFreedom float64, alternative HypothesisAlternative, confidenceLevel float64) (*TTestResult, error) {
+	if standardError < 10*2.220446049250313e-16*math.Abs(meanDifference) || standardError == 0 {
+		return nil, fmt.Errorf("data are essentially constant")
+	}
+
+	t := meanDifference / standardError
+
+	result := &TTestResult{
+		TStatistic:       t,
+		DegreesOfFreedom: degreesOfFreedom,
+		Alternative:      alternative,
+		MeanDifference:   meanDifference,
+		StandardError:    standardError,
+		ConfidenceLevel:  confidenceLevel,
+	}
+
+	switch alternative {
+	case AlternativeTwoSided:
+		result.PValue = 2 * studentTCDF(-math.Abs(t), degreesOfFreedom)
+		margin := studentTQuantile((1+confidenceLevel)/2, degreesOfFreedom) * standardError
+		result.ConfidenceIntervalLower = meanDifference - margin
+		result.ConfidenceIntervalUpper = meanDifference + margin
+
+	case AlternativeLess:
+		result.PValue = studentTCDF(t, degreesOfFreedom)
+		result.ConfidenceIntervalLower = math.Inf(-1)
+		result.ConfidenceIntervalUpper = meanDifference + studentTQuantile(confidenceLevel, degreesOfFreedom)*standardError
+
+	case AlternativeGreater:
+		result.PValue = studentTCDF(-t, degreesOfFreedom)
+		result.ConfidenceIntervalLower = meanDifference - studentTQuantile(confidenceLevel, degreesOfFreedom)*standardError
+		result.ConfidenceIntervalUpper = math.Inf(1)
+	}
+
+	return result, nil
+}
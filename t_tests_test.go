@@ -0,0 +1,144 @@
+package stats_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+// the "sleep" data set from R, giving the increase in hours of sleep for ten patients under each of two drugs
+var sleepGroup1 = []float64{0.7, -1.6, -0.2, -1.2, -0.1, 3.4, 3.7, 0.8, 0.0, 2.0}
+var sleepGroup2 = []float64{1.9, 0.8, 1.1, 0.1, -0.1, 4.4, 5.5, 1.6, 4.6, 3.4}
+
+type tTestExpectation struct {
+	tStatistic              float64
+	degreesOfFreedom        float64
+	pValue                  float64
+	meanDifference          float64
+	confidenceIntervalLower float64
+	confidenceIntervalUpper float64
+}
+
+func (expected *tTestExpectation) compareTo(got *stats.TTestResult) error {
+	for _, comparison := range []struct {
+		name      string
+		expected  float64
+		got       float64
+		tolerance float64
+	}{
+		{"TStatistic", expected.tStatistic, got.TStatistic, 1e-4},
+		{"DegreesOfFreedom", expected.degreesOfFreedom, got.DegreesOfFreedom, 1e-3},
+		{"PValue", expected.pValue, got.PValue, 1e-5},
+		{"MeanDifference", expected.meanDifference, got.MeanDifference, 1e-9},
+		{"ConfidenceIntervalLower", expected.confidenceIntervalLower, got.ConfidenceIntervalLower, 1e-6},
+		{"ConfidenceIntervalUpper", expected.confidenceIntervalUpper, got.ConfidenceIntervalUpper, 1e-6},
+	} {
+		if math.IsInf(comparison.expected, 0) {
+			if comparison.got != comparison.expected {
+				return fmt.Errorf("expected %s (%f), got (%f)", comparison.name, comparison.expected, comparison.got)
+			}
+			continue
+		}
+
+		if math.Abs(comparison.expected-comparison.got) > comparison.tolerance {
+			return fmt.Errorf("expected %s (%f), got (%f)", comparison.name, comparison.expected, comparison.got)
+		}
+	}
+
+	return nil
+}
+
+func TestTTestsAgainstR(t *testing.T) {
+	group1, _ := stats.MakeStatisticalSampleSetFrom(sleepGroup1)
+	group2, _ := stats.MakeStatisticalSampleSetFrom(sleepGroup2)
+
+	for testIndex, testCase := range []struct {
+		run      func() (*stats.TTestResult, error)
+		expected *tTestExpectation
+	}{
+		{
+			// t.test(sleepGroup1, mu = 0)
+			run: func() (*stats.TTestResult, error) {
+				return stats.OneSampleTTest(group1, 0, stats.AlternativeTwoSided, 0.95)
+			},
+			expected: &tTestExpectation{1.325710, 9, 0.2175978, 0.75, -0.5297804, 2.0297804},
+		},
+		{
+			// t.test(sleepGroup1, sleepGroup2, paired = TRUE)
+			run: func() (*stats.TTestResult, error) {
+				return stats.PairedTTest(sleepGroup1, sleepGroup2, stats.AlternativeTwoSided, 0.95)
+			},
+			expected: &tTestExpectation{-4.062128, 9, 0.002832890, -1.58, -2.4598858, -0.7001142},
+		},
+		{
+			// t.test(sleepGroup1, sleepGroup2, var.equal = TRUE)
+			run: func() (*stats.TTestResult, error) {
+				return stats.StudentTTest(group1, group2, stats.AlternativeTwoSided, 0.95)
+			},
+			expected: &tTestExpectation{-1.860813, 18, 0.07918671, -1.58, -3.363874, 0.203874},
+		},
+		{
+			// t.test(sleepGroup1, sleepGroup2)
+			run: func() (*stats.TTestResult, error) {
+				return stats.WelchTTest(group1, group2, stats.AlternativeTwoSided, 0.95)
+			},
+			expected: &tTestExpectation{-1.860813, 17.77647, 0.07939414, -1.58, -3.3654832, 0.2054832},
+		},
+		{
+			// t.test(sleepGroup1, sleepGroup2, alternative = "less")
+			run: func() (*stats.TTestResult, error) {
+				return stats.WelchTTest(group1, group2, stats.AlternativeLess, 0.95)
+			},
+			expected: &tTestExpectation{-1.860813, 17.77647, 0.03969707, -1.58, math.Inf(-1), -0.1066185},
+		},
+		{
+			// t.test(sleepGroup1, sleepGroup2, alternative = "greater")
+			run: func() (*stats.TTestResult, error) {
+				return stats.WelchTTest(group1, group2, stats.AlternativeGreater, 0.95)
+			},
+			expected: &tTestExpectation{-1.860813, 17.77647, 0.9603029, -1.58, -3.0533815, math.Inf(1)},
+		},
+	} {
+		result, err := testCase.run()
+		if err != nil {
+			t.Errorf("on test with index (%d) got error: %s", testIndex, err.Error())
+			continue
+		}
+
+		if err := testCase.expected.compareTo(result); err != nil {
+			t.Errorf("on test with index (%d): %s", testIndex, err.Error())
+		}
+	}
+}
+
+func TestTTestErrors(t *testing.T) {
+	single, _ := stats.MakeStatisticalSampleSetFrom([]float64{1})
+	constant, _ := stats.MakeStatisticalSampleSetFrom([]float64{2, 2, 2})
+	group1, _ := stats.MakeStatisticalSampleSetFrom(sleepGroup1)
+
+	if _, err := stats.OneSampleTTest(single, 0, stats.AlternativeTwoSided, 0.95); err == nil {
+		t.Errorf("on OneSampleTTest() with a single sample, expected error, got none")
+	}
+
+	if _, err := stats.OneSampleTTest(constant, 0, stats.AlternativeTwoSided, 0.95); err == nil {
+		t.Errorf("on OneSampleTTest() with constant data, expected error, got none")
+	}
+
+	if _, err := stats.OneSampleTTest(group1, 0, stats.AlternativeTwoSided, 1); err == nil {
+		t.Errorf("on OneSampleTTest() with confidence level (1), expected error, got none")
+	}
+
+	if _, err := stats.WelchTTest(group1, single, stats.AlternativeTwoSided, 0.95); err == nil {
+		t.Errorf("on WelchTTest() with a single sample in one set, expected error, got none")
+	}
+
+	if _, err := stats.StudentTTest(group1, group1, stats.HypothesisAlternative(5), 0.95); err == nil {
+		t.Errorf("on StudentTTest() with invalid alternative, expected error, got none")
+	}
+
+	if _, err := stats.PairedTTest([]float64{1, 2, 3}, []float64{1, 2}, stats.AlternativeTwoSided, 0.95); err == nil {
+		t.Errorf("on PairedTTest() with samples of different lengths, expected error, got none")
+	}
+}
@@ -0,0 +1,349 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// A TDigest is a merging t-digest, as described by Dunning and Ertl in "Computing Extremely Accurate Quantiles
+// Using t-Digests".  It estimates quantiles of a stream of values using memory bounded by the compression, rather
+// than the number of values added.  Quantiles near 0 and 1 are estimated more accurately than those near the median.
+type TDigest struct {
+	mutex                     sync.Mutex
+	compression               float64
+	mergedCentroids           []tDigestCentroid
+	unmergedCentroids         []tDigestCentroid
+	maximumUnmergedCentroids  int
+	totalWeightOfAllCentroids float64
+	countOfValuesAdded        int
+	sumOfAllValuesAdded       float64
+	minimumValueAdded         float64
+	maximumValueAdded         float64
+}
+
+type tDigestCentroid struct {
+	mean   float64
+	weight float64
+}
+
+const DefaultTDigestCompression = 100
+
+// NewTDigest creates an empty TDigest.  Larger compression values yield more accurate quantiles at the cost of
+// memory; the digest retains on the order of compression centroids.  The compression must be at least 20.
+func NewTDigest(compression float64) (*TDigest, error) {
+	if !(compression >= 20) {
+		return nil, fmt.Errorf("compression must be at least 20")
+	}
+
+	return &TDigest{
+		compression:              compression,
+		maximumUnmergedCentroids: int(5 * compression),
+		minimumValueAdded:        math.Inf(1),
+		maximumValueAdded:        math.Inf(-1),
+	}, nil
+}
+
+// Add adds a value to the digest.  NaN values are ignored.
+func (digest *TDigest) Add(value float64) {
+	if math.IsNaN(value) {
+		return
+	}
+
+	digest.mutex.Lock()
+	defer digest.mutex.Unlock()
+
+	digest.addCentroid(tDigestCentroid{mean: value, weight: 1})
+	digest.countOfValuesAdded++
+	digest.sumOfAllValuesAdded += value
+}
+
+// AddMany adds each of the provided values to the digest.
+func (digest *TDigest) AddMany(values []float64) {
+	for _, v := range values {
+		digest.Add(v)
+	}
+}
+
+// Merge adds all of the values summarized by another digest to this one.  The other digest is unchanged.
+func (digest *TDigest) Merge(other *TDigest) {
+	other.mutex.Lock()
+	other.mergeUnmergedCentroids()
+	centroidsOfOther := make([]tDigestCentroid, len(other.mergedCentroids))
+	copy(centroidsOfOther, other.mergedCentroids)
+	countOfOther, sumOfOther := other.countOfValuesAdded, other.sumOfAllValuesAdded
+	minimumOfOther, maximumOfOther := other.minimumValueAdded, other.maximumValueAdded
+	other.mutex.Unlock()
+
+	digest.mutex.Lock()
+	defer digest.mutex.Unlock()
+
+	for _, centroid := range centroidsOfOther {
+		digest.addCentroid(centroid)
+	}
+
+	digest.countOfValuesAdded += countOfOther
+	digest.sumOfAllValuesAdded += sumOfOther
+	digest.minimumValueAdded = math.Min(digest.minimumValueAdded, minimumOfOther)
+	digest.maximumValueAdded = math.Max(digest.maximumValueAdded, maximumOfOther)
+}
+
+// Count returns the number of values added to the digest.
+func (digest *TDigest) Count() int {
+	digest.mutex.Lock()
+	defer digest.mutex.Unlock()
+
+	return digest.countOfValuesAdded
+}
+
+// Compression returns the compression with which the digest was created.
+func (digest *TDigest) Compression() float64 {
+	return digest.compression
+}
+
+// Quantile returns the estimated value at quantile q (in the range 0..1).  It returns NaN if the digest is empty or
+// q is outside of the range 0..1.
+func (digest *TDigest) Quantile(q float64) float64 {
+	digest.mutex.Lock()
+	defer digest.mutex.Unlock()
+
+	digest.mergeUnmergedCentroids()
+
+	return quantileOfTDigestCentroids(digest.mergedCentroids, digest.totalWeightOfAllCentroids, digest.minimumValueAdded, digest.maximumValueAdded, q)
+}
+
+// CDF returns the estimated fraction of values in the digest that are less than or equal to x.  It returns NaN if
+// the digest is empty.
+func (digest *TDigest) CDF(x float64) float64 {
+	digest.mutex.Lock()
+	defer digest.mutex.Unlock()
+
+	digest.mergeUnmergedCentroids()
+
+	return cdfOfTDigestCentroids(digest.mergedCentroids, digest.totalWeightOfAllCentroids, digest.minimumValueAdded, digest.maximumValueAdded, x)
+}
+
+// Summary returns a read-only snapshot of the digest.  Values subsequently added to the digest do not change
+// the summary.  An error is returned if no values have been added to the digest.
+func (digest *TDigest) Summary() (*TDigestSummary, error) {
+	digest.mutex.Lock()
+	defer digest.mutex.Unlock()
+
+	if digest.countOfValuesAdded == 0 {
+		return nil, fmt.Errorf("there must be at least one value in the digest")
+	}
+
+	digest.mergeUnmergedCentroids()
+
+	centroids := make([]tDigestCentroid, len(digest.mergedCentroids))
+	copy(centroids, digest.mergedCentroids)
+
+	return &TDigestSummary{
+		centroids:                 centroids,
+		totalWeightOfAllCentroids: digest.totalWeightOfAllCentroids,
+		countOfValues:             digest.countOfValuesAdded,
+		sumOfAllValues:            digest.sumOfAllValuesAdded,
+		minimumValue:              digest.minimumValueAdded,
+		maximumValue:              digest.maximumValueAdded,
+	}, nil
+}
+
+func (digest *TDigest) addCentroid(centroid tDigestCentroid) {
+	digest.unmergedCentroids = append(digest.unmergedCentroids, centroid)
+	digest.totalWeightOfAllCentroids += centroid.weight
+
+	if centroid.mean < digest.minimumValueAdded {
+		digest.minimumValueAdded = centroid.mean
+	}
+	if centroid.mean > digest.maximumValueAdded {
+		digest.maximumValueAdded = centroid.mean
+	}
+
+	if len(digest.unmergedCentroids) >= digest.maximumUnmergedCentroids {
+		digest.mergeUnmergedCentroids()
+	}
+}
+
+// mergeUnmergedCentroids combines the unmerged centroids with the merged centroids, using the k1 scale function
+// (k(q) = compression / 2π * asin(2q - 1)) to limit the weight of each resulting centroid.
+func (digest *TDigest) mergeUnmergedCentroids() {
+	if len(digest.unmergedCentroids) == 0 {
+		return
+	}
+
+	allCentroids := append(digest.unmergedCentroids, digest.mergedCentroids...)
+	sort.Slice(allCentroids, func(i, j int) bool { return allCentroids[i].mean < allCentroids[j].mean })
+
+	totalWeight := digest.totalWeightOfAllCentroids
+	mergedCentroids := make([]tDigestCentroid, 0, len(digest.mergedCentroids)+1)
+
+	current := allCentroids[0]
+	weightBeforeCurrent := 0.0
+	quantileLimit := digest.inverseScaleFunction(digest.scaleFunction(0) + 1)
+
+	for _, next := range allCentroids[1:] {
+		if (weightBeforeCurrent+current.weight+next.weight)/totalWeight <= quantileLimit {
+			current.mean += (next.mean - current.mean) * next.weight / (current.weight + next.weight)
+			current.weight += next.weight
+			continue
+		}
+
+		mergedCentroids = append(mergedCentroids, current)
+		weightBeforeCurrent += current.weight
+		quantileLimit = digest.inverseScaleFunction(digest.scaleFunction(weightBeforeCurrent/totalWeight) + 1)
+		current = next
+	}
+
+	digest.mergedCentroids = append(mergedCentroids, current)
+	digest.unmergedCentroids = digest.unmergedCentroids[:0]
+}
+
+func (digest *TDigest) scaleFunction(q float64) float64 {
+	return digest.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+func (digest *TDigest) inverseScaleFunction(k float64) float64 {
+	if k >= digest.compression/4 {
+		return 1
+	}
+
+	return (math.Sin(k*2*math.Pi/digest.compression) + 1) / 2
+}
+
+// A TDigestSummary is a read-only snapshot of a TDigest.  It provides the same summary statistics as a
+// StatisticalSampleSet, computed approximately from the digest.
+type TDigestSummary struct {
+	centroids                 []tDigestCentroid
+	totalWeightOfAllCentroids float64
+	countOfValues             int
+	sumOfAllValues            float64
+	minimumValue              float64
+	maximumValue              float64
+}
+
+func (summary *TDigestSummary) Count() int {
+	return summary.countOfValues
+}
+
+func (summary *TDigestSummary) Minimum() float64 {
+	return summary.minimumValue
+}
+
+func (summary *TDigestSummary) Maximum() float64 {
+	return summary.maximumValue
+}
+
+func (summary *TDigestSummary) Mean() float64 {
+	return summary.sumOfAllValues / float64(summary.countOfValues)
+}
+
+func (summary *TDigestSummary) Median() float64 {
+	return summary.Quantile(0.5)
+}
+
+// ValueNearestPercentile returns the estimated value at the percentile.  It panics if the percentile is not in
+// the range 0..100.
+func (summary *TDigestSummary) ValueNearestPercentile(percentile int) float64 {
+	if percentile < 0 || percentile > 100 {
+		panic("percentile must be in the range 0..100")
+	}
+
+	return summary.Quantile(float64(percentile) / 100)
+}
+
+// Quantile returns the estimated value at quantile q (in the range 0..1).  It returns NaN if q is outside of
+// the range 0..1.
+func (summary *TDigestSummary) Quantile(q float64) float64 {
+	return quantileOfTDigestCentroids(summary.centroids, summary.totalWeightOfAllCentroids, summary.minimumValue, summary.maximumValue, q)
+}
+
+// CDF returns the estimated fraction of values that are less than or equal to x.
+func (summary *TDigestSummary) CDF(x float64) float64 {
+	return cdfOfTDigestCentroids(summary.centroids, summary.totalWeightOfAllCentroids, summary.minimumValue, summary.maximumValue, x)
+}
+
+// quantileOfTDigestCentroids interpolates between the centroid means, treating half of the weight of each
+// centroid as lying on either side of its mean.  The minimum and maximum anchor the tails.
+func quantileOfTDigestCentroids(centroids []tDigestCentroid, totalWeight float64, minimum float64, maximum float64, q float64) float64 {
+	if len(centroids) == 0 || !(q >= 0 && q <= 1) {
+		return math.NaN()
+	}
+
+	if len(centroids) == 1 || q == 0 {
+		if q == 0 {
+			return minimum
+		}
+		return interpolateTDigestQuantile(minimum, maximum, q)
+	}
+
+	if q == 1 {
+		return maximum
+	}
+
+	targetWeight := q * totalWeight
+
+	firstCentroid := centroids[0]
+	if targetWeight < firstCentroid.weight/2 {
+		return interpolateTDigestQuantile(minimum, firstCentroid.mean, targetWeight/(firstCentroid.weight/2))
+	}
+
+	weightSoFar := firstCentroid.weight / 2
+	for i := 0; i < len(centroids)-1; i++ {
+		weightBetweenCentroids := (centroids[i].weight + centroids[i+1].weight) / 2
+		if weightSoFar+weightBetweenCentroids > targetWeight {
+			return interpolateTDigestQuantile(centroids[i].mean, centroids[i+1].mean, (targetWeight-weightSoFar)/weightBetweenCentroids)
+		}
+		weightSoFar += weightBetweenCentroids
+	}
+
+	lastCentroid := centroids[len(centroids)-1]
+
+	return interpolateTDigestQuantile(lastCentroid.mean, maximum, (targetWeight-weightSoFar)/(lastCentroid.weight/2))
+}
+
+func interpolateTDigestQuantile(from float64, to float64, fraction float64) float64 {
+	if fraction <= 0 {
+		return from
+	}
+	if fraction >= 1 {
+		return to
+	}
+
+	return from + (to-from)*fraction
+}
+
+func cdfOfTDigestCentroids(centroids []tDigestCentroid, totalWeight float64, minimum float64, maximum float64, x float64) float64 {
+	if len(centroids) == 0 || math.IsNaN(x) {
+		return math.NaN()
+	}
+
+	if x < minimum {
+		return 0
+	}
+	if x >= maximum {
+		return 1
+	}
+
+	if len(centroids) == 1 {
+		return (x - minimum) / (maximum - minimum)
+	}
+
+	firstCentroid := centroids[0]
+	if x < firstCentroid.mean {
+		return (x - minimum) / (firstCentroid.mean - minimum) * (firstCentroid.weight / 2) / totalWeight
+	}
+
+	weightSoFar := firstCentroid.weight / 2
+	for i := 0; i < len(centroids)-1; i++ {
+		weightBetweenCentroids := (centroids[i].weight + centroids[i+1].weight) / 2
+		if x < centroids[i+1].mean {
+			return (weightSoFar + (x-centroids[i].mean)/(centroids[i+1].mean-centroids[i].mean)*weightBetweenCentroids) / totalWeight
+		}
+		weightSoFar += weightBetweenCentroids
+	}
+
+	lastCentroid := centroids[len(centroids)-1]
+
+	return (weightSoFar + (x-lastCentroid.mean)/(maximum-lastCentroid.mean)*(lastCentroid.weight/2)) / totalWeight
+}
@@ -0,0 +1,139 @@
+package stats_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+func TestTDigestQuantileAccuracy(t *testing.T) {
+	digest, err := stats.NewTDigest(stats.DefaultTDigestCompression)
+	if err != nil {
+		t.Fatalf("on NewTDigest() got error: %s", err.Error())
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	values := make([]float64, 100000)
+	for i := range values {
+		values[i] = float64(i + 1)
+	}
+	rng.Shuffle(len(values), func(i, j int) { values[i], values[j] = values[j], values[i] })
+
+	digest.AddMany(values)
+
+	if digest.Count() != len(values) {
+		t.Errorf("expected Count (%d), got (%d)", len(values), digest.Count())
+	}
+
+	for _, testCase := range []struct {
+		q                float64
+		allowedDeviation float64
+	}{
+		{0, 0},
+		{0.001, 50},
+		{0.01, 50},
+		{0.25, 500},
+		{0.5, 500},
+		{0.75, 500},
+		{0.99, 50},
+		{0.999, 50},
+		{1, 0},
+	} {
+		expected := 1 + testCase.q*float64(len(values)-1)
+		got := digest.Quantile(testCase.q)
+		if math.Abs(got-expected) > testCase.allowedDeviation {
+			t.Errorf("for quantile (%f) expected value within (%f) of (%f), got (%f)", testCase.q, testCase.allowedDeviation, expected, got)
+		}
+
+		cdf := digest.CDF(expected)
+		if math.Abs(cdf-testCase.q) > 0.01 {
+			t.Errorf("for CDF(%f) expected value near (%f), got (%f)", expected, testCase.q, cdf)
+		}
+	}
+}
+
+func TestTDigestMerge(t *testing.T) {
+	first, _ := stats.NewTDigest(200)
+	second, _ := stats.NewTDigest(200)
+
+	for i := 1; i <= 5000; i++ {
+		first.Add(float64(i))
+		second.Add(float64(i + 5000))
+	}
+
+	first.Merge(second)
+
+	if first.Count() != 10000 {
+		t.Errorf("expected Count (10000) after Merge, got (%d)", first.Count())
+	}
+
+	if second.Count() != 5000 {
+		t.Errorf("expected Count of merged digest to remain (5000), got (%d)", second.Count())
+	}
+
+	summary, err := first.Summary()
+	if err != nil {
+		t.Fatalf("on Summary() got error: %s", err.Error())
+	}
+
+	if summary.Minimum() != 1 || summary.Maximum() != 10000 {
+		t.Errorf("expected Minimum (1) and Maximum (10000), got (%f) and (%f)", summary.Minimum(), summary.Maximum())
+	}
+
+	if summary.Mean() != 5000.5 {
+		t.Errorf("expected Mean (5000.5), got (%f)", summary.Mean())
+	}
+
+	if math.Abs(summary.Median()-5000.5) > 50 {
+		t.Errorf("expected Median near (5000.5), got (%f)", summary.Median())
+	}
+
+	if math.Abs(summary.ValueNearestPercentile(90)-9000) > 50 {
+		t.Errorf("expected ValueNearestPercentile(90) near (9000), got (%f)", summary.ValueNearestPercentile(90))
+	}
+
+	first.Add(1000000)
+	if summary.Maximum() != 10000 {
+		t.Errorf("expected Summary to be unchanged by later Add(), but Maximum is (%f)", summary.Maximum())
+	}
+}
+
+func TestTDigestSummaryMatchesStatisticalSampleSet(t *testing.T) {
+	values := []float64{3, 1, 4, 1, 5, 9, 2, 6, 5, 3, 5}
+
+	digest, _ := stats.NewTDigest(stats.DefaultTDigestCompression)
+	digest.AddMany(values)
+
+	approximate, err := digest.Summary()
+	if err != nil {
+		t.Fatalf("on Summary() got error: %s", err.Error())
+	}
+
+	exact, _ := stats.MakeStatisticalSampleSetFrom(values)
+
+	for _, summary := range []stats.SampleSetSummary{exact, approximate} {
+		if summary.Count() != len(values) || summary.Minimum() != 1 || summary.Maximum() != 9 {
+			t.Errorf("for summary of type (%T) expected Count (%d), Minimum (1) and Maximum (9), got (%d), (%f) and (%f)", summary, len(values), summary.Count(), summary.Minimum(), summary.Maximum())
+		}
+		if math.Abs(summary.Median()-4) > 0.5 {
+			t.Errorf("for summary of type (%T) expected Median near (4), got (%f)", summary, summary.Median())
+		}
+	}
+}
+
+func TestTDigestErrors(t *testing.T) {
+	if _, err := stats.NewTDigest(10); err == nil {
+		t.Errorf("on NewTDigest() with compression (10), expected error, got none")
+	}
+
+	digest, _ := stats.NewTDigest(stats.DefaultTDigestCompression)
+	if _, err := digest.Summary(); err == nil {
+		t.Errorf("on Summary() of empty digest, expected error, got none")
+	}
+
+	if !math.IsNaN(digest.Quantile(0.5)) {
+		t.Errorf("on Quantile() of empty digest, expected NaN, got (%f)", digest.Quantile(0.5))
+	}
+}
@@ -0,0 +1,200 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// A WeightedSampleSet is a set of samples, each of which carries a non-negative weight.  Weights may be frequency
+// weights (the number of times a value was observed), reliability weights (e.g., inverse variances) or sampling
+// and design weights.  The weighted variance is available under either the frequency or the reliability
+// interpretation.
+type WeightedSampleSet struct {
+	valuesSortedInAscendingOrder []float64
+	weightsOfSortedValues        []float64
+	sumOfAllWeights              float64
+	sumOfAllSquaredWeights       float64
+	sumOfAllWeightedValues       float64
+	distributionTracker          *weightedValueDistributionTracker
+	varianceTracker              *weightedVarianceTracker
+}
+
+// MakeWeightedSampleSetFrom creates a set in which samples[i] has weight weights[i].  There must be the same
+// number of samples and weights, weights must be finite and non-negative, and at least one weight must be
+// greater than zero.  Samples with a weight of zero are ignored.
+func MakeWeightedSampleSetFrom(samples []float64, weights []float64) (*WeightedSampleSet, error) {
+	if len(samples) != len(weights) {
+		return nil, fmt.Errorf("there must be the same number of samples and weights")
+	}
+
+	indexesOfSamplesWithWeight := make([]int, 0, len(samples))
+	for i, w := range weights {
+		if !(w >= 0) || math.IsInf(w, 1) {
+			return nil, fmt.Errorf("weights must be finite and non-negative")
+		}
+
+		if w > 0 {
+			indexesOfSamplesWithWeight = append(indexesOfSamplesWithWeight, i)
+		}
+	}
+
+	if len(indexesOfSamplesWithWeight) == 0 {
+		return nil, fmt.Errorf("there must be at least one sample with a weight greater than zero")
+	}
+
+	sort.SliceStable(indexesOfSamplesWithWeight, func(i, j int) bool {
+		return samples[indexesOfSamplesWithWeight[i]] < samples[indexesOfSamplesWithWeight[j]]
+	})
+
+	sortedValues := make([]float64, len(indexesOfSamplesWithWeight))
+	weightsOfSortedValues := make([]float64, len(indexesOfSamplesWithWeight))
+
+	sumOfWeights, sumOfSquaredWeights, sumOfWeightedValues := float64(0), float64(0), float64(0)
+	for i, indexOfSample := range indexesOfSamplesWithWeight {
+		v, w := samples[indexOfSample], weights[indexOfSample]
+
+		sortedValues[i] = v
+		weightsOfSortedValues[i] = w
+
+		sumOfWeights += w
+		sumOfSquaredWeights += w * w
+		sumOfWeightedValues += w * v
+	}
+
+	if sumOfWeightedValues == math.Inf(1) || sumOfWeights == math.Inf(1) {
+		return nil, ErrorFloat64Overflow
+	}
+
+	if sumOfWeightedValues == math.Inf(-1) {
+		return nil, ErrorFloat64Underflow
+	}
+
+	return &WeightedSampleSet{
+		valuesSortedInAscendingOrder: sortedValues,
+		weightsOfSortedValues:        weightsOfSortedValues,
+		sumOfAllWeights:              sumOfWeights,
+		sumOfAllSquaredWeights:       sumOfSquaredWeights,
+		sumOfAllWeightedValues:       sumOfWeightedValues,
+		distributionTracker:          newWeightedValueDistributionTracker(sortedValues, weightsOfSortedValues),
+		varianceTracker:              newWeightedVarianceTracker(sortedValues, weightsOfSortedValues, sumOfWeightedValues/sumOfWeights),
+	}, nil
+}
+
+// Count returns the number of samples in the set with a weight greater than zero.
+func (set *WeightedSampleSet) Count() int {
+	return len(set.valuesSortedInAscendingOrder)
+}
+
+func (set *WeightedSampleSet) SumOfWeights() float64 {
+	return set.sumOfAllWeights
+}
+
+func (set *WeightedSampleSet) Minimum() float64 {
+	return set.valuesSortedInAscendingOrder[0]
+}
+
+func (set *WeightedSampleSet) Maximum() float64 {
+	return set.valuesSortedInAscendingOrder[len(set.valuesSortedInAscendingOrder)-1]
+}
+
+func (set *WeightedSampleSet) Range() float64 {
+	return set.Maximum() - set.Minimum()
+}
+
+func (set *WeightedSampleSet) Mean() float64 {
+	return set.sumOfAllWeightedValues / set.sumOfAllWeights
+}
+
+// FrequencyWeightedVariance treats each weight as the number of times its value was observed, so that the sum of
+// the weights is the sample size.  It is the weighted sum of squared deviations divided by (sum of weights - 1).
+func (set *WeightedSampleSet) FrequencyWeightedVariance() float64 {
+	return set.varianceTracker.Variance() / (set.sumOfAllWeights - 1)
+}
+
+// ReliabilityWeightedVariance treats weights as measures of the relative reliability of each value.  It is the
+// weighted sum of squared deviations divided by (V1 - V2/V1), where V1 is the sum of the weights and V2 the sum of
+// the squared weights.
+func (set *WeightedSampleSet) ReliabilityWeightedVariance() float64 {
+	return set.varianceTracker.Variance() / (set.sumOfAllWeights - set.sumOfAllSquaredWeights/set.sumOfAllWeights)
+}
+
+// PopulationVariance is the weighted sum of squared deviations divided by the sum of the weights.
+func (set *WeightedSampleSet) PopulationVariance() float64 {
+	return set.varianceTracker.Variance() / set.sumOfAllWeights
+}
+
+func (set *WeightedSampleSet) FrequencyWeightedStdev() float64 {
+	return math.Sqrt(set.FrequencyWeightedVariance())
+}
+
+func (set *WeightedSampleSet) ReliabilityWeightedStdev() float64 {
+	return math.Sqrt(set.ReliabilityWeightedVariance())
+}
+
+func (set *WeightedSampleSet) PopulationStdev() float64 {
+	return math.Sqrt(set.PopulationVariance())
+}
+
+// Median returns the weighted median.  When every weight is the same, this is the same as the unweighted median.
+func (set *WeightedSampleSet) Median() float64 {
+	return set.weightedQuantile(0.5)
+}
+
+// QuantileWithErrors returns the weighted quantile q (in the range 0..1).  This is the smallest value at which the
+// cumulative weight reaches q times the sum of the weights, averaged with the next value when the cumulative
+// weight is exactly equal to that target.  When every weight is the same, this is QuantileType2.
+func (set *WeightedSampleSet) QuantileWithErrors(q float64) (float64, error) {
+	if err := errorIfQuantileIsNotValid(q); err != nil {
+		return 0, err
+	}
+
+	return set.weightedQuantile(q), nil
+}
+
+// Quantile is the same as QuantileWithErrors, except that it panics on an error.
+func (set *WeightedSampleSet) Quantile(q float64) float64 {
+	f, err := set.QuantileWithErrors(q)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return f
+}
+
+func (set *WeightedSampleSet) weightedQuantile(q float64) float64 {
+	targetWeight := q * set.sumOfAllWeights
+	tolerance := 1e-12 * set.sumOfAllWeights
+
+	lastIndex := len(set.valuesSortedInAscendingOrder) - 1
+	cumulativeWeight := float64(0)
+
+	for i, w := range set.weightsOfSortedValues {
+		cumulativeWeight += w
+
+		if math.Abs(cumulativeWeight-targetWeight) <= tolerance && i < lastIndex {
+			return (set.valuesSortedInAscendingOrder[i] + set.valuesSortedInAscendingOrder[i+1]) / 2
+		}
+
+		if cumulativeWeight > targetWeight {
+			return set.valuesSortedInAscendingOrder[i]
+		}
+	}
+
+	return set.valuesSortedInAscendingOrder[lastIndex]
+}
+
+// Mode returns the largest total weight of any distinct value, and the values whose weights sum to that total.
+func (set *WeightedSampleSet) Mode() (modeTotalWeight float64, valuesWithThatTotalWeight []float64) {
+	for value, totalWeight := range set.distributionTracker.Map() {
+		switch {
+		case totalWeight > modeTotalWeight:
+			modeTotalWeight = totalWeight
+			valuesWithThatTotalWeight = []float64{value}
+		case totalWeight == modeTotalWeight:
+			valuesWithThatTotalWeight = append(valuesWithThatTotalWeight, value)
+		}
+	}
+
+	return modeTotalWeight, valuesWithThatTotalWeight
+}
@@ -0,0 +1,156 @@
+package stats_test
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+
+	stats "github.com/blorticus-go/statistics"
+)
+
+type weightedSampleSetTestCase struct {
+	floatSet                         []float64
+	weightSet                        []float64
+	expandedFloatSet                 []float64
+	quantiles                        []float64
+	expectedModeTotalWeight          float64
+	expectedModeValuesWithThatWeight []float64
+}
+
+// RunTest compares a set with integer frequency weights to the set in which each value is repeated
+// the number of times given by its weight.
+func (testCase *weightedSampleSetTestCase) RunTest() error {
+	weighted, err := stats.MakeWeightedSampleSetFrom(testCase.floatSet, testCase.weightSet)
+	if err != nil {
+		return fmt.Errorf("on MakeWeightedSampleSetFrom() got error: %s", err.Error())
+	}
+
+	expanded, err := stats.MakeStatisticalSampleSetFrom(testCase.expandedFloatSet)
+	if err != nil {
+		return fmt.Errorf("on MakeStatisticalSampleSetFrom() got error: %s", err.Error())
+	}
+
+	for _, comparison := range []struct {
+		name     string
+		expected float64
+		got      float64
+	}{
+		{"Minimum", expanded.Minimum(), weighted.Minimum()},
+		{"Maximum", expanded.Maximum(), weighted.Maximum()},
+		{"Range", expanded.Range(), weighted.Range()},
+		{"SumOfWeights", float64(expanded.Count()), weighted.SumOfWeights()},
+		{"Mean", expanded.Mean(), weighted.Mean()},
+		{"Median", expanded.Median(), weighted.Median()},
+		{"FrequencyWeightedVariance", expanded.SampleVariance(), weighted.FrequencyWeightedVariance()},
+		{"PopulationVariance", expanded.PopulationVariance(), weighted.PopulationVariance()},
+		{"FrequencyWeightedStdev", expanded.SampleStdev(), weighted.FrequencyWeightedStdev()},
+	} {
+		if math.Abs(comparison.expected-comparison.got) > 1e-9 {
+			return fmt.Errorf("expected %s (%f), got (%f)", comparison.name, comparison.expected, comparison.got)
+		}
+	}
+
+	for _, q := range testCase.quantiles {
+		expected := expanded.Quantile(q, stats.QuantileType2)
+		got := weighted.Quantile(q)
+		if expected != got {
+			return fmt.Errorf("for quantile (%f) expected (%f), got (%f)", q, expected, got)
+		}
+	}
+
+	modeTotalWeight, modeValues := weighted.Mode()
+	if modeTotalWeight != testCase.expectedModeTotalWeight {
+		return fmt.Errorf("expected mode total weight (%f), got (%f)", testCase.expectedModeTotalWeight, modeTotalWeight)
+	}
+
+	sort.Float64s(modeValues)
+	if fmt.Sprint(modeValues) != fmt.Sprint(testCase.expectedModeValuesWithThatWeight) {
+		return fmt.Errorf("expected mode values (%v), got (%v)", testCase.expectedModeValuesWithThatWeight, modeValues)
+	}
+
+	return nil
+}
+
+func TestWeightedSampleSetWithFrequencyWeights(t *testing.T) {
+	for testIndex, testCase := range []*weightedSampleSetTestCase{
+		{
+			floatSet:                         []float64{4, 1, 3, 2},
+			weightSet:                        []float64{3, 1, 1, 2},
+			expandedFloatSet:                 []float64{1, 2, 2, 3, 4, 4, 4},
+			quantiles:                        []float64{0, 0.25, 0.5, 0.75, 0.9, 1},
+			expectedModeTotalWeight:          3,
+			expectedModeValuesWithThatWeight: []float64{4},
+		},
+		{
+			floatSet:                         []float64{10, 20, 10, 30, 40},
+			weightSet:                        []float64{1, 2, 1, 4, 0},
+			expandedFloatSet:                 []float64{10, 10, 20, 20, 30, 30, 30, 30},
+			quantiles:                        []float64{0, 0.25, 0.5, 0.75, 1},
+			expectedModeTotalWeight:          4,
+			expectedModeValuesWithThatWeight: []float64{30},
+		},
+		{
+			floatSet:                         []float64{-1.5, 2.5, 6},
+			weightSet:                        []float64{2, 2, 1},
+			expandedFloatSet:                 []float64{-1.5, -1.5, 2.5, 2.5, 6},
+			quantiles:                        []float64{0.1, 0.4, 0.5, 0.8},
+			expectedModeTotalWeight:          2,
+			expectedModeValuesWithThatWeight: []float64{-1.5, 2.5},
+		},
+	} {
+		if err := testCase.RunTest(); err != nil {
+			t.Errorf("on test with index (%d): %s", testIndex, err.Error())
+		}
+	}
+}
+
+func TestWeightedSampleSetWithReliabilityWeights(t *testing.T) {
+	floatSet := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+
+	unweighted, _ := stats.MakeStatisticalSampleSetFrom(floatSet)
+	weighted, err := stats.MakeWeightedSampleSetFrom(floatSet, []float64{0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5, 0.5})
+	if err != nil {
+		t.Fatalf("on MakeWeightedSampleSetFrom() got error: %s", err.Error())
+	}
+
+	if math.Abs(weighted.ReliabilityWeightedVariance()-unweighted.SampleVariance()) > 1e-12 {
+		t.Errorf("with equal weights expected ReliabilityWeightedVariance (%f), got (%f)", unweighted.SampleVariance(), weighted.ReliabilityWeightedVariance())
+	}
+
+	weighted, _ = stats.MakeWeightedSampleSetFrom([]float64{1, 2, 3}, []float64{1, 2, 3})
+
+	// mean = 14 / 6; S = sum w (x - mean)^2 = 20 / 6; V1 = 6; V2 = 14
+	expectedVariance := (20.0 / 6.0) / (6.0 - 14.0/6.0)
+	if math.Abs(weighted.ReliabilityWeightedVariance()-expectedVariance) > 1e-12 {
+		t.Errorf("expected ReliabilityWeightedVariance (%f), got (%f)", expectedVariance, weighted.ReliabilityWeightedVariance())
+	}
+
+	if math.Abs(weighted.ReliabilityWeightedStdev()-math.Sqrt(expectedVariance)) > 1e-12 {
+		t.Errorf("expected ReliabilityWeightedStdev (%f), got (%f)", math.Sqrt(expectedVariance), weighted.ReliabilityWeightedStdev())
+	}
+}
+
+func TestMakeWeightedSampleSetFromErrors(t *testing.T) {
+	for testIndex, testCase := range []struct {
+		floatSet  []float64
+		weightSet []float64
+	}{
+		{[]float64{}, []float64{}},
+		{[]float64{1, 2}, []float64{1}},
+		{[]float64{1, 2}, []float64{1, -1}},
+		{[]float64{1, 2}, []float64{0, 0}},
+		{[]float64{1, 2}, []float64{1, math.NaN()}},
+		{[]float64{1, 2}, []float64{1, math.Inf(1)}},
+		{[]float64{math.MaxFloat64, math.MaxFloat64}, []float64{1, 1}},
+	} {
+		if _, err := stats.MakeWeightedSampleSetFrom(testCase.floatSet, testCase.weightSet); err == nil {
+			t.Errorf("on test with index (%d) expected error, got none", testIndex)
+		}
+	}
+
+	weighted, _ := stats.MakeWeightedSampleSetFrom([]float64{1}, []float64{1})
+	if _, err := weighted.QuantileWithErrors(1.5); err == nil {
+		t.Errorf("on QuantileWithErrors(1.5) expected error, got none")
+	}
+}